@@ -3,18 +3,25 @@ package bucket
 import (
 	"bytes"
 	"context"
+	"io"
 	"time"
 
 	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/internal/metrics"
+	"github.com/isd-sgcu/johnjud-backend/internal/tracing"
 	"github.com/minio/minio-go/v7"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Client interface {
-	Upload([]byte, string) (string, string, error)
-	Delete(string) error
-	DeleteMany([]string) error
+	Upload(context.Context, []byte, string) (string, string, error)
+	Delete(context.Context, string) error
+	DeleteMany(context.Context, []string) error
+	Download(context.Context, string) ([]byte, error)
+	Ping(context.Context) error
 }
 
 type clientImpl struct {
@@ -26,17 +33,21 @@ func NewClient(conf config.Bucket, minioClient *minio.Client) Client {
 	return &clientImpl{conf: conf, minio: minioClient}
 }
 
-func (c *clientImpl) Upload(file []byte, objectKey string) (string, string, error) {
-	ctx := context.Background()
-	_, cancel := context.WithTimeout(ctx, 50*time.Second)
+func (c *clientImpl) Upload(ctx context.Context, file []byte, objectKey string) (_ string, _ string, err error) {
+	ctx, span := c.startSpan(ctx, "bucket.Upload", attribute.String("bucket.object_key", objectKey), attribute.Int("bucket.bytes", len(file)))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
 	defer cancel()
 
 	buffer := bytes.NewReader(file)
 
-	uploadOutput, err := c.minio.PutObject(context.Background(), c.conf.BucketName, objectKey, buffer,
+	uploadOutput, err := c.minio.PutObject(ctx, c.conf.BucketName, objectKey, buffer,
 		buffer.Size(), minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	metrics.ObserveBucket("upload", len(file), err)
 	if err != nil {
 		log.Error().
+			Ctx(ctx).
 			Err(err).
 			Str("service", "file").
 			Str("module", "bucket client").
@@ -48,17 +59,21 @@ func (c *clientImpl) Upload(file []byte, objectKey string) (string, string, erro
 	return c.getURL(objectKey), uploadOutput.Key, nil
 }
 
-func (c *clientImpl) Delete(objectKey string) error {
-	ctx := context.Background()
-	_, cancel := context.WithTimeout(ctx, 50*time.Second)
+func (c *clientImpl) Delete(ctx context.Context, objectKey string) (err error) {
+	ctx, span := c.startSpan(ctx, "bucket.Delete", attribute.String("bucket.object_key", objectKey))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
 	defer cancel()
 
 	opts := minio.RemoveObjectOptions{
 		GovernanceBypass: true,
 	}
-	err := c.minio.RemoveObject(context.Background(), c.conf.BucketName, objectKey, opts)
+	err = c.minio.RemoveObject(ctx, c.conf.BucketName, objectKey, opts)
+	metrics.ObserveBucket("delete", 0, err)
 	if err != nil {
 		log.Error().
+			Ctx(ctx).
 			Err(err).
 			Str("service", "file").
 			Str("module", "bucket client").
@@ -70,18 +85,22 @@ func (c *clientImpl) Delete(objectKey string) error {
 	return nil
 }
 
-func (c *clientImpl) DeleteMany(objectKeys []string) error {
-	ctx := context.Background()
-	_, cancel := context.WithTimeout(ctx, 50*time.Second)
+func (c *clientImpl) DeleteMany(ctx context.Context, objectKeys []string) (err error) {
+	ctx, span := c.startSpan(ctx, "bucket.DeleteMany", attribute.Int("bucket.objects", len(objectKeys)))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
 	defer cancel()
 
 	opts := minio.RemoveObjectOptions{
 		GovernanceBypass: true,
 	}
 	for _, objectKey := range objectKeys {
-		err := c.minio.RemoveObject(context.Background(), c.conf.BucketName, objectKey, opts)
+		err = c.minio.RemoveObject(ctx, c.conf.BucketName, objectKey, opts)
+		metrics.ObserveBucket("delete", 0, err)
 		if err != nil {
 			log.Error().
+				Ctx(ctx).
 				Err(err).
 				Str("service", "file").
 				Str("module", "bucket client").
@@ -94,6 +113,59 @@ func (c *clientImpl) DeleteMany(objectKeys []string) error {
 	return nil
 }
 
+func (c *clientImpl) Download(ctx context.Context, objectKey string) (_ []byte, err error) {
+	ctx, span := c.startSpan(ctx, "bucket.Download", attribute.String("bucket.object_key", objectKey))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
+	defer cancel()
+
+	object, err := c.minio.GetObject(ctx, c.conf.BucketName, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "file").
+			Str("module", "bucket client").
+			Msgf("Couldn't get object from bucket %v:%v.", c.conf.BucketName, objectKey)
+
+		return nil, errors.Wrap(err, "Error while getting the object")
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "file").
+			Str("module", "bucket client").
+			Msgf("Couldn't read object from bucket %v:%v.", c.conf.BucketName, objectKey)
+
+		return nil, errors.Wrap(err, "Error while reading the object")
+	}
+
+	return data, nil
+}
+
+// Ping checks that the bucket is reachable and exists, it is used by the readiness check
+func (c *clientImpl) Ping(ctx context.Context) error {
+	exists, err := c.minio.BucketExists(ctx, c.conf.BucketName)
+	if err != nil {
+		return errors.Wrap(err, "Error while checking the bucket")
+	}
+	if !exists {
+		return errors.Errorf("bucket %v does not exist", c.conf.BucketName)
+	}
+
+	return nil
+}
+
+func (c *clientImpl) startSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	attributes = append(attributes, attribute.String("bucket.name", c.conf.BucketName))
+	return tracing.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
 func (c *clientImpl) getURL(objectKey string) string {
 	return "https://" + c.conf.Endpoint + "/" + c.conf.BucketName + "/" + objectKey
 }
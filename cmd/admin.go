@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// passwordEnv keeps the password out of the arguments, which show up in the shell history and the process list
+const passwordEnv = "ADMIN_PASSWORD"
+
+// runCreateAdmin signs up the first admin, Signup only creates users so the role is updated afterwards
+func runCreateAdmin(a *app, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the admin")
+	firstname := flags.String("firstname", "Admin", "first name of the admin")
+	lastname := flags.String("lastname", "Johnjud", "last name of the admin")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+
+	ctx := context.Background()
+	user := &model.User{}
+	err := a.userRepo.FindByEmail(ctx, *email, user)
+	if err == nil {
+		if _, respErr := a.userService.UpdateRole(ctx, user.ID.String(), &dto.UpdateUserRoleRequest{Role: constant.ADMIN}); respErr != nil {
+			return errors.New(respErr.Message)
+		}
+		fmt.Printf("promoted %s to admin\n", *email)
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	password, err := readPassword()
+	if err != nil {
+		return err
+	}
+
+	request := &dto.SignupRequest{
+		Email:     *email,
+		Password:  password,
+		Firstname: *firstname,
+		Lastname:  *lastname,
+	}
+	if err := validate(ctx, a, request); err != nil {
+		return err
+	}
+
+	signup, respErr := a.authService.Signup(ctx, request)
+	if respErr != nil {
+		return errors.New(respErr.Message)
+	}
+	if _, respErr := a.userService.UpdateRole(ctx, signup.Id, &dto.UpdateUserRoleRequest{Role: constant.ADMIN}); respErr != nil {
+		return errors.New(respErr.Message)
+	}
+
+	fmt.Printf("created admin %s with id %s\n", signup.Email, signup.Id)
+	return nil
+}
+
+// runResetPassword goes through the same reset as the forgot password link and then signs the user out everywhere
+func runResetPassword(a *app, args []string) error {
+	flags := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+
+	ctx := context.Background()
+	user := &model.User{}
+	if err := a.userRepo.FindByEmail(ctx, *email, user); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New(constant.UserNotFoundErrorMessage)
+		}
+		return err
+	}
+
+	password, err := readPassword()
+	if err != nil {
+		return err
+	}
+
+	resetToken, err := a.tokenService.CreateResetPasswordToken(ctx, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	request := &dto.ResetPasswordRequest{Token: resetToken, Password: password}
+	if err := validate(ctx, a, request); err != nil {
+		_ = a.tokenService.RemoveResetPasswordToken(ctx, resetToken)
+		return err
+	}
+
+	if _, respErr := a.authService.ResetPassword(ctx, request, &dto.RequestMeta{UserAgent: "cli"}); respErr != nil {
+		return errors.New(respErr.Message)
+	}
+	if respErr := a.sessionService.RevokeByUserId(ctx, user.ID.String(), ""); respErr != nil {
+		return errors.New(respErr.Message)
+	}
+
+	fmt.Printf("reset the password of %s\n", *email)
+	return nil
+}
+
+// readPassword reads ADMIN_PASSWORD, or the first line of stdin when it is not set
+func readPassword() (string, error) {
+	if password := os.Getenv(passwordEnv); password != "" {
+		return password, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s is not set, enter the password: ", passwordEnv)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.Wrap(err, "read password")
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("password is required")
+	}
+
+	return password, nil
+}
+
+func validate(ctx context.Context, a *app, request interface{}) error {
+	errs := a.validator.Validate(ctx, request)
+	if errs == nil {
+		return nil
+	}
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Message)
+	}
+
+	return errors.New(constant.InvalidRequestBodyMessage + strings.Join(messages, ", "))
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/client/bucket"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/database"
+	"github.com/isd-sgcu/johnjud-backend/internal/apikey"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/email"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/jwt"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/oauth"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/securityevent"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/throttle"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/token"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/twofactor"
+	"github.com/isd-sgcu/johnjud-backend/internal/cache"
+	"github.com/isd-sgcu/johnjud-backend/internal/healthcheck"
+	"github.com/isd-sgcu/johnjud-backend/internal/image"
+	"github.com/isd-sgcu/johnjud-backend/internal/password"
+	"github.com/isd-sgcu/johnjud-backend/internal/pet"
+	"github.com/isd-sgcu/johnjud-backend/internal/ratelimit"
+	"github.com/isd-sgcu/johnjud-backend/internal/session"
+	"github.com/isd-sgcu/johnjud-backend/internal/user"
+	"github.com/isd-sgcu/johnjud-backend/internal/utils"
+	"github.com/isd-sgcu/johnjud-backend/internal/validator"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// app holds the services shared by the commands, the server and the admin commands go through the same ones
+type app struct {
+	conf      *config.Config
+	db        *gorm.DB
+	cache     *redis.Client
+	validator validator.IDtoValidator
+	// bucketTransport holds the connections of the minio client, which has no Close of its own
+	bucketTransport *http.Transport
+
+	tokenService         token.Service
+	sessionService       session.Service
+	emailService         email.Service
+	emailWorker          email.Worker
+	userRepo             user.Repository
+	petRepo              pet.Repository
+	userService          user.Service
+	twoFactorService     twofactor.Service
+	oauthService         oauth.Service
+	securityEventService securityevent.Service
+	authService          auth.Service
+	apiKeyService        apikey.Service
+	imageService         image.Service
+	petService           pet.Service
+	healthCheckService   healthcheck.Service
+	rateLimitService     ratelimit.Service
+}
+
+func newApp(conf *config.Config, db *gorm.DB) (*app, error) {
+	cacheDb, err := database.InitRedisConnection(&conf.Redis)
+	if err != nil {
+		return nil, errors.Wrap(err, "init redis connection")
+	}
+
+	breachedPasswords, err := password.LoadBreachedList(conf.Password.BreachedListPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "load breached passwords")
+	}
+	passwordPolicy := password.NewPolicy(conf.Password, breachedPasswords)
+
+	v, err := validator.NewIValidator(passwordPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "init validator")
+	}
+
+	uuidUtil := utils.NewUuidUtil()
+	bcryptUtil := utils.NewBcryptUtil()
+
+	accessTokenCache := cache.NewRepository(cacheDb)
+	refreshTokenCache := cache.NewRepository(cacheDb)
+	resetPasswordCache := cache.NewRepository(cacheDb)
+	verifyEmailCache := cache.NewRepository(cacheDb)
+	throttleCache := cache.NewRepository(cacheDb)
+	twoFactorChallengeCache := cache.NewRepository(cacheDb)
+	oauthStateCache := cache.NewRepository(cacheDb)
+	magicLinkCache := cache.NewRepository(cacheDb)
+	apiKeyRateCache := cache.NewRepository(cacheDb)
+
+	jwtStrat := jwt.NewJwtStrategy(conf.Jwt.Secret)
+	jwtUtils := jwt.NewJwtUtil()
+	jwtSvc := jwt.NewService(conf.Jwt, jwtStrat, jwtUtils)
+	tokenSvc := token.NewService(jwtSvc, accessTokenCache, refreshTokenCache, resetPasswordCache, verifyEmailCache, twoFactorChallengeCache, magicLinkCache, uuidUtil)
+
+	sessionRepo := session.NewRepository(db)
+	sessionSvc := session.NewService(sessionRepo, tokenSvc)
+
+	emailTransport, err := email.NewTransport(conf.Email, conf.Sendgrid, conf.Smtp)
+	if err != nil {
+		return nil, errors.Wrap(err, "init email transport")
+	}
+	emailRenderer, err := email.NewRenderer()
+	if err != nil {
+		return nil, errors.Wrap(err, "parse email templates")
+	}
+	emailRepo := email.NewRepository(db)
+	emailSvc := email.NewService(conf.Email, emailRepo, emailRenderer)
+	emailWorker := email.NewWorker(conf.Email, emailRepo, emailTransport)
+	petRepo := pet.NewRepository(db)
+
+	securityEventRepo := securityevent.NewRepository(db)
+	securityEventSvc := securityevent.NewService(securityEventRepo)
+	userRepo := user.NewRepository(db)
+	userSvc := user.NewService(userRepo, bcryptUtil, sessionSvc, petRepo, tokenSvc, emailSvc, securityEventSvc, conf.Auth)
+
+	authRepo := auth.NewRepository(db)
+	throttleSvc := throttle.NewService(conf.Throttle, throttleCache)
+	twoFactorRepo := twofactor.NewRepository(db)
+	twoFactorSvc := twofactor.NewService(twoFactorRepo, userRepo, conf.Auth)
+	oauthRepo := oauth.NewRepository(db)
+	oauthSvc := oauth.NewService(conf.OAuth, oauthRepo, userRepo, oauthStateCache, &http.Client{Timeout: 10 * time.Second})
+	authSvc := auth.NewService(authRepo, userRepo, tokenSvc, emailSvc, throttleSvc, twoFactorSvc, oauthSvc, securityEventSvc, bcryptUtil, conf.Auth)
+
+	apiKeyRepo := apikey.NewRepository(db)
+	apiKeySvc := apikey.NewService(conf.ApiKey, apiKeyRepo, apiKeyRateCache)
+
+	bucketTransport, err := minio.DefaultTransport(conf.Bucket.UseSSL)
+	if err != nil {
+		return nil, errors.Wrap(err, "init minio transport")
+	}
+	minioClient, err := minio.New(conf.Bucket.Endpoint, &minio.Options{
+		Creds:     credentials.NewStaticV4(conf.Bucket.AccessKeyID, conf.Bucket.SecretAccessKey, ""),
+		Secure:    conf.Bucket.UseSSL,
+		Transport: bucketTransport,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "init minio client")
+	}
+	imageClient := bucket.NewClient(conf.Bucket, minioClient)
+	randomUtils := utils.NewRandomUtil()
+	imageRepo := image.NewRepository(db)
+	imageSvc := image.NewService(imageClient, imageRepo, randomUtils)
+
+	petSvc := pet.NewService(petRepo, imageSvc, v, conf.Pet, userRepo, emailSvc)
+
+	healthCheckSvc := healthcheck.NewService(conf.HealthCheck,
+		healthcheck.NewDatabaseDependency(db),
+		healthcheck.NewRedisDependency(cacheDb),
+		healthcheck.NewBucketDependency(imageClient),
+	)
+
+	return &app{
+		conf:                 conf,
+		db:                   db,
+		cache:                cacheDb,
+		validator:            v,
+		bucketTransport:      bucketTransport,
+		tokenService:         tokenSvc,
+		sessionService:       sessionSvc,
+		emailService:         emailSvc,
+		emailWorker:          emailWorker,
+		userRepo:             userRepo,
+		petRepo:              petRepo,
+		userService:          userSvc,
+		twoFactorService:     twoFactorSvc,
+		oauthService:         oauthSvc,
+		securityEventService: securityEventSvc,
+		authService:          authSvc,
+		apiKeyService:        apiKeySvc,
+		imageService:         imageSvc,
+		petService:           petSvc,
+		healthCheckService:   healthCheckSvc,
+		rateLimitService:     ratelimit.NewService(cacheDb),
+	}, nil
+}
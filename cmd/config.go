@@ -0,0 +1,19 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+)
+
+const configUsage = "usage: config print"
+
+// runConfig prints the effective config, e.g. to check what a deployment picked up from its file, env and secrets
+func runConfig(conf *config.Config, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errors.New(configUsage)
+	}
+
+	return conf.Print(os.Stdout)
+}
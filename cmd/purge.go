@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// runPurgeOrphans removes the images that were uploaded but never attached to a pet, or whose pet has been deleted
+func runPurgeOrphans(a *app, args []string) error {
+	flags := flag.NewFlagSet("purge-orphans", flag.ContinueOnError)
+	olderThan := flags.Duration("older-than", 24*time.Hour, "only purge images uploaded before this long ago")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *olderThan < 0 {
+		return errors.New("-older-than must not be negative")
+	}
+
+	res, respErr := a.imageService.PurgeOrphans(context.Background(), time.Now().Add(-*olderThan))
+	if respErr != nil {
+		return errors.New(respErr.Message)
+	}
+
+	fmt.Printf("purged %d orphan images\n", res.Count)
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/pkg/errors"
+)
+
+var (
+	seedTypes    = []string{"dog", "cat"}
+	seedNames    = []string{"Lucky", "Mali", "Kanom", "Moo Ping", "Tofu", "Khao Tom", "Som", "Nam Tan", "Bua", "Pla Too", "Mochi", "Kati"}
+	seedPatterns = []string{"solid", "spots", "stripes", "tabby", "bicolor"}
+	seedOrigins  = []string{"shelter", "street", "rescue", "owner surrender"}
+	seedHabits   = []string{
+		"friendly with people and other pets",
+		"shy at first, loves treats",
+		"playful and needs a lot of exercise",
+		"calm, likes to nap in the sun",
+		"curious and follows people around",
+	}
+	seedCaptions = []string{
+		"Looking for a warm home",
+		"Found near the faculty canteen",
+		"Vaccinated and ready for adoption",
+		"Gets along well with children",
+		"",
+	}
+	seedColors = map[string]color.RGBA{
+		"white":  {R: 240, G: 240, B: 235, A: 255},
+		"black":  {R: 35, G: 35, B: 40, A: 255},
+		"brown":  {R: 139, G: 94, B: 60, A: 255},
+		"orange": {R: 230, G: 140, B: 60, A: 255},
+		"grey":   {R: 150, G: 150, B: 155, A: 255},
+		"cream":  {R: 235, G: 215, B: 175, A: 255},
+	}
+)
+
+// runSeed creates fake pets through the pet and image services so the data goes to the bucket like real uploads
+func runSeed(a *app, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	count := flags.Int("pets", 20, "number of pets to create")
+	force := flags.Bool("force", false, "seed outside of development")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !a.conf.App.IsDevelopment() && !*force {
+		return errors.New("seed only runs in development, use -force to seed anyway")
+	}
+
+	ctx := context.Background()
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var colorNames []string
+	for name := range seedColors {
+		colorNames = append(colorNames, name)
+	}
+
+	for i := 0; i < *count; i++ {
+		colorName := colorNames[random.Intn(len(colorNames))]
+
+		file, err := seedImage(seedColors[colorName], random)
+		if err != nil {
+			return err
+		}
+		uploaded, respErr := a.imageService.Upload(ctx, &dto.UploadImageRequest{
+			Filename: fmt.Sprintf("seed-%d.png", i+1),
+			File:     file,
+		})
+		if respErr != nil {
+			return errors.New(respErr.Message)
+		}
+
+		request := seedPet(random, colorName)
+		request.Images = []string{uploaded.Id}
+		if err := validate(ctx, a, request); err != nil {
+			return err
+		}
+
+		pet, respErr := a.petService.Create(ctx, request)
+		if respErr != nil {
+			return errors.New(respErr.Message)
+		}
+		fmt.Printf("created %s %s (%s)\n", pet.Type, pet.Name, pet.Id)
+	}
+
+	return nil
+}
+
+func seedPet(random *rand.Rand, colorName string) *dto.CreatePetRequest {
+	gender := constant.MALE
+	if random.Intn(2) == 0 {
+		gender = constant.FEMALE
+	}
+	status := constant.FINDHOME
+	if random.Intn(5) == 0 {
+		status = constant.ADOPTED
+	}
+	isSterile := random.Intn(3) != 0
+	isVaccinated := random.Intn(4) != 0
+	isVisible := true
+	// up to 10 years old
+	birthdate := time.Now().AddDate(0, -random.Intn(120), -random.Intn(28)).UTC().Truncate(24 * time.Hour)
+
+	return &dto.CreatePetRequest{
+		Type:         seedTypes[random.Intn(len(seedTypes))],
+		Name:         seedNames[random.Intn(len(seedNames))],
+		Birthdate:    birthdate.Format(time.RFC3339),
+		Gender:       gender,
+		Color:        colorName,
+		Pattern:      seedPatterns[random.Intn(len(seedPatterns))],
+		Habit:        seedHabits[random.Intn(len(seedHabits))],
+		Caption:      seedCaptions[random.Intn(len(seedCaptions))],
+		Status:       status,
+		IsSterile:    &isSterile,
+		IsVaccinated: &isVaccinated,
+		IsVisible:    &isVisible,
+		Origin:       seedOrigins[random.Intn(len(seedOrigins))],
+		Contact:      "johnjud@example.com",
+		Tel:          fmt.Sprintf("08%08d", random.Intn(100000000)),
+	}
+}
+
+// seedImage draws a soft gradient in the color of the pet as a stand in for a photo
+func seedImage(base color.RGBA, random *rand.Rand) ([]byte, error) {
+	const width, height = 640, 480
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	cx, cy := random.Intn(width), random.Intn(height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dx, dy := x-cx, y-cy
+			shade := 1 - float64(dx*dx+dy*dy)/float64(width*width+height*height)
+			img.Set(x, y, color.RGBA{
+				R: uint8(float64(base.R) * shade),
+				G: uint8(float64(base.G) * shade),
+				B: uint8(float64(base.B) * shade),
+				A: 255,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
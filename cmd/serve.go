@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/database"
+	"github.com/isd-sgcu/johnjud-backend/internal/apikey"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/email"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/oauth"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/securityevent"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/twofactor"
+	"github.com/isd-sgcu/johnjud-backend/internal/healthcheck"
+	"github.com/isd-sgcu/johnjud-backend/internal/image"
+	"github.com/isd-sgcu/johnjud-backend/internal/lifecycle"
+	"github.com/isd-sgcu/johnjud-backend/internal/metrics"
+	guard "github.com/isd-sgcu/johnjud-backend/internal/middleware/auth"
+	ratelimitMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/ratelimit"
+	"github.com/isd-sgcu/johnjud-backend/internal/pet"
+	"github.com/isd-sgcu/johnjud-backend/internal/router"
+	"github.com/isd-sgcu/johnjud-backend/internal/tracing"
+	"github.com/isd-sgcu/johnjud-backend/internal/user"
+)
+
+func runServe(a *app, _ []string) error {
+	if a.conf.Database.AutoMigrate {
+		if err := database.MigratePostgresDatabase(a.db); err != nil {
+			return err
+		}
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), a.conf.Tracing)
+	if err != nil {
+		return err
+	}
+
+	collectTimeout := time.Duration(a.conf.Metrics.CollectTimeout) * time.Millisecond
+	if err := metrics.Registry.Register(pet.NewCollector(a.petRepo, collectTimeout)); err != nil {
+		return err
+	}
+
+	hc := healthcheck.NewHandler(a.healthCheckService)
+	emailHandler := email.NewHandler(a.emailService)
+	userHandler := user.NewHandler(a.userService, a.validator)
+	twoFactorHandler := twofactor.NewHandler(a.twoFactorService, a.validator)
+	oauthHandler := oauth.NewHandler(a.oauthService)
+	securityEventHandler := securityevent.NewHandler(a.securityEventService)
+	authHandler := auth.NewHandler(a.authService, a.userService, a.validator)
+	apiKeyHandler := apikey.NewHandler(a.apiKeyService, a.validator)
+	imageHandler := image.NewHandler(a.imageService, a.validator, a.conf.App.MaxFileSize)
+	petHandler := pet.NewHandler(a.petService, a.imageService, a.validator, a.conf.App.MaxFileSize)
+
+	authGuard := guard.NewAuthGuard(a.authService, a.apiKeyService, constant.ExcludePath, constant.AdminPath, a.conf.App, constant.VersionList)
+
+	rateLimiter := ratelimitMiddleware.NewLimiter(a.rateLimitService, a.conf.RateLimit, constant.VersionList)
+
+	r := router.NewFiberRouter(&authGuard, &rateLimiter, a.conf.App)
+
+	r.GetUser("/admin", userHandler.FindAll)
+	r.GetUser("/admin/:id/sessions", userHandler.FindSessions)
+	r.GetUser("/admin/:id/adoptions", userHandler.FindAdoptions)
+	r.PutUser("/admin/:id/role", userHandler.UpdateRole)
+	r.PutUser("/admin/:id/suspend", userHandler.Suspend)
+	r.GetUser("/me/export", userHandler.Export)
+	r.GetUser("/:id", userHandler.FindOne)
+	r.PatchUser("", userHandler.Update)
+	r.PutUser("/password", userHandler.ChangePassword)
+	r.PostUser("/verify-email", userHandler.VerifyEmail)
+	r.DeleteUser("/me", userHandler.DeleteAccount)
+	r.DeleteUser("/:id", userHandler.Delete)
+
+	r.PostAuth("/signup", authHandler.Signup)
+	r.PostAuth("/signin", authHandler.SignIn)
+	r.PostAuth("/signin/2fa", authHandler.SignInTwoFactor)
+	r.GetAuth("/oauth/authorize", oauthHandler.Authorize)
+	r.PostAuth("/oauth/callback", authHandler.SignInOAuth)
+	r.PostAuth("/magic-link", authHandler.RequestMagicLink)
+	r.PostAuth("/signin/magic-link", authHandler.SignInMagicLink)
+	r.PostAuth("/signout", authHandler.SignOut)
+	//r.PostAuth("/me", authHandler.Validate)
+	r.PostAuth("/refreshToken", authHandler.RefreshToken)
+	r.PostAuth("/forgot-password", authHandler.ForgotPassword)
+	r.PostAuth("/2fa/enroll", twoFactorHandler.Enroll)
+	r.PostAuth("/2fa/enable", twoFactorHandler.Enable)
+	r.PostAuth("/2fa/disable", twoFactorHandler.Disable)
+	r.GetAuth("/security-events", securityEventHandler.FindMine)
+	r.PutAuth("/admin/reset-password", authHandler.ResetPassword)
+	r.GetAuth("/admin/email-preview", emailHandler.Preview)
+	r.GetAuth("/admin/emails", emailHandler.FindAll)
+	r.PostAuth("/admin/emails/:id/retry", emailHandler.Retry)
+	r.GetAuth("/admin/api-keys", apiKeyHandler.FindAll)
+	r.PostAuth("/admin/api-keys", apiKeyHandler.Create)
+	r.DeleteAuth("/admin/api-keys/:id", apiKeyHandler.Revoke)
+	r.GetAuth("/admin/security-events", securityEventHandler.FindAll)
+
+	r.GetHealthCheck("", hc.HealthCheck)
+	r.GetHealthCheck("/healthz", hc.Liveness)
+	r.GetHealthCheck("/readyz", hc.Readiness)
+
+	r.GetPet("", petHandler.FindAll)
+	r.GetPet("/admin", petHandler.FindAllAdmin)
+	r.GetPet("/admin/export", petHandler.Export)
+	r.GetPet("/:id", petHandler.FindOne)
+	r.PostPet("", petHandler.Create)
+	r.PostPet("/import", petHandler.Import)
+	r.PutPet("/:id", petHandler.Update)
+	r.PutPet("/:id/adopt", petHandler.Adopt)
+	r.PutPet("/:id/visible", petHandler.ChangeView)
+	r.DeletePet("/:id", petHandler.Delete)
+
+	r.PostImage("", imageHandler.Upload)
+	r.DeleteImage("/:id", imageHandler.Delete)
+
+	// requests are cancelled once shutdown stops waiting for them
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	v1 := router.NewAPIv1(requestCtx, r, a.conf.App)
+	metricsServer := metrics.NewServer(a.conf.Metrics.Port)
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+
+	// the components are stopped in the reverse order, so the server drains its requests while the worker,
+	// the connections and the tracer are still up
+	lc := lifecycle.NewManager(time.Duration(a.conf.App.ShutdownTimeout) * time.Second)
+	lc.Append(lifecycle.Hook{
+		Name: "database",
+		Stop: func(context.Context) error {
+			sqlDb, err := a.db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDb.Close()
+		},
+	})
+	lc.Append(lifecycle.Hook{
+		Name: "redis",
+		Stop: func(context.Context) error {
+			return a.cache.Close()
+		},
+	})
+	lc.Append(lifecycle.Hook{
+		Name: "bucket",
+		Stop: func(context.Context) error {
+			a.bucketTransport.CloseIdleConnections()
+			return nil
+		},
+	})
+	lc.Append(lifecycle.Hook{
+		Name: "tracing",
+		Stop: shutdownTracing,
+	})
+	lc.Append(lifecycle.Hook{
+		Name: "email worker",
+		Start: func(context.Context) error {
+			go func() {
+				defer close(workerDone)
+				a.emailWorker.Run(workerCtx)
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			stopWorker()
+			select {
+			case <-workerDone:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+	lc.Append(lifecycle.Hook{
+		Name: "metrics server",
+		Start: func(context.Context) error {
+			go func() {
+				if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					lc.Fail("metrics server", err)
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			return metricsServer.Shutdown(ctx)
+		},
+	})
+	lc.Append(lifecycle.Hook{
+		Name: "server",
+		Start: func(context.Context) error {
+			go func() {
+				if err := v1.Listen(fmt.Sprintf(":%v", a.conf.App.Port)); err != nil && err != http.ErrServerClosed {
+					lc.Fail("server", err)
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			defer cancelRequests()
+			return v1.ShutdownWithContext(ctx)
+		},
+	})
+
+	return lc.Run(context.Background())
+}
@@ -1,150 +1,319 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
-	"strconv"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type App struct {
-	Port        int
-	Env         string
-	MaxFileSize int64
+	Port        int    `config:"port" default:"3001" validate:"min=1,max=65535"`
+	Env         string `config:"env" validate:"required"`
+	MaxFileSize int64  `config:"max_file_size" default:"10" validate:"min=1"`
+	// ProxyHeader holds the client ip when running behind a proxy, e.g. X-Real-IP or X-Forwarded-For. Of a list
+	// of hops the rightmost one that is not a trusted proxy is the client, as the ones left of it are sent by the client
+	ProxyHeader string `config:"proxy_header"`
+	// TrustedProxies are the ips or cidr ranges of the proxies whose ProxyHeader is believed,
+	// the header of any other caller is ignored so it cannot pick its own ip
+	TrustedProxies []string `config:"trusted_proxies" sep:","`
+	// RequestTimeout is the number of seconds a request may run before its context is cancelled
+	RequestTimeout int `config:"request_timeout" default:"60" validate:"min=1"`
+	// ShutdownTimeout is the number of seconds the server, workers and connections have in total to stop
+	ShutdownTimeout int `config:"shutdown_timeout" default:"10" validate:"min=1"`
 }
 
 type Database struct {
-	Url string
+	Url string `config:"url" validate:"required" secret:"true"`
+	// AutoMigrate applies the pending migrations when the server starts, otherwise run the migrate command
+	AutoMigrate bool `config:"auto_migrate" default:"true"`
 }
 
 type Redis struct {
-	Host     string
-	Port     int
-	Password string
+	Host     string `config:"host" default:"localhost" validate:"required"`
+	Port     int    `config:"port" default:"6379" validate:"min=1,max=65535"`
+	Password string `config:"password" secret:"true"`
 }
 
 type Jwt struct {
-	Secret          string
-	ExpiresIn       int
-	RefreshTokenTTL int
-	Issuer          string
-	ResetTokenTTL   int
+	Secret                string `config:"secret" validate:"required" secret:"true"`
+	ExpiresIn             int    `config:"expires_in" default:"3600" validate:"min=1"`
+	RefreshTokenTTL       int    `config:"refresh_token_ttl" default:"604800" validate:"min=1"`
+	Issuer                string `config:"issuer" default:"johnjud" validate:"required"`
+	ResetTokenTTL         int    `config:"reset_token_ttl" default:"900" validate:"min=1"`
+	VerifyEmailTokenTTL   int    `config:"verify_email_token_ttl" default:"86400" validate:"min=1"`
+	TwoFactorChallengeTTL int    `config:"two_factor_challenge_ttl" default:"300" validate:"min=1"`
+	MagicLinkTokenTTL     int    `config:"magic_link_token_ttl" default:"900" validate:"min=1"`
 }
 
 type Auth struct {
-	ClientURL string
+	ClientURL string `config:"client_url" validate:"required,url"`
+	// TotpIssuer is the account name shown in authenticator apps
+	TotpIssuer string `config:"totp_issuer" default:"Johnjud" validate:"required"`
+	// RequireAdminTwoFactor signs admins without 2fa in as users until they enroll
+	RequireAdminTwoFactor bool `config:"require_admin_2fa" default:"false"`
+	// ReauthTTL is how long in seconds after signing in a user can delete the account without the password
+	ReauthTTL int `config:"reauth_ttl" default:"300" validate:"min=1"`
+}
+
+type OAuth struct {
+	// RedirectURL is the client page that receives the code and posts it back with the state
+	RedirectURL string                   `config:"redirect_url"`
+	StateTTL    int                      `config:"state_ttl" default:"600" validate:"min=1"`
+	Providers   map[string]OAuthProvider `config:"providers" sep:","`
+}
+
+// OAuthProvider endpoints are discovered from the issuer when they are not set
+type OAuthProvider struct {
+	ClientID     string   `config:"client_id" validate:"required"`
+	ClientSecret string   `config:"client_secret" validate:"required" secret:"true"`
+	Issuer       string   `config:"issuer"`
+	AuthURL      string   `config:"auth_url"`
+	TokenURL     string   `config:"token_url"`
+	UserInfoURL  string   `config:"userinfo_url"`
+	Scopes       []string `config:"scopes"`
+	// TrustEmail treats the email as verified for providers that do not send email_verified
+	TrustEmail bool `config:"trust_email" default:"false"`
+}
+
+type Password struct {
+	MinLength     int  `config:"min_length" default:"8" validate:"min=1"`
+	MaxLength     int  `config:"max_length" default:"64" validate:"max=72"`
+	RequireUpper  bool `config:"require_upper" default:"false"`
+	RequireLower  bool `config:"require_lower" default:"true"`
+	RequireDigit  bool `config:"require_digit" default:"true"`
+	RequireSymbol bool `config:"require_symbol" default:"false"`
+	// BreachedListPath is an optional file of breached sha1 hashes sorted by hash, such as the Pwned Passwords
+	// download ordered by hash, checked on top of the embedded common passwords. It is searched on disk, not loaded
+	BreachedListPath string `config:"breached_list_path"`
+}
+
+type ApiKey struct {
+	// DefaultRateLimit is the number of requests per minute of keys created without a rate limit
+	DefaultRateLimit int `config:"default_rate_limit" default:"600" validate:"min=1"`
+}
+
+type Email struct {
+	Transport          string `config:"transport" default:"sendgrid" validate:"oneof=sendgrid smtp file log"`
+	FromName           string `config:"from_name" default:"johnjud" alias:"SENDGRID_NAME"`
+	FromAddress        string `config:"from_address" validate:"required,email" alias:"SENDGRID_ADDRESS"`
+	SinkDir            string `config:"sink_dir" default:"./tmp/emails"`
+	OutboxPollInterval int    `config:"outbox_poll_interval" default:"5" validate:"min=1"`
+	OutboxBatchSize    int    `config:"outbox_batch_size" default:"20" validate:"min=1"`
+	OutboxMaxAttempts  int    `config:"outbox_max_attempts" default:"8" validate:"min=1"`
+	OutboxBaseBackoff  int    `config:"outbox_base_backoff" default:"30" validate:"min=1"`
+	OutboxMaxBackoff   int    `config:"outbox_max_backoff" default:"3600" validate:"min=1"`
 }
 
 type Sendgrid struct {
-	ApiKey  string
-	Name    string
-	Address string
+	ApiKey string `config:"api_key" secret:"true"`
+}
+
+type Smtp struct {
+	Host     string `config:"host"`
+	Port     int    `config:"port" validate:"min=0,max=65535"`
+	Username string `config:"username"`
+	Password string `config:"password" secret:"true"`
+}
+
+type Throttle struct {
+	MaxFailures   int `config:"max_failures" default:"10" validate:"min=1"`
+	IpMaxFailures int `config:"ip_max_failures" default:"50" validate:"min=1"`
+	Window        int `config:"window" default:"900" validate:"min=1"`
+	Lockout       int `config:"lockout" default:"900" validate:"min=1"`
+	DelayAfter    int `config:"delay_after" default:"3" validate:"min=0"`
+	BaseDelay     int `config:"base_delay" default:"1" validate:"min=0"`
+	MaxDelay      int `config:"max_delay" default:"30" validate:"min=0"`
 }
 
 type Bucket struct {
-	Endpoint        string
-	AccessKeyID     string
-	SecretAccessKey string
-	UseSSL          bool
-	BucketName      string
+	Endpoint        string `config:"endpoint" validate:"required"`
+	AccessKeyID     string `config:"access_key_id" validate:"required"`
+	SecretAccessKey string `config:"secret_access_key" validate:"required" secret:"true"`
+	UseSSL          bool   `config:"use_ssl" default:"true"`
+	BucketName      string `config:"name" validate:"required"`
+}
+
+type Pet struct {
+	PageURL        string `config:"page_url"`
+	ExportFontPath string `config:"export_font_path"`
+	// ExportPdfMaxPets caps a pdf export, the primary photo of every pet in it is held in memory
+	ExportPdfMaxPets int `config:"export_pdf_max_pets" default:"200" validate:"min=1"`
+}
+
+type HealthCheck struct {
+	// Timeout is the number of milliseconds each dependency gets to answer the readiness check
+	Timeout int `config:"timeout" default:"2000" validate:"min=1"`
+}
+
+type Metrics struct {
+	// Port serves /metrics on its own listener so it can be kept off the public network, apart from the api
+	Port int `config:"port" default:"9091" validate:"min=1,max=65535"`
+	// CollectTimeout is the number of milliseconds the database queries of a scrape may take
+	CollectTimeout int `config:"collect_timeout" default:"2000" validate:"min=1"`
+}
+
+type Tracing struct {
+	// Enabled exports the spans to the otlp http endpoint, the trace context is propagated either way
+	Enabled     bool   `config:"enabled" default:"false"`
+	Endpoint    string `config:"endpoint" default:"localhost:4318"`
+	Insecure    bool   `config:"insecure" default:"false"`
+	ServiceName string `config:"service_name" default:"johnjud-backend" validate:"required"`
+	// SampleRatio is the share of new traces that are recorded, requests from a sampled caller are always recorded
+	SampleRatio float64 `config:"sample_ratio" default:"1" validate:"min=0,max=1"`
+}
+
+// RateLimit holds the named policies, the one named default covers the routes that are not in another policy
+// and the one named guard counts every request by ip before the guard checks its token or api key.
+// There is no limit when there are no policies
+type RateLimit struct {
+	Policies map[string]RateLimitPolicy `config:"policies" sep:","`
+}
+
+// RateLimitPolicy allows Limit requests per Window seconds for each key, all of them at once in a burst at most
+type RateLimitPolicy struct {
+	Limit  int `config:"limit" validate:"min=1"`
+	Window int `config:"window" validate:"min=1"`
+	// Key counts the requests by ip, user or api_key. A request without a user or api key is counted by its ip
+	Key string `config:"key" default:"ip" validate:"oneof=ip user api_key"`
+	// Routes are written like the guard paths, e.g. POST /auth/signup or GET /pets/:id
+	Routes []string `config:"routes" sep:","`
 }
 
 type Config struct {
-	App      App
-	Database Database
-	Redis    Redis
-	Jwt      Jwt
-	Auth     Auth
-	Sendgrid Sendgrid
-	Bucket   Bucket
+	App         App         `config:"app"`
+	Database    Database    `config:"database" env:"DB"`
+	Redis       Redis       `config:"redis"`
+	Jwt         Jwt         `config:"jwt"`
+	Auth        Auth        `config:"auth"`
+	OAuth       OAuth       `config:"oauth"`
+	Password    Password    `config:"password"`
+	ApiKey      ApiKey      `config:"api_key"`
+	Email       Email       `config:"email"`
+	Sendgrid    Sendgrid    `config:"sendgrid"`
+	Smtp        Smtp        `config:"smtp"`
+	Throttle    Throttle    `config:"throttle"`
+	Bucket      Bucket      `config:"bucket"`
+	Pet         Pet         `config:"pet"`
+	HealthCheck HealthCheck `config:"healthcheck"`
+	Metrics     Metrics     `config:"metrics"`
+	Tracing     Tracing     `config:"tracing"`
+	RateLimit   RateLimit   `config:"rate_limit"`
+}
+
+// Error lists every problem found in the config so they can be fixed at once
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "invalid config: " + strings.Join(e.Problems, "; ")
 }
 
+// LoadConfig reads the defaults, then the yaml or toml file named by CONFIG_FILE, then the env vars, which are
+// loaded from .env when APP_ENV is not set. Each env var can also be read from the file named by NAME_FILE
 func LoadConfig() (*Config, error) {
 	if os.Getenv("APP_ENV") == "" {
-		err := godotenv.Load(".env")
-		if err != nil {
+		if err := godotenv.Load(".env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
 			return nil, err
 		}
 	}
 
-	port, err := strconv.ParseInt(os.Getenv("APP_PORT"), 10, 64)
-	if err != nil {
-		return nil, err
+	l := newLoader()
+	var file map[string]any
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		file = l.readFile(path)
 	}
-	maxFileSize, err := strconv.ParseInt(os.Getenv("APP_MAX_FILE_SIZE"), 10, 64)
-	if err != nil {
-		return nil, err
-	}
-	app := App{
-		Port:        int(port),
-		Env:         os.Getenv("APP_ENV"),
-		MaxFileSize: maxFileSize,
+
+	conf := &Config{}
+	l.loadStruct(reflect.ValueOf(conf).Elem(), file, "", "")
+	l.problems = append(l.problems, conf.validate()...)
+	if len(l.problems) > 0 {
+		return nil, &Error{Problems: l.problems}
 	}
 
-	database := Database{
-		Url: os.Getenv("DB_URL"),
+	return conf, nil
+}
+
+// validate checks the values that depend on each other
+func (c *Config) validate() []string {
+	var problems []string
+
+	if c.Metrics.Port == c.App.Port {
+		problems = append(problems, "metrics.port (METRICS_PORT) must not be app.port (APP_PORT)")
 	}
 
-	redisPort, err := strconv.Atoi(os.Getenv("REDIS_PORT"))
-	if err != nil {
-		return nil, err
+	if c.App.ProxyHeader != "" && len(c.App.TrustedProxies) == 0 {
+		problems = append(problems, "app.trusted_proxies (APP_TRUSTED_PROXIES) is required by app.proxy_header")
 	}
-	redis := Redis{
-		Host:     os.Getenv("REDIS_HOST"),
-		Port:     redisPort,
-		Password: os.Getenv("REDIS_PASSWORD"),
+
+	if c.Password.MinLength > c.Password.MaxLength {
+		problems = append(problems, "password.min_length must not be greater than password.max_length")
 	}
 
-	jwtExpiresIn, err := strconv.Atoi(os.Getenv("JWT_EXPIRES_IN"))
-	if err != nil {
-		return nil, err
+	switch c.Email.Transport {
+	case "sendgrid":
+		if c.Sendgrid.ApiKey == "" {
+			problems = append(problems, "sendgrid.api_key (SENDGRID_API_KEY) is required by the sendgrid email transport")
+		}
+	case "smtp":
+		if c.Smtp.Host == "" || c.Smtp.Port == 0 {
+			problems = append(problems, "smtp.host (SMTP_HOST) and smtp.port (SMTP_PORT) are required by the smtp email transport")
+		}
 	}
-	jwtRefreshTokenTTL, err := strconv.Atoi(os.Getenv("JWT_REFRESH_TOKEN_TTL"))
-	if err != nil {
-		return nil, err
+
+	var names []string
+	for name := range c.OAuth.Providers {
+		names = append(names, name)
 	}
-	jwtResetTokenTTL, err := strconv.Atoi(os.Getenv("JWT_RESET_TOKEN_TTL"))
-	if err != nil {
-		return nil, err
+	sort.Strings(names)
+	if len(names) > 0 && c.OAuth.RedirectURL == "" {
+		problems = append(problems, "oauth.redirect_url (OAUTH_REDIRECT_URL) is required by the oauth providers")
 	}
-	jwt := Jwt{
-		Secret:          os.Getenv("JWT_SECRET"),
-		ExpiresIn:       jwtExpiresIn,
-		RefreshTokenTTL: jwtRefreshTokenTTL,
-		Issuer:          os.Getenv("JWT_ISSUER"),
-		ResetTokenTTL:   jwtResetTokenTTL,
+	for _, name := range names {
+		provider := c.OAuth.Providers[name]
+		if provider.Issuer == "" && (provider.AuthURL == "" || provider.TokenURL == "" || provider.UserInfoURL == "") {
+			problems = append(problems, fmt.Sprintf("oauth.providers.%v needs an issuer or the auth, token and userinfo urls", name))
+		}
 	}
 
-	auth := Auth{
-		ClientURL: os.Getenv("AUTH_CLIENT_URL"),
+	var policies []string
+	for name := range c.RateLimit.Policies {
+		policies = append(policies, name)
 	}
-
-	sendgrid := Sendgrid{
-		ApiKey:  os.Getenv("SENDGRID_API_KEY"),
-		Name:    os.Getenv("SENDGRID_NAME"),
-		Address: os.Getenv("SENDGRID_ADDRESS"),
+	sort.Strings(policies)
+	if guard, ok := c.RateLimit.Policies["guard"]; ok {
+		if guard.Key != "ip" {
+			problems = append(problems, "rate_limit.policies.guard.key must be ip, the user is not known before the guard")
+		}
+		if len(guard.Routes) > 0 {
+			problems = append(problems, "rate_limit.policies.guard.routes must be empty, the guard policy covers every route")
+		}
 	}
-
-	bucket := Bucket{
-		Endpoint:        os.Getenv("BUCKET_ENDPOINT"),
-		AccessKeyID:     os.Getenv("BUCKET_ACCESS_KEY_ID"),
-		SecretAccessKey: os.Getenv("BUCKET_SECRET_ACCESS_KEY"),
-		UseSSL:          os.Getenv("BUCKET_USE_SSL") == "true",
-		BucketName:      os.Getenv("BUCKET_NAME"),
+	routes := map[string]string{}
+	for _, name := range policies {
+		for _, route := range c.RateLimit.Policies[name].Routes {
+			if method, path, ok := strings.Cut(route, " "); !ok || method == "" || !strings.HasPrefix(path, "/") {
+				problems = append(problems, fmt.Sprintf("rate_limit.policies.%v.routes: %q is not like POST /auth/signup", name, route))
+			}
+			if other, ok := routes[route]; ok {
+				problems = append(problems, fmt.Sprintf("rate_limit.policies.%v.routes: %v is also in %v", name, route, other))
+			}
+			routes[route] = name
+		}
 	}
 
-	return &Config{
-		App:      app,
-		Database: database,
-		Redis:    redis,
-		Jwt:      jwt,
-		Auth:     auth,
-		Sendgrid: sendgrid,
-		Bucket:   bucket,
-	}, nil
+	if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+		problems = append(problems, "tracing.endpoint (TRACING_ENDPOINT) is required when tracing is enabled")
+	}
 
+	return problems
 }
-
 func (ac *App) IsDevelopment() bool {
 	return ac.Env == "development"
 }
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// The fields of the config are described by tags:
+//
+//	config   the key in the config file, the env var is the key in upper case after the prefix of its section
+//	env      overrides the env var name or the prefix of a section, e.g. DB for the database section
+//	default  the value used when neither the file nor the env sets one
+//	validate the go-playground validator rules of the value
+//	secret   redacts the value when the config is printed
+//	sep      splits a list from the env with the separator instead of spaces
+//	alias    an old env var name that is still read, with a warning, when the env var is not set
+//
+// A value is read from the file named by NAME_FILE instead when it is set, e.g. JWT_SECRET_FILE=/run/secrets/jwt
+type loader struct {
+	validate *validator.Validate
+	problems []string
+}
+
+func newLoader() *loader {
+	return &loader{validate: validator.New()}
+}
+
+func (l *loader) problem(format string, args ...any) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
+
+// readFile decodes the yaml or toml config file into nested maps
+func (l *loader) readFile(path string) map[string]any {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		l.problem("CONFIG_FILE: %v", err)
+		return nil
+	}
+
+	values := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		l.problem("CONFIG_FILE: %v is not a .yaml, .yml or .toml file", path)
+		return nil
+	}
+	if err != nil {
+		l.problem("CONFIG_FILE: %v", err)
+		return nil
+	}
+
+	return values
+}
+
+// lookupEnv reads the env var or the file named by NAME_FILE
+func (l *loader) lookupEnv(name string) (string, bool) {
+	value, ok := os.LookupEnv(name)
+	path, fromFile := os.LookupEnv(name + "_FILE")
+	if !fromFile {
+		return value, ok
+	}
+	if ok {
+		l.problem("%v: both %v and %v_FILE are set", name, name, name)
+		return "", false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		l.problem("%v_FILE: %v", name, err)
+		return "", false
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), true
+}
+
+// loadStruct sets the fields of the struct from the defaults, the file section and the env, in that order
+func (l *loader) loadStruct(v reflect.Value, file map[string]any, path string, prefix string) {
+	known := map[string]bool{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("config")
+		if key == "" {
+			continue
+		}
+		known[key] = true
+
+		name := strings.ToUpper(key)
+		if env, ok := field.Tag.Lookup("env"); ok {
+			name = env
+		}
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		l.loadField(v.Field(i), field, file[key], join(path, key), name)
+	}
+
+	var unknown []string
+	for key := range file {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		l.problem("%v: unknown key", join(path, key))
+	}
+}
+
+func (l *loader) loadField(v reflect.Value, field reflect.StructField, fileValue any, path string, name string) {
+	switch v.Kind() {
+	case reflect.Struct:
+		l.loadStruct(v, l.section(fileValue, path), path, name)
+		return
+	case reflect.Map:
+		l.loadMap(v, field, fileValue, path, name)
+		return
+	}
+
+	// a value that could not be read or parsed is reported once, not again by its rules
+	problems := len(l.problems)
+	if value, ok := field.Tag.Lookup("default"); ok {
+		l.set(v, field, value, path, name)
+	}
+	if fileValue != nil {
+		l.setFileValue(v, field, fileValue, path, name)
+	}
+	if value, ok := l.lookupEnv(name); ok {
+		l.set(v, field, value, path, name)
+	} else if alias := field.Tag.Get("alias"); alias != "" {
+		if value, ok := l.lookupEnv(alias); ok {
+			log.Warn().
+				Str("module", "config").
+				Str("env", alias).
+				Msgf("%v is deprecated, use %v instead", alias, name)
+			l.set(v, field, value, path, alias)
+		}
+	}
+
+	if rules := field.Tag.Get("validate"); rules != "" && len(l.problems) == problems {
+		if err := l.validate.Var(v.Interface(), rules); err != nil {
+			if errs, ok := err.(validator.ValidationErrors); ok {
+				for _, e := range errs {
+					l.problem("%v (%v) %v", path, name, message(e))
+				}
+				return
+			}
+			l.problem("%v (%v): %v", path, name, err)
+		}
+	}
+}
+
+// loadMap loads a map of sections such as the oauth providers. The env var of the map lists the keys, e.g.
+// OAUTH_PROVIDERS=google,line, and the fields of each are read with the key after the prefix, e.g. OAUTH_GOOGLE_
+func (l *loader) loadMap(v reflect.Value, field reflect.StructField, fileValue any, path string, name string) {
+	sections := l.section(fileValue, path)
+
+	var keys []string
+	if value, ok := l.lookupEnv(name); ok {
+		keys = split(value, field.Tag.Get("sep"))
+	} else {
+		for key := range sections {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	prefix := strings.TrimSuffix(name, "_"+strings.ToUpper(field.Tag.Get("config")))
+	v.Set(reflect.MakeMap(v.Type()))
+	for _, key := range keys {
+		key = strings.ToLower(key)
+		item := reflect.New(v.Type().Elem()).Elem()
+		l.loadStruct(item, l.section(sections[key], join(path, key)), join(path, key), prefix+"_"+strings.ToUpper(key))
+		v.SetMapIndex(reflect.ValueOf(key), item)
+	}
+}
+
+func (l *loader) section(value any, path string) map[string]any {
+	if value == nil {
+		return nil
+	}
+	section, ok := value.(map[string]any)
+	if !ok {
+		l.problem("%v: must be a table of values", path)
+		return nil
+	}
+
+	return section
+}
+
+func (l *loader) setFileValue(v reflect.Value, field reflect.StructField, value any, path string, name string) {
+	switch value := value.(type) {
+	case map[string]any:
+		l.problem("%v: must be a value, not a table", path)
+	case []any:
+		if v.Kind() != reflect.Slice {
+			l.problem("%v: must be a value, not a list", path)
+			return
+		}
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, fmt.Sprint(item))
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		if v.Kind() == reflect.Slice {
+			l.problem("%v: must be a list", path)
+			return
+		}
+		l.set(v, field, fmt.Sprint(value), path, name)
+	}
+}
+
+// set parses the text of the default, the env or a scalar of the file into the field
+func (l *loader) set(v reflect.Value, field reflect.StructField, value string, path string, name string) {
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(value)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(value, 10, 64)
+		v.SetInt(n)
+	case reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(value, 64)
+		v.SetFloat(f)
+	case reflect.Slice:
+		v.Set(reflect.ValueOf(split(value, field.Tag.Get("sep"))))
+	default:
+		err = fmt.Errorf("unsupported type %v", v.Type())
+	}
+	if err != nil {
+		l.problem("%v (%v): %q is not a valid %v", path, name, value, v.Kind())
+	}
+}
+
+func split(value string, sep string) []string {
+	if sep == "" {
+		return strings.Fields(value)
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func message(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + e.Param()
+	case "max":
+		return "must be at most " + e.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(e.Param(), " ", ", ")
+	case "email":
+		return "must be an email address"
+	case "url":
+		return "must be a url"
+	default:
+		return fmt.Sprintf("must pass %v=%v", e.Tag(), e.Param())
+	}
+}
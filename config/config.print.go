@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[redacted]"
+
+// Print writes the config as a yaml config file with the secrets redacted
+func (c *Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(printStruct(reflect.ValueOf(c).Elem())); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func printStruct(v reflect.Value) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("config")
+		if key == "" {
+			continue
+		}
+
+		node.Content = append(node.Content, scalar(key), printValue(v.Field(i), field.Tag.Get("secret") == "true"))
+	}
+
+	return node
+}
+
+func printValue(v reflect.Value, secret bool) *yaml.Node {
+	switch v.Kind() {
+	case reflect.Struct:
+		return printStruct(v)
+	case reflect.Map:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		var keys []string
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			node.Content = append(node.Content, scalar(key), printValue(v.MapIndex(reflect.ValueOf(key)), secret))
+		}
+		return node
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for i := 0; i < v.Len(); i++ {
+			node.Content = append(node.Content, scalar(fmt.Sprint(v.Index(i).Interface())))
+		}
+		return node
+	case reflect.String:
+		if secret && v.String() != "" {
+			return scalar(redacted)
+		}
+		return scalar(v.String())
+	case reflect.Bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v.Bool())}
+	case reflect.Int, reflect.Int64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(v.Int(), 10)}
+	case reflect.Float64:
+		value := strconv.FormatFloat(v.Float(), 'g', -1, 64)
+		// keeps 1 from being printed as !!float 1
+		if !strings.ContainsAny(value, ".eIN") {
+			value += ".0"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: value}
+	default:
+		return scalar(fmt.Sprint(v.Interface()))
+	}
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
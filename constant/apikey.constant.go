@@ -0,0 +1,23 @@
+package constant
+
+type ApiKeyScope string
+
+const (
+	PetsReadScope  ApiKeyScope = "pets:read"
+	PetsWriteScope ApiKeyScope = "pets:write"
+)
+
+const ApiKeyHeader = "X-API-Key"
+
+// ApiKeyPath lists the only paths an api key can access and the scope each one needs
+var ApiKeyPath = map[string]ApiKeyScope{
+	"GET /pets":              PetsReadScope,
+	"GET /pets/:id":          PetsReadScope,
+	"GET /pets/admin":        PetsReadScope,
+	"GET /pets/admin/export": PetsReadScope,
+	"POST /pets":             PetsWriteScope,
+	"POST /pets/import":      PetsWriteScope,
+	"PUT /pets/:id":          PetsWriteScope,
+	"PUT /pets/:id/visible":  PetsWriteScope,
+	"DELETE /pets/:id":       PetsWriteScope,
+}
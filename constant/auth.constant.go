@@ -1,28 +1,48 @@
 package constant
 
 var ExcludePath = map[string]struct{}{
-	"POST /auth/signup":          {},
-	"POST /auth/signin":          {},
-	"POST /auth/verify":          {},
-	"POST /auth/forgot-password": {},
-	"PUT /auth/reset-password":   {},
-	"POST /auth/refreshToken":    {},
-	"GET /user/:id":              {},
-	"GET /pets":                  {},
-	"GET /pets/:id":              {},
-	"GET /adopt":                 {},
+	"POST /auth/signup":            {},
+	"POST /auth/signin":            {},
+	"POST /auth/signin/2fa":        {},
+	"GET /auth/oauth/authorize":    {},
+	"POST /auth/oauth/callback":    {},
+	"POST /auth/magic-link":        {},
+	"POST /auth/signin/magic-link": {},
+	"POST /auth/verify":            {},
+	"POST /auth/forgot-password":   {},
+	"PUT /auth/reset-password":     {},
+	"POST /auth/refreshToken":      {},
+	"GET /user/:id":                {},
+	"POST /user/verify-email":      {},
+	"GET /pets":                    {},
+	"GET /pets/:id":                {},
+	"GET /adopt":                   {},
 }
 
 var AdminPath = map[string]struct{}{
-	"DELETE /user/:id":            {},
-	"GET /pets/admin":             {},
-	"POST /pets":                  {},
-	"PUT /pets/:id":               {},
-	"PUT /pets/:id/visible":       {},
-	"DELETE /pets/:id":            {},
-	"POST /images/assign/:pet_id": {},
-	"DELETE /images/:id":          {},
-	"POST /images/":               {},
+	"DELETE /user/:id":                  {},
+	"GET /user/admin":                   {},
+	"GET /user/admin/:id/sessions":      {},
+	"GET /user/admin/:id/adoptions":     {},
+	"PUT /user/admin/:id/role":          {},
+	"PUT /user/admin/:id/suspend":       {},
+	"GET /auth/admin/email-preview":     {},
+	"GET /auth/admin/emails":            {},
+	"POST /auth/admin/emails/:id/retry": {},
+	"GET /pets/admin":                   {},
+	"GET /pets/admin/export":            {},
+	"POST /pets":                        {},
+	"POST /pets/import":                 {},
+	"PUT /pets/:id":                     {},
+	"PUT /pets/:id/visible":             {},
+	"DELETE /pets/:id":                  {},
+	"POST /images/assign/:pet_id":       {},
+	"DELETE /images/:id":                {},
+	"POST /images/":                     {},
+	"GET /auth/admin/api-keys":          {},
+	"POST /auth/admin/api-keys":         {},
+	"DELETE /auth/admin/api-keys/:id":   {},
+	"GET /auth/admin/security-events":   {},
 }
 
 var VersionList = map[string]struct{}{
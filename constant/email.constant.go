@@ -1,4 +1,37 @@
 package constant
 
-// auth
-const ResetPasswordSubject = "Reset Password Request"
+type EmailTransport string
+
+const (
+	SENDGRID EmailTransport = "sendgrid"
+	SMTP     EmailTransport = "smtp"
+	FILE     EmailTransport = "file"
+	LOG      EmailTransport = "log"
+)
+
+type EmailTemplate string
+
+const (
+	ResetPasswordTemplate  EmailTemplate = "reset_password"
+	VerifyEmailTemplate    EmailTemplate = "verify_email"
+	AdoptionStatusTemplate EmailTemplate = "adoption_status"
+	AccountLockedTemplate  EmailTemplate = "account_locked"
+	MagicLinkTemplate      EmailTemplate = "magic_link"
+)
+
+type Locale string
+
+const (
+	TH Locale = "th"
+	EN Locale = "en"
+)
+
+const DefaultLocale = EN
+
+type EmailStatus string
+
+const (
+	PENDING EmailStatus = "pending"
+	SENT    EmailStatus = "sent"
+	DEAD    EmailStatus = "dead"
+)
@@ -21,15 +21,20 @@ const InvalidContentMessage = "Invalid content"
 // auth
 const InvalidTokenErrorMessage = "Invalid token"
 const IncorrectEmailPasswordErrorMessage = "Incorrect email or password"
+const IncorrectCurrentPasswordErrorMessage = "Incorrect current password"
+const ReauthRequiredErrorMessage = "Sign in again or enter the password to continue"
 const IncorrectPasswordErrorMessage = "New password should not be the same as the previous one"
 const DuplicateEmailErrorMessage = "Duplicate email"
 const InternalServerErrorMessage = "Internal server error"
 
 const UserNotFoundErrorMessage = "User not found"
+const SuspendedUserErrorMessage = "This account has been suspended"
+const ChangeOwnAccountErrorMessage = "Admins cannot change the role or suspension of their own account"
 
 // file
 const UploadToBucketErrorMessage = "Error uploading to bucket client"
 const DeleteFromBucketErrorMessage = "Error deleting from bucket client"
+const DownloadFromBucketErrorMessage = "Error downloading from bucket client"
 
 const ImageNotFoundErrorMessage = "Image not found"
 const CreateImageErrorMessage = "Error creating image in db"
@@ -37,3 +42,41 @@ const DeleteImageErrorMessage = "Error deleting image from db"
 const PrimaryKeyRequiredErrorMessage = "UUID Primary key (petId) required"
 const PetIdNotUUIDErrorMessage = "Pet id is not uuid"
 const PetIdNotFoundErrorMessage = "Pet id not found"
+
+// pet
+const InvalidImportFileErrorMessage = "Invalid import file, only csv and xlsx are supported"
+const EmptyImportFileErrorMessage = "Import file has no pet rows"
+const ImportPetErrorMessage = "Error importing pets"
+const InvalidExportFormatErrorMessage = "Invalid export format, only csv and pdf are supported"
+const ExportPetErrorMessage = "Error exporting pets"
+const TooManyPetsForPdfErrorMessage = "Too many pets for a pdf export, narrow the filter or export csv"
+
+// email
+const InvalidEmailTemplateErrorMessage = "Invalid email template"
+const InvalidLocaleErrorMessage = "Invalid locale, only th and en are supported"
+const InvalidEmailStatusErrorMessage = "Invalid email status, only pending, sent and dead are supported"
+const EmailNotFoundErrorMessage = "Email not found or not in the dead state"
+
+// throttle
+const TooManyRequestsErrorMessage = "Too many attempts, please try again later"
+const AccountLockedErrorMessage = "Too many failed sign in attempts, the account is temporarily locked"
+
+// two factor
+const TwoFactorAlreadyEnabledErrorMessage = "Two-factor authentication is already enabled"
+const TwoFactorNotEnrolledErrorMessage = "Two-factor authentication has not been enrolled"
+const TwoFactorNotEnabledErrorMessage = "Two-factor authentication is not enabled"
+const TwoFactorRequiredErrorMessage = "Two-factor authentication is required for admins"
+const InvalidTwoFactorCodeErrorMessage = "Invalid two-factor code"
+
+const OAuthProviderNotFoundErrorMessage = "OAuth provider not found"
+const InvalidOAuthStateErrorMessage = "Invalid or expired OAuth state"
+const OAuthProviderErrorMessage = "Cannot sign in with the OAuth provider"
+const OAuthEmailNotVerifiedErrorMessage = "The email of the OAuth account is not verified"
+
+const InvalidApiKeyErrorMessage = "Invalid API key"
+const ApiKeyScopeErrorMessage = "API key is not allowed to access this path"
+const ApiKeyNotFoundErrorMessage = "API key not found"
+const ApiKeyRateLimitErrorMessage = "API key rate limit exceeded"
+const RateLimitErrorMessage = "Too many requests, please try again later"
+
+const InvalidSecurityEventTypeErrorMessage = "Invalid security event type"
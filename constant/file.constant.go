@@ -6,3 +6,12 @@ var AllowContentType = map[string]struct{}{
 	"image/png":  {},
 	"image/gif":  {},
 }
+
+var AllowImportContentType = map[string]struct{}{
+	"text/csv":                 {},
+	"text/plain":               {},
+	"application/csv":          {},
+	"application/vnd.ms-excel": {},
+	"application/octet-stream": {},
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {},
+}
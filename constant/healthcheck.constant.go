@@ -0,0 +1,15 @@
+package constant
+
+type HealthStatus string
+
+const (
+	HealthStatusUp      HealthStatus = "up"
+	HealthStatusDown    HealthStatus = "down"
+	HealthStatusTimeout HealthStatus = "timeout"
+)
+
+const (
+	DatabaseDependency = "database"
+	RedisDependency    = "redis"
+	BucketDependency   = "bucket"
+)
@@ -20,6 +20,13 @@ const (
 	FINDHOME Status = "findhome"
 )
 
+type ExportFormat string
+
+const (
+	CSV ExportFormat = "csv"
+	PDF ExportFormat = "pdf"
+)
+
 func (g *Gender) UnmarshalJSON(data []byte) error {
 	var s string
 	if err := json.Unmarshal(data, &s); err != nil {
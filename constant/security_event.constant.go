@@ -0,0 +1,27 @@
+package constant
+
+type SecurityEventType string
+
+const (
+	SignInEvent          SecurityEventType = "signin"
+	SignInTwoFactorEvent SecurityEventType = "signin_2fa"
+	SignInOAuthEvent     SecurityEventType = "signin_oauth"
+	SignInMagicLinkEvent SecurityEventType = "signin_magic_link"
+	SignOutEvent         SecurityEventType = "signout"
+	MagicLinkEvent       SecurityEventType = "magic_link"
+	ForgotPasswordEvent  SecurityEventType = "forgot_password"
+	ResetPasswordEvent   SecurityEventType = "reset_password"
+	ChangePasswordEvent  SecurityEventType = "change_password"
+)
+
+var SecurityEventTypes = map[SecurityEventType]struct{}{
+	SignInEvent:          {},
+	SignInTwoFactorEvent: {},
+	SignInOAuthEvent:     {},
+	SignInMagicLinkEvent: {},
+	SignOutEvent:         {},
+	MagicLinkEvent:       {},
+	ForgotPasswordEvent:  {},
+	ResetPasswordEvent:   {},
+	ChangePasswordEvent:  {},
+}
@@ -0,0 +1,10 @@
+package constant
+
+type ThrottleAction string
+
+const (
+	SignInAction         ThrottleAction = "signin"
+	ForgotPasswordAction ThrottleAction = "forgot_password"
+	TwoFactorAction      ThrottleAction = "two_factor"
+	MagicLinkAction      ThrottleAction = "magic_link"
+)
@@ -4,6 +4,10 @@ const FindOneUserSuccessMessage = "find one user success"
 const UpdateUserSuccessMessage = "update user success"
 const DeleteUserSuccessMessage = "delete user success"
 
+// the domain is reserved so the email of a deleted account can never be delivered
+const AnonymizedEmailDomain = "deleted.invalid"
+const AnonymizedName = "Deleted"
+
 type Role string
 
 const (
@@ -0,0 +1,275 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// migrationLockId is the key of the postgres advisory lock held while migrating so only one instance migrates
+const migrationLockId = 7301826450
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// SchemaMigration is a row of the migration status table
+type SchemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	dir, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations reads the <version>_<name>.up.sql and <version>_<name>.down.sql pairs, ordered by version
+func LoadMigrations(dir fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(dir, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, errors.New(fmt.Sprintf("invalid migration file name %s", entry.Name()))
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, entry.Name())
+		}
+
+		content, err := fs.ReadFile(dir, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, errors.New(fmt.Sprintf("migration %d has two names, %s and %s", version, migration.Name, match[2]))
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var migrations []*Migration
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, errors.New(fmt.Sprintf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name))
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies the pending migrations in order and returns how many were applied
+func (m *Migrator) Up() (int, error) {
+	count := 0
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return errors.Wrapf(err, "migration %d_%s up", migration.Version, migration.Name)
+			}
+
+			log.Info().
+				Str("service", "database").
+				Str("module", "migrate").
+				Int64("version", migration.Version).
+				Str("name", migration.Name).
+				Msg("Applied migration")
+			count++
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Down rolls back the last steps applied migrations and returns how many were rolled back
+func (m *Migrator) Down(steps int) (int, error) {
+	count := 0
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&SchemaMigration{}, "version = ?", migration.Version).Error
+			})
+			if err != nil {
+				return errors.Wrapf(err, "migration %d_%s down", migration.Version, migration.Name)
+			}
+
+			log.Info().
+				Str("service", "database").
+				Str("module", "migrate").
+				Int64("version", migration.Version).
+				Str("name", migration.Name).
+				Msg("Rolled back migration")
+			count++
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Status lists every migration with when it was applied, versions applied by a newer release are listed too
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	var result []*MigrationStatus
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := &MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if row, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &row.AppliedAt
+				delete(applied, migration.Version)
+			}
+			result = append(result, status)
+		}
+
+		for _, row := range applied {
+			appliedAt := row.AppliedAt
+			result = append(result, &MigrationStatus{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt})
+		}
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].Version < result[j].Version
+		})
+
+		return nil
+	})
+
+	return result, err
+}
+
+// withLock runs fn on a single connection holding the advisory lock, the lock belongs to the session so the
+// connection cannot go back to the pool before it is released
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockId).Error; err != nil {
+			return errors.Wrap(err, "acquire migration lock")
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockId).Error; err != nil {
+				log.Error().
+					Err(err).
+					Str("service", "database").
+					Str("module", "migrate").
+					Msg("Error releasing migration lock")
+			}
+		}()
+
+		err := conn.Exec(`CREATE TABLE IF NOT EXISTS "schema_migrations" (
+			"version" bigint PRIMARY KEY,
+			"name" text,
+			"applied_at" timestamp
+		)`).Error
+		if err != nil {
+			return errors.Wrap(err, "create migration status table")
+		}
+
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]SchemaMigration, error) {
+	var rows []SchemaMigration
+	if err := conn.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := map[int64]SchemaMigration{}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "images";
+DROP TABLE IF EXISTS "pets";
+DROP TABLE IF EXISTS "auth_sessions";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,66 @@
+-- the tables were created by gorm AutoMigrate before versioned migrations, IF NOT EXISTS lets those databases
+-- adopt this migration without changes. This is the schema before versioned migrations, every column and table
+-- added since has its own migration with IF NOT EXISTS so a database that AutoMigrate already updated also adopts it
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" text,
+    "created_at" timestamp,
+    "updated_at" timestamp,
+    "deleted_at" timestamp,
+    "email" text,
+    "password" text,
+    "firstname" text,
+    "lastname" text,
+    "role" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "auth_sessions" (
+    "id" text,
+    "created_at" timestamp,
+    "updated_at" timestamp,
+    "deleted_at" timestamp,
+    "user_id" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_auth_sessions_deleted_at" ON "auth_sessions" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "pets" (
+    "id" text,
+    "created_at" timestamp,
+    "updated_at" timestamp,
+    "deleted_at" timestamp,
+    "type" text,
+    "name" text,
+    "birthdate" text,
+    "gender" text,
+    "color" text,
+    "habit" text,
+    "caption" text,
+    "status" text,
+    "is_sterile" boolean,
+    "is_vaccinated" boolean,
+    "is_visible" boolean,
+    "origin" text,
+    "owner" text,
+    "contact" text,
+    "tel" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_pets_deleted_at" ON "pets" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "images" (
+    "id" text,
+    "created_at" timestamp,
+    "updated_at" timestamp,
+    "deleted_at" timestamp,
+    "pet_id" text,
+    "image_url" text,
+    "object_key" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_images_pet" FOREIGN KEY ("pet_id") REFERENCES "pets" ("id") ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_images_deleted_at" ON "images" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_name" ON "images" ("pet_id");
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS "email_outboxes" (
+    "id" text,
+    "created_at" timestamp,
+    "updated_at" timestamp,
+    "deleted_at" timestamp,
+    "template" text,
+    "from_name" text,
+    "from_address" text,
+    "to_name" text,
+    "to_address" text,
+    "subject" text,
+    "text" text,
+    "html" text,
+    "status" text DEFAULT 'pending',
+    "attempts" bigint DEFAULT 0,
+    "next_attempt_at" timestamp,
+    "last_error" text,
+    "sent_at" timestamp,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_email_outboxes_next_attempt_at" ON "email_outboxes" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_email_outboxes_status" ON "email_outboxes" ("status");
+CREATE INDEX IF NOT EXISTS "idx_email_outboxes_deleted_at" ON "email_outboxes" ("deleted_at");
@@ -0,0 +1,16 @@
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_secret" text;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_enabled" boolean DEFAULT false;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_last_step" bigint DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "recovery_codes" (
+    "id" text,
+    "created_at" timestamp,
+    "updated_at" timestamp,
+    "deleted_at" timestamp,
+    "user_id" text,
+    "code_hash" text,
+    "used_at" timestamp,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_recovery_codes_user_id" ON "recovery_codes" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_recovery_codes_deleted_at" ON "recovery_codes" ("deleted_at");
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "o_auth_identities";
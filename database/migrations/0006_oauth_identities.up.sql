@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "o_auth_identities" (
+    "id" text,
+    "created_at" timestamp,
+    "updated_at" timestamp,
+    "deleted_at" timestamp,
+    "user_id" text,
+    "provider" varchar(32),
+    "subject" varchar(255),
+    "email" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_oauth_provider_subject" ON "o_auth_identities" ("provider", "subject");
+CREATE INDEX IF NOT EXISTS "idx_o_auth_identities_user_id" ON "o_auth_identities" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_o_auth_identities_deleted_at" ON "o_auth_identities" ("deleted_at");
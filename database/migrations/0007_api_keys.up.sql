@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS "api_keys" (
+    "id" text,
+    "created_at" timestamp,
+    "updated_at" timestamp,
+    "deleted_at" timestamp,
+    "name" text,
+    "prefix" text,
+    "key_hash" varchar(64),
+    "scopes" text,
+    "rate_limit" bigint,
+    "created_by" text,
+    "expires_at" timestamp,
+    "last_used_at" timestamp,
+    "revoked_at" timestamp,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_api_keys_key_hash" ON "api_keys" ("key_hash");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_deleted_at" ON "api_keys" ("deleted_at");
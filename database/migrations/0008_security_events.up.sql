@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS "security_events" (
+    "id" text,
+    "created_at" timestamp,
+    "updated_at" timestamp,
+    "deleted_at" timestamp,
+    "user_id" text,
+    "email" text,
+    "type" text,
+    "ip" text,
+    "user_agent" text,
+    "success" boolean,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_security_events_type" ON "security_events" ("type");
+CREATE INDEX IF NOT EXISTS "idx_security_events_user_id" ON "security_events" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_security_events_deleted_at" ON "security_events" ("deleted_at");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_security_events_created_at";
+DROP INDEX IF EXISTS "idx_security_events_user_id_created_at";
@@ -0,0 +1,3 @@
+-- the activity of a user is read newest first
+CREATE INDEX IF NOT EXISTS "idx_security_events_user_id_created_at" ON "security_events" ("user_id", "created_at" DESC);
+CREATE INDEX IF NOT EXISTS "idx_security_events_created_at" ON "security_events" ("created_at" DESC);
@@ -2,7 +2,8 @@ package database
 
 import (
 	"github.com/isd-sgcu/johnjud-backend/config"
-	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/metrics"
+	"github.com/isd-sgcu/johnjud-backend/internal/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
@@ -20,10 +21,23 @@ func InitPostgresDatabase(conf *config.Database, isDebug bool) (db *gorm.DB, err
 		return nil, err
 	}
 
-	err = db.AutoMigrate(&model.User{}, &model.AuthSession{}, &model.Pet{}, &model.Image{})
-	if err != nil {
+	if err := db.Use(metrics.NewGormPlugin()); err != nil {
+		return nil, err
+	}
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
 		return nil, err
 	}
 
 	return
 }
+
+// MigratePostgresDatabase applies the pending migrations, replicas starting together wait for the first one
+func MigratePostgresDatabase(db *gorm.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	_, err = migrator.Up()
+	return err
+}
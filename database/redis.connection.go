@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/internal/tracing"
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
 )
@@ -19,6 +20,7 @@ func InitRedisConnection(conf *config.Redis) (*redis.Client, error) {
 	if cache == nil {
 		return nil, errors.New("Failed to connect to redis server")
 	}
+	cache.AddHook(tracing.NewRedisHook())
 
 	return cache, nil
 }
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/isd-sgcu/johnjud-backend/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MigrateTest struct {
+	suite.Suite
+}
+
+func TestMigrate(t *testing.T) {
+	suite.Run(t, new(MigrateTest))
+}
+
+func (t *MigrateTest) TestEmbeddedMigrations() {
+	_, err := database.NewMigrator(nil)
+
+	assert.Nil(t.T(), err)
+}
+
+func (t *MigrateTest) TestLoadMigrationsOrdered() {
+	dir := fstest.MapFS{
+		"0010_add_index.up.sql":      {Data: []byte("CREATE INDEX;")},
+		"0010_add_index.down.sql":    {Data: []byte("DROP INDEX;")},
+		"0002_add_column.up.sql":     {Data: []byte("ALTER TABLE ADD;")},
+		"0002_add_column.down.sql":   {Data: []byte("ALTER TABLE DROP;")},
+		"0001_init.up.sql":           {Data: []byte("CREATE TABLE;")},
+		"0001_init.down.sql":         {Data: []byte("DROP TABLE;")},
+		"README.md":                  {Data: []byte("not a migration")},
+		"0003_nested/ignored.up.sql": {Data: []byte("")},
+	}
+
+	migrations, err := database.LoadMigrations(dir)
+
+	assert.Nil(t.T(), err)
+	assert.Len(t.T(), migrations, 3)
+	assert.Equal(t.T(), &database.Migration{Version: 1, Name: "init", Up: "CREATE TABLE;", Down: "DROP TABLE;"}, migrations[0])
+	assert.Equal(t.T(), int64(2), migrations[1].Version)
+	assert.Equal(t.T(), "add_column", migrations[1].Name)
+	assert.Equal(t.T(), int64(10), migrations[2].Version)
+}
+
+func (t *MigrateTest) TestLoadMigrationsMissingDown() {
+	dir := fstest.MapFS{
+		"0001_init.up.sql": {Data: []byte("CREATE TABLE;")},
+	}
+
+	migrations, err := database.LoadMigrations(dir)
+
+	assert.Nil(t.T(), migrations)
+	assert.ErrorContains(t.T(), err, "needs both an up and a down file")
+}
+
+func (t *MigrateTest) TestLoadMigrationsInvalidName() {
+	dir := fstest.MapFS{
+		"init.up.sql": {Data: []byte("CREATE TABLE;")},
+	}
+
+	migrations, err := database.LoadMigrations(dir)
+
+	assert.Nil(t.T(), migrations)
+	assert.ErrorContains(t.T(), err, "invalid migration file name")
+}
+
+func (t *MigrateTest) TestLoadMigrationsDuplicateVersion() {
+	dir := fstest.MapFS{
+		"0001_init.up.sql":    {Data: []byte("CREATE TABLE;")},
+		"0001_init.down.sql":  {Data: []byte("DROP TABLE;")},
+		"0001_other.up.sql":   {Data: []byte("CREATE TABLE;")},
+		"0001_other.down.sql": {Data: []byte("DROP TABLE;")},
+	}
+
+	migrations, err := database.LoadMigrations(dir)
+
+	assert.Nil(t.T(), migrations)
+	assert.ErrorContains(t.T(), err, "has two names")
+}
+
+// databases created by AutoMigrate already have some of the schema, so every statement must be safe to run again
+func (t *MigrateTest) TestMigrationsAreIdempotent() {
+	migrations, err := database.LoadMigrations(os.DirFS("../migrations"))
+	assert.Nil(t.T(), err)
+
+	for _, migration := range migrations {
+		for _, statement := range statements(migration.Up) {
+			assert.Contains(t.T(), statement, "IF NOT EXISTS", "%d_%s up", migration.Version, migration.Name)
+		}
+		for _, statement := range statements(migration.Down) {
+			assert.Contains(t.T(), statement, "IF EXISTS", "%d_%s down", migration.Version, migration.Name)
+		}
+	}
+}
+
+func statements(sql string) []string {
+	var lines []string
+	for _, line := range strings.Split(sql, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	var result []string
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			result = append(result, statement)
+		}
+	}
+
+	return result
+}
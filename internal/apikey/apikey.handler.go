@@ -0,0 +1,112 @@
+package apikey
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/router"
+	"github.com/isd-sgcu/johnjud-backend/internal/validator"
+)
+
+type Handler struct {
+	service  Service
+	validate validator.IDtoValidator
+}
+
+func NewHandler(service Service, validate validator.IDtoValidator) *Handler {
+	return &Handler{service, validate}
+}
+
+// FindAll is a function that returns all api keys
+// @Summary finds all api keys
+// @Description Returns the api keys without the keys themselves, newest first
+// @Tags auth
+// @Produce json
+// @Success 200 {object} []dto.ApiKey
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/admin/api-keys [get]
+func (h *Handler) FindAll(c router.IContext) {
+	response, respErr := h.service.FindAll(c.UserContext())
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Create is a function that mints a new api key
+// @Summary creates api key
+// @Description Returns the key in plain text, it is only shown once. Partners send it in the X-API-Key header
+// @Param request body dto.CreateApiKeyRequest true "create api key dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 201 {object} dto.CreateApiKeyResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/admin/api-keys [post]
+func (h *Handler) Create(c router.IContext) {
+	request := &dto.CreateApiKeyRequest{}
+	if err := c.Bind(request); err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.BindingRequestErrorMessage + err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
+		var errorMessage []string
+		for _, reqErr := range err {
+			errorMessage = append(errorMessage, reqErr.Message)
+		}
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.InvalidRequestBodyMessage + strings.Join(errorMessage, ", "),
+			Data:       nil,
+		})
+		return
+	}
+
+	response, respErr := h.service.Create(c.UserContext(), c.UserID(), request)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// Revoke is a function that revokes an api key
+// @Summary revokes api key
+// @Description Requests with the key are rejected from now on
+// @Param id path string true "api key id"
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.RevokeApiKeyResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid id"
+// @Failure 404 {object} dto.ResponseNotfoundErr "Api key not found or already revoked"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/admin/api-keys/{id} [delete]
+func (h *Handler) Revoke(c router.IContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequestError(constant.InvalidIDMessage))
+		return
+	}
+
+	response, respErr := h.service.Revoke(c.UserContext(), id)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,71 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindAll(ctx context.Context, apiKeys *[]*model.ApiKey) error
+	FindByHash(ctx context.Context, keyHash string, apiKey *model.ApiKey) error
+	Create(ctx context.Context, apiKey *model.ApiKey) error
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+	UpdateLastUsed(ctx context.Context, id string, usedAt time.Time, interval time.Duration) error
+	IsActiveAdmin(ctx context.Context, userId string) (bool, error)
+}
+
+type repositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repositoryImpl{Db: db}
+}
+
+func (r *repositoryImpl) FindAll(ctx context.Context, apiKeys *[]*model.ApiKey) error {
+	return r.Db.WithContext(ctx).Order("created_at desc").Find(apiKeys).Error
+}
+
+func (r *repositoryImpl) FindByHash(ctx context.Context, keyHash string, apiKey *model.ApiKey) error {
+	return r.Db.WithContext(ctx).First(apiKey, "key_hash = ?", keyHash).Error
+}
+
+func (r *repositoryImpl) Create(ctx context.Context, apiKey *model.ApiKey) error {
+	return r.Db.WithContext(ctx).Create(apiKey).Error
+}
+
+// Revoke returns ErrRecordNotFound when the key does not exist or is already revoked
+func (r *repositoryImpl) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	result := r.Db.WithContext(ctx).Model(&model.ApiKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// UpdateLastUsed only writes when the last use is older than the interval so busy keys do not update every request
+func (r *repositoryImpl) UpdateLastUsed(ctx context.Context, id string, usedAt time.Time, interval time.Duration) error {
+	return r.Db.WithContext(ctx).Model(&model.ApiKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-interval)).
+		Update("last_used_at", usedAt).Error
+}
+
+// IsActiveAdmin reports whether the user is an admin who is not suspended, deleted users are not found
+func (r *repositoryImpl) IsActiveAdmin(ctx context.Context, userId string) (bool, error) {
+	var count int64
+	err := r.Db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND role = ? AND is_suspended = ?", userId, constant.ADMIN, false).
+		Count(&count).Error
+
+	return count > 0, err
+}
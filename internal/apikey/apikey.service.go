@@ -0,0 +1,248 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/cache"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	keyPrefix    = "jj_"
+	prefixLength = 11
+	// rate limits are counted in fixed windows of a minute
+	rateLimitWindow = 60
+	// last used is written at most once per interval for each key
+	lastUsedInterval = time.Minute
+)
+
+type Service interface {
+	FindAll(ctx context.Context) ([]*dto.ApiKey, *dto.ResponseErr)
+	Create(ctx context.Context, createdBy string, request *dto.CreateApiKeyRequest) (*dto.CreateApiKeyResponse, *dto.ResponseErr)
+	Revoke(ctx context.Context, id string) (*dto.RevokeApiKeyResponse, *dto.ResponseErr)
+	Validate(ctx context.Context, key string) (*dto.ApiKeyPayload, *dto.ResponseErr)
+	Allow(ctx context.Context, payload *dto.ApiKeyPayload) (time.Duration, error)
+}
+
+type serviceImpl struct {
+	config     config.ApiKey
+	repository Repository
+	cache      cache.Repository
+}
+
+func NewService(config config.ApiKey, repository Repository, cache cache.Repository) Service {
+	return &serviceImpl{config: config, repository: repository, cache: cache}
+}
+
+func (s *serviceImpl) FindAll(ctx context.Context) ([]*dto.ApiKey, *dto.ResponseErr) {
+	var apiKeys []*model.ApiKey
+	if err := s.repository.FindAll(ctx, &apiKeys); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "api key").
+			Str("module", "find all").
+			Msg("Error finding api keys")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	result := make([]*dto.ApiKey, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		result = append(result, RawToDto(apiKey))
+	}
+
+	return result, nil
+}
+
+// Create returns the key in plain text, it cannot be read again afterwards
+func (s *serviceImpl) Create(ctx context.Context, createdBy string, request *dto.CreateApiKeyRequest) (*dto.CreateApiKeyResponse, *dto.ResponseErr) {
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, dto.BadRequestError(constant.InvalidRequestBodyMessage + "expires_at must be in the future")
+	}
+
+	creatorId, err := uuid.Parse(createdBy)
+	if err != nil {
+		return nil, dto.UnauthorizedError(constant.InvalidTokenErrorMessage)
+	}
+
+	key, err := GenerateKey()
+	if err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	rateLimit := request.RateLimit
+	if rateLimit == 0 {
+		rateLimit = s.config.DefaultRateLimit
+	}
+
+	var scopes []string
+	for _, scope := range request.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	apiKey := &model.ApiKey{
+		Name:      request.Name,
+		Prefix:    key[:prefixLength],
+		KeyHash:   HashKey(key),
+		Scopes:    strings.Join(scopes, " "),
+		RateLimit: rateLimit,
+		CreatedBy: creatorId,
+		ExpiresAt: request.ExpiresAt,
+	}
+	if err := s.repository.Create(ctx, apiKey); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "api key").
+			Str("module", "create").
+			Msg("Error creating api key")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return &dto.CreateApiKeyResponse{ApiKey: RawToDto(apiKey), Key: key}, nil
+}
+
+func (s *serviceImpl) Revoke(ctx context.Context, id string) (*dto.RevokeApiKeyResponse, *dto.ResponseErr) {
+	if err := s.repository.Revoke(ctx, id, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.NotFoundError(constant.ApiKeyNotFoundErrorMessage)
+		}
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "api key").
+			Str("module", "revoke").
+			Str("id", id).
+			Msg("Error revoking api key")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return &dto.RevokeApiKeyResponse{Success: true}, nil
+}
+
+// Validate rejects unknown, revoked and expired keys and the keys of an admin who was demoted, suspended
+// or deleted, and records the use of the key
+func (s *serviceImpl) Validate(ctx context.Context, key string) (*dto.ApiKeyPayload, *dto.ResponseErr) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, dto.UnauthorizedError(constant.InvalidApiKeyErrorMessage)
+	}
+
+	apiKey := &model.ApiKey{}
+	if err := s.repository.FindByHash(ctx, HashKey(key), apiKey); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.UnauthorizedError(constant.InvalidApiKeyErrorMessage)
+		}
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return nil, dto.UnauthorizedError(constant.InvalidApiKeyErrorMessage)
+	}
+
+	active, err := s.repository.IsActiveAdmin(ctx, apiKey.CreatedBy.String())
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "api key").
+			Str("module", "validate").
+			Str("id", apiKey.ID.String()).
+			Msg("Error finding creator of api key")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+	if !active {
+		return nil, dto.UnauthorizedError(constant.InvalidApiKeyErrorMessage)
+	}
+
+	if err := s.repository.UpdateLastUsed(ctx, apiKey.ID.String(), now, lastUsedInterval); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "api key").
+			Str("module", "validate").
+			Str("id", apiKey.ID.String()).
+			Msg("Error updating last used of api key")
+	}
+
+	return &dto.ApiKeyPayload{
+		Id:        apiKey.ID.String(),
+		CreatedBy: apiKey.CreatedBy.String(),
+		Scopes:    ParseScopes(apiKey.Scopes),
+		RateLimit: apiKey.RateLimit,
+	}, nil
+}
+
+// Allow counts the request of the key and returns how long to wait when the key is over its rate limit
+func (s *serviceImpl) Allow(ctx context.Context, payload *dto.ApiKeyPayload) (time.Duration, error) {
+	key := fmt.Sprintf("apikey:rate:%s", payload.Id)
+	count, err := s.cache.Increment(ctx, key, rateLimitWindow)
+	if err != nil {
+		return 0, err
+	}
+	if int(count) <= payload.RateLimit {
+		return 0, nil
+	}
+
+	ttl, err := s.cache.GetTTL(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+
+	return ttl, nil
+}
+
+func GenerateKey() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func ParseScopes(scopes string) []constant.ApiKeyScope {
+	var result []constant.ApiKeyScope
+	for _, scope := range strings.Fields(scopes) {
+		result = append(result, constant.ApiKeyScope(scope))
+	}
+
+	return result
+}
+
+func HasScope(payload *dto.ApiKeyPayload, scope constant.ApiKeyScope) bool {
+	for _, s := range payload.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func RawToDto(in *model.ApiKey) *dto.ApiKey {
+	return &dto.ApiKey{
+		Id:         in.ID.String(),
+		Name:       in.Name,
+		Prefix:     in.Prefix,
+		Scopes:     ParseScopes(in.Scopes),
+		RateLimit:  in.RateLimit,
+		CreatedBy:  in.CreatedBy.String(),
+		ExpiresAt:  in.ExpiresAt,
+		LastUsedAt: in.LastUsedAt,
+		RevokedAt:  in.RevokedAt,
+		CreatedAt:  in.CreatedAt,
+	}
+}
@@ -0,0 +1,221 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/apikey"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	mock_apikey "github.com/isd-sgcu/johnjud-backend/mocks/repository/apikey"
+	mock_cache "github.com/isd-sgcu/johnjud-backend/mocks/repository/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ApiKeyServiceTest struct {
+	suite.Suite
+	config  config.ApiKey
+	key     string
+	apiKey  *model.ApiKey
+	payload *dto.ApiKeyPayload
+}
+
+func TestApiKeyService(t *testing.T) {
+	suite.Run(t, new(ApiKeyServiceTest))
+}
+
+func (t *ApiKeyServiceTest) SetupTest() {
+	t.config = config.ApiKey{DefaultRateLimit: 600}
+	t.key, _ = apikey.GenerateKey()
+	t.apiKey = &model.ApiKey{
+		Base:      model.Base{ID: uuid.New()},
+		Name:      faker.Word(),
+		Prefix:    t.key[:11],
+		KeyHash:   apikey.HashKey(t.key),
+		Scopes:    "pets:read",
+		RateLimit: 60,
+		CreatedBy: uuid.New(),
+	}
+	t.payload = &dto.ApiKeyPayload{
+		Id:        t.apiKey.ID.String(),
+		CreatedBy: t.apiKey.CreatedBy.String(),
+		Scopes:    []constant.ApiKeyScope{constant.PetsReadScope},
+		RateLimit: 60,
+	}
+}
+
+func (t *ApiKeyServiceTest) TestCreateSuccess() {
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	var created *model.ApiKey
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apiKey *model.ApiKey) error {
+		created = apiKey
+		return nil
+	})
+
+	service := apikey.NewService(t.config, repo, cache)
+	actual, err := service.Create(context.Background(), t.apiKey.CreatedBy.String(), &dto.CreateApiKeyRequest{
+		Name:   "Partner shelter",
+		Scopes: []constant.ApiKeyScope{constant.PetsReadScope, constant.PetsWriteScope},
+	})
+
+	assert.Nil(t.T(), err)
+	assert.True(t.T(), strings.HasPrefix(actual.Key, "jj_"))
+	assert.Equal(t.T(), apikey.HashKey(actual.Key), created.KeyHash)
+	assert.NotContains(t.T(), created.KeyHash, actual.Key)
+	assert.Equal(t.T(), actual.Key[:11], actual.Prefix)
+	assert.Equal(t.T(), "pets:read pets:write", created.Scopes)
+	assert.Equal(t.T(), t.config.DefaultRateLimit, actual.RateLimit)
+}
+
+func (t *ApiKeyServiceTest) TestCreateExpiredInPast() {
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	expiresAt := time.Now().Add(-time.Hour)
+
+	service := apikey.NewService(t.config, repo, cache)
+	actual, err := service.Create(context.Background(), t.apiKey.CreatedBy.String(), &dto.CreateApiKeyRequest{
+		Name:      "Partner shelter",
+		Scopes:    []constant.ApiKeyScope{constant.PetsReadScope},
+		ExpiresAt: &expiresAt,
+	})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+}
+
+func (t *ApiKeyServiceTest) TestValidateSuccess() {
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	repo.EXPECT().FindByHash(gomock.Any(), apikey.HashKey(t.key), &model.ApiKey{}).SetArg(2, *t.apiKey).Return(nil)
+	repo.EXPECT().IsActiveAdmin(gomock.Any(), t.apiKey.CreatedBy.String()).Return(true, nil)
+	repo.EXPECT().UpdateLastUsed(gomock.Any(), t.apiKey.ID.String(), gomock.Any(), time.Minute).Return(nil)
+
+	service := apikey.NewService(t.config, repo, cache)
+	actual, err := service.Validate(context.Background(), t.key)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), t.payload, actual)
+}
+
+func (t *ApiKeyServiceTest) TestValidateCreatorNotActiveAdmin() {
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	repo.EXPECT().FindByHash(gomock.Any(), apikey.HashKey(t.key), &model.ApiKey{}).SetArg(2, *t.apiKey).Return(nil)
+	repo.EXPECT().IsActiveAdmin(gomock.Any(), t.apiKey.CreatedBy.String()).Return(false, nil)
+
+	service := apikey.NewService(t.config, repo, cache)
+	actual, err := service.Validate(context.Background(), t.key)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusUnauthorized, err.StatusCode)
+}
+
+func (t *ApiKeyServiceTest) TestValidateUnknownKey() {
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	repo.EXPECT().FindByHash(gomock.Any(), apikey.HashKey(t.key), &model.ApiKey{}).Return(gorm.ErrRecordNotFound)
+
+	service := apikey.NewService(t.config, repo, cache)
+	actual, err := service.Validate(context.Background(), t.key)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusUnauthorized, err.StatusCode)
+}
+
+func (t *ApiKeyServiceTest) TestValidateRevoked() {
+	revokedAt := time.Now().Add(-time.Minute)
+	t.apiKey.RevokedAt = &revokedAt
+
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	repo.EXPECT().FindByHash(gomock.Any(), apikey.HashKey(t.key), &model.ApiKey{}).SetArg(2, *t.apiKey).Return(nil)
+
+	service := apikey.NewService(t.config, repo, cache)
+	actual, err := service.Validate(context.Background(), t.key)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), constant.InvalidApiKeyErrorMessage, err.Message)
+}
+
+func (t *ApiKeyServiceTest) TestValidateExpired() {
+	expiresAt := time.Now().Add(-time.Minute)
+	t.apiKey.ExpiresAt = &expiresAt
+
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	repo.EXPECT().FindByHash(gomock.Any(), apikey.HashKey(t.key), &model.ApiKey{}).SetArg(2, *t.apiKey).Return(nil)
+
+	service := apikey.NewService(t.config, repo, cache)
+	actual, err := service.Validate(context.Background(), t.key)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusUnauthorized, err.StatusCode)
+}
+
+func (t *ApiKeyServiceTest) TestAllowUnderLimit() {
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().Increment(gomock.Any(), "apikey:rate:"+t.payload.Id, 60).Return(int64(60), nil)
+
+	service := apikey.NewService(t.config, repo, cache)
+	wait, err := service.Allow(context.Background(), t.payload)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), time.Duration(0), wait)
+}
+
+func (t *ApiKeyServiceTest) TestAllowOverLimit() {
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().Increment(gomock.Any(), "apikey:rate:"+t.payload.Id, 60).Return(int64(61), nil)
+	cache.EXPECT().GetTTL(gomock.Any(), "apikey:rate:"+t.payload.Id).Return(12*time.Second, nil)
+
+	service := apikey.NewService(t.config, repo, cache)
+	wait, err := service.Allow(context.Background(), t.payload)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), 12*time.Second, wait)
+}
+
+func (t *ApiKeyServiceTest) TestRevokeNotFound() {
+	controller := gomock.NewController(t.T())
+	repo := mock_apikey.NewMockRepository(controller)
+	cache := mock_cache.NewMockRepository(controller)
+
+	repo.EXPECT().Revoke(gomock.Any(), t.apiKey.ID.String(), gomock.Any()).Return(gorm.ErrRecordNotFound)
+
+	service := apikey.NewService(t.config, repo, cache)
+	actual, err := service.Revoke(context.Background(), t.apiKey.ID.String())
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
+}
@@ -46,7 +46,7 @@ func (h *handlerImpl) Signup(c router.IContext) {
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
 		var errorMessage []string
 		for _, reqErr := range err {
 			errorMessage = append(errorMessage, reqErr.Message)
@@ -59,7 +59,7 @@ func (h *handlerImpl) Signup(c router.IContext) {
 		return
 	}
 
-	response, respErr := h.service.Signup(request)
+	response, respErr := h.service.Signup(c.UserContext(), request)
 	if respErr != nil {
 		c.JSON(respErr.StatusCode, respErr)
 		return
@@ -70,14 +70,15 @@ func (h *handlerImpl) Signup(c router.IContext) {
 
 // SignIn is a function that authenticate user with email and password
 // @Summary Sign in user
-// @Description Return the credential of user including access token and refresh token
+// @Description Return the credential of user including access token and refresh token, or a challenge token when the user has two factor authentication enabled
 // @Param signIn body dto.SignInRequest true "signIn request dto"
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Success 201 {object} dto.Credential
+// @Success 201 {object} dto.SignInResponse
 // @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
 // @Failure 403 {object} dto.ResponseForbiddenErr "Incorrect email or password"
+// @Failure 429 {object} dto.ResponseTooManyRequestsErr "Too many failed attempts, see the Retry-After header"
 // @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
 // @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
 // @Router /v1/auth/signin [post]
@@ -93,7 +94,103 @@ func (h *handlerImpl) SignIn(c router.IContext) {
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
+		var errorMessage []string
+		for _, reqErr := range err {
+			errorMessage = append(errorMessage, reqErr.Message)
+		}
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.InvalidRequestBodyMessage + strings.Join(errorMessage, ", "),
+			Data:       nil,
+		})
+		return
+	}
+
+	response, respErr := h.service.SignIn(c.UserContext(), request, router.RequestMeta(c))
+	if respErr != nil {
+		router.JSONError(c, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SignInTwoFactor is a function that finishes the sign in of a user with two factor authentication
+// @Summary Sign in user with two factor code
+// @Description Return the credential of user when the totp or recovery code matches the challenge from sign in
+// @Param signIn body dto.SignInTwoFactorRequest true "signIn two factor request dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 201 {object} dto.Credential
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 401 {object} dto.ResponseUnauthorizedErr "Invalid challenge token or code"
+// @Failure 429 {object} dto.ResponseTooManyRequestsErr "Too many failed attempts, see the Retry-After header"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/signin/2fa [post]
+func (h *handlerImpl) SignInTwoFactor(c router.IContext) {
+	request := &dto.SignInTwoFactorRequest{}
+	err := c.Bind(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.BindingRequestErrorMessage + err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
+		var errorMessage []string
+		for _, reqErr := range err {
+			errorMessage = append(errorMessage, reqErr.Message)
+		}
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.InvalidRequestBodyMessage + strings.Join(errorMessage, ", "),
+			Data:       nil,
+		})
+		return
+	}
+
+	response, respErr := h.service.SignInTwoFactor(c.UserContext(), request, router.RequestMeta(c))
+	if respErr != nil {
+		router.JSONError(c, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SignInOAuth is a function that finishes the sign in with an oauth provider
+// @Summary Sign in user with oauth provider
+// @Description Return the credential of the user linked to the provider account by verified email, a new user is signed up when none has the email. A challenge token is returned instead when the user has two factor authentication enabled
+// @Param signIn body dto.OAuthCallbackRequest true "oauth callback request dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 201 {object} dto.SignInResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 401 {object} dto.ResponseUnauthorizedErr "Invalid state or code"
+// @Failure 403 {object} dto.ResponseForbiddenErr "Email not verified by the provider"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/oauth/callback [post]
+func (h *handlerImpl) SignInOAuth(c router.IContext) {
+	request := &dto.OAuthCallbackRequest{}
+	err := c.Bind(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.BindingRequestErrorMessage + err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
 		var errorMessage []string
 		for _, reqErr := range err {
 			errorMessage = append(errorMessage, reqErr.Message)
@@ -106,7 +203,102 @@ func (h *handlerImpl) SignIn(c router.IContext) {
 		return
 	}
 
-	response, respErr := h.service.SignIn(request)
+	response, respErr := h.service.SignInOAuth(c.UserContext(), request, router.RequestMeta(c))
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RequestMagicLink is a function that emails a passwordless sign in link
+// @Summary Request magic link
+// @Description Send a single use sign in link to the email, the response is the same when no user has the email
+// @Param request body dto.MagicLinkRequest true "magic link request dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.MagicLinkResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 429 {object} dto.ResponseTooManyRequestsErr "Too many requests, see the Retry-After header"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/magic-link [post]
+func (h *handlerImpl) RequestMagicLink(c router.IContext) {
+	request := &dto.MagicLinkRequest{}
+	err := c.Bind(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.BindingRequestErrorMessage + err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
+		var errorMessage []string
+		for _, reqErr := range err {
+			errorMessage = append(errorMessage, reqErr.Message)
+		}
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.InvalidRequestBodyMessage + strings.Join(errorMessage, ", "),
+			Data:       nil,
+		})
+		return
+	}
+
+	response, respErr := h.service.RequestMagicLink(c.UserContext(), request, router.RequestMeta(c))
+	if respErr != nil {
+		router.JSONError(c, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SignInMagicLink is a function that signs in user with the token of a magic link
+// @Summary Sign in user with magic link
+// @Description Return the credential of user the same way as sign in, or a challenge token when the user has two factor authentication enabled. The token can only be used once
+// @Param request body dto.SignInMagicLinkRequest true "signIn magic link request dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.SignInResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 401 {object} dto.ResponseUnauthorizedErr "Invalid or used token"
+// @Failure 403 {object} dto.ResponseForbiddenErr "User is suspended"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/signin/magic-link [post]
+func (h *handlerImpl) SignInMagicLink(c router.IContext) {
+	request := &dto.SignInMagicLinkRequest{}
+	err := c.Bind(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.BindingRequestErrorMessage + err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
+		var errorMessage []string
+		for _, reqErr := range err {
+			errorMessage = append(errorMessage, reqErr.Message)
+		}
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.InvalidRequestBodyMessage + strings.Join(errorMessage, ", "),
+			Data:       nil,
+		})
+		return
+	}
+
+	response, respErr := h.service.SignInMagicLink(c.UserContext(), request, router.RequestMeta(c))
 	if respErr != nil {
 		c.JSON(respErr.StatusCode, respErr)
 		return
@@ -129,7 +321,7 @@ func (h *handlerImpl) SignIn(c router.IContext) {
 func (h *handlerImpl) SignOut(c router.IContext) {
 	token := c.Token()
 
-	response, respErr := h.service.SignOut(token)
+	response, respErr := h.service.SignOut(c.UserContext(), token, router.RequestMeta(c))
 	if respErr != nil {
 		c.JSON(respErr.StatusCode, respErr)
 		return
@@ -163,7 +355,7 @@ func (h *handlerImpl) RefreshToken(c router.IContext) {
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
 		var errorMessage []string
 		for _, reqErr := range err {
 			errorMessage = append(errorMessage, reqErr.Message)
@@ -176,7 +368,7 @@ func (h *handlerImpl) RefreshToken(c router.IContext) {
 		return
 	}
 
-	response, respErr := h.service.RefreshToken(request)
+	response, respErr := h.service.RefreshToken(c.UserContext(), request)
 	if respErr != nil {
 		c.JSON(respErr.StatusCode, respErr)
 		return
@@ -194,6 +386,7 @@ func (h *handlerImpl) RefreshToken(c router.IContext) {
 // @Produce json
 // @Success 200 {object} dto.ForgotPasswordResponse
 // @Failure 400 {object} dto.ResponseBadRequestErr "Invalid email"
+// @Failure 429 {object} dto.ResponseTooManyRequestsErr "Too many requests, see the Retry-After header"
 // @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
 // @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
 // @Router /v1/auth/forgot-password [post]
@@ -209,7 +402,7 @@ func (h *handlerImpl) ForgotPassword(c router.IContext) {
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
 		var errorMessage []string
 		for _, reqErr := range err {
 			errorMessage = append(errorMessage, reqErr.Message)
@@ -222,9 +415,9 @@ func (h *handlerImpl) ForgotPassword(c router.IContext) {
 		return
 	}
 
-	response, respErr := h.service.ForgotPassword(request)
+	response, respErr := h.service.ForgotPassword(c.UserContext(), request, router.RequestMeta(c))
 	if respErr != nil {
-		c.JSON(respErr.StatusCode, respErr)
+		router.JSONError(c, respErr)
 		return
 	}
 
@@ -255,7 +448,7 @@ func (h *handlerImpl) ResetPassword(c router.IContext) {
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
 		var errorMessage []string
 		for _, reqErr := range err {
 			errorMessage = append(errorMessage, reqErr.Message)
@@ -268,7 +461,7 @@ func (h *handlerImpl) ResetPassword(c router.IContext) {
 		return
 	}
 
-	response, errResp := h.service.ResetPassword(request)
+	response, errResp := h.service.ResetPassword(c.UserContext(), request, router.RequestMeta(c))
 	if errResp != nil {
 		c.JSON(errResp.StatusCode, errResp)
 		return
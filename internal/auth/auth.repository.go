@@ -1,13 +1,15 @@
 package auth
 
 import (
+	"context"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
 	"gorm.io/gorm"
 )
 
 type Repository interface {
-	Create(auth *model.AuthSession) error
-	Delete(id string) error
+	FindById(ctx context.Context, id string, auth *model.AuthSession) error
+	Create(ctx context.Context, auth *model.AuthSession) error
+	Delete(ctx context.Context, id string) error
 }
 
 type repositoryImpl struct {
@@ -18,10 +20,14 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repositoryImpl{Db: db}
 }
 
-func (r *repositoryImpl) Create(auth *model.AuthSession) error {
-	return r.Db.Create(auth).Error
+func (r *repositoryImpl) FindById(ctx context.Context, id string, auth *model.AuthSession) error {
+	return r.Db.WithContext(ctx).First(auth, "id = ?", id).Error
 }
 
-func (r *repositoryImpl) Delete(id string) error {
-	return r.Db.Delete(&model.AuthSession{}, "id = ?", id).Error
+func (r *repositoryImpl) Create(ctx context.Context, auth *model.AuthSession) error {
+	return r.Db.WithContext(ctx).Create(auth).Error
+}
+
+func (r *repositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.Db.WithContext(ctx).Delete(&model.AuthSession{}, "id = ?", id).Error
 }
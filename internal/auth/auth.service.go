@@ -1,13 +1,19 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/isd-sgcu/johnjud-backend/config"
 	"github.com/isd-sgcu/johnjud-backend/constant"
 	"github.com/isd-sgcu/johnjud-backend/internal/auth/email"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/oauth"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/securityevent"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/throttle"
 	"github.com/isd-sgcu/johnjud-backend/internal/auth/token"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/twofactor"
 	"github.com/isd-sgcu/johnjud-backend/internal/dto"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
 	"github.com/isd-sgcu/johnjud-backend/internal/user"
@@ -21,37 +27,49 @@ import (
 )
 
 type Service interface {
-	Validate(refreshToken string) (*dto.TokenPayloadAuth, *dto.ResponseErr)
-	RefreshToken(request *dto.RefreshTokenRequest) (*dto.Credential, *dto.ResponseErr)
-	Signup(request *dto.SignupRequest) (*dto.SignupResponse, *dto.ResponseErr)
-	SignIn(request *dto.SignInRequest) (*dto.Credential, *dto.ResponseErr)
-	SignOut(accessToken string) (*dto.SignOutResponse, *dto.ResponseErr)
-	ForgotPassword(request *dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, *dto.ResponseErr)
-	ResetPassword(request *dto.ResetPasswordRequest) (*dto.ResetPasswordResponse, *dto.ResponseErr)
+	Validate(ctx context.Context, refreshToken string) (*dto.TokenPayloadAuth, *dto.ResponseErr)
+	RefreshToken(ctx context.Context, request *dto.RefreshTokenRequest) (*dto.Credential, *dto.ResponseErr)
+	Signup(ctx context.Context, request *dto.SignupRequest) (*dto.SignupResponse, *dto.ResponseErr)
+	SignIn(ctx context.Context, request *dto.SignInRequest, meta *dto.RequestMeta) (*dto.SignInResponse, *dto.ResponseErr)
+	SignInTwoFactor(ctx context.Context, request *dto.SignInTwoFactorRequest, meta *dto.RequestMeta) (*dto.Credential, *dto.ResponseErr)
+	SignInOAuth(ctx context.Context, request *dto.OAuthCallbackRequest, meta *dto.RequestMeta) (*dto.SignInResponse, *dto.ResponseErr)
+	RequestMagicLink(ctx context.Context, request *dto.MagicLinkRequest, meta *dto.RequestMeta) (*dto.MagicLinkResponse, *dto.ResponseErr)
+	SignInMagicLink(ctx context.Context, request *dto.SignInMagicLinkRequest, meta *dto.RequestMeta) (*dto.SignInResponse, *dto.ResponseErr)
+	SignOut(ctx context.Context, accessToken string, meta *dto.RequestMeta) (*dto.SignOutResponse, *dto.ResponseErr)
+	ForgotPassword(ctx context.Context, request *dto.ForgotPasswordRequest, meta *dto.RequestMeta) (*dto.ForgotPasswordResponse, *dto.ResponseErr)
+	ResetPassword(ctx context.Context, request *dto.ResetPasswordRequest, meta *dto.RequestMeta) (*dto.ResetPasswordResponse, *dto.ResponseErr)
 }
 
 type serviceImpl struct {
-	authRepo     Repository
-	userRepo     user.Repository
-	tokenService token.Service
-	emailService email.Service
-	bcryptUtil   utils.IBcryptUtil
-	config       config.Auth
+	authRepo             Repository
+	userRepo             user.Repository
+	tokenService         token.Service
+	emailService         email.Service
+	throttleService      throttle.Service
+	twoFactorService     twofactor.Service
+	oauthService         oauth.Service
+	securityEventService securityevent.Service
+	bcryptUtil           utils.IBcryptUtil
+	config               config.Auth
 }
 
-func NewService(authRepo Repository, userRepo user.Repository, tokenService token.Service, emailService email.Service, bcryptUtil utils.IBcryptUtil, config config.Auth) Service {
+func NewService(authRepo Repository, userRepo user.Repository, tokenService token.Service, emailService email.Service, throttleService throttle.Service, twoFactorService twofactor.Service, oauthService oauth.Service, securityEventService securityevent.Service, bcryptUtil utils.IBcryptUtil, config config.Auth) Service {
 	return &serviceImpl{
-		authRepo:     authRepo,
-		userRepo:     userRepo,
-		tokenService: tokenService,
-		emailService: emailService,
-		bcryptUtil:   bcryptUtil,
-		config:       config,
+		authRepo:             authRepo,
+		userRepo:             userRepo,
+		tokenService:         tokenService,
+		emailService:         emailService,
+		throttleService:      throttleService,
+		twoFactorService:     twoFactorService,
+		oauthService:         oauthService,
+		securityEventService: securityEventService,
+		bcryptUtil:           bcryptUtil,
+		config:               config,
 	}
 }
 
-func (s *serviceImpl) Validate(refreshToken string) (*dto.TokenPayloadAuth, *dto.ResponseErr) {
-	userCredential, err := s.tokenService.Validate(refreshToken)
+func (s *serviceImpl) Validate(ctx context.Context, refreshToken string) (*dto.TokenPayloadAuth, *dto.ResponseErr) {
+	userCredential, err := s.tokenService.Validate(ctx, refreshToken)
 	if err != nil {
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusUnauthorized,
@@ -60,13 +78,14 @@ func (s *serviceImpl) Validate(refreshToken string) (*dto.TokenPayloadAuth, *dto
 	}
 
 	return &dto.TokenPayloadAuth{
-		UserId: userCredential.UserID,
-		Role:   string(userCredential.Role),
+		UserId:        userCredential.UserID,
+		Role:          string(userCredential.Role),
+		AuthSessionId: userCredential.AuthSessionID,
 	}, nil
 }
 
-func (s *serviceImpl) RefreshToken(request *dto.RefreshTokenRequest) (*dto.Credential, *dto.ResponseErr) {
-	refreshTokenCache, err := s.tokenService.FindRefreshTokenCache(request.RefreshToken)
+func (s *serviceImpl) RefreshToken(ctx context.Context, request *dto.RefreshTokenRequest) (*dto.Credential, *dto.ResponseErr) {
+	refreshTokenCache, err := s.tokenService.FindRefreshTokenCache(ctx, request.RefreshToken)
 	if err != nil {
 		st, _ := status.FromError(err)
 		switch st.Code() {
@@ -76,12 +95,22 @@ func (s *serviceImpl) RefreshToken(request *dto.RefreshTokenRequest) (*dto.Crede
 			return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 		}
 	}
-	credential, err := s.tokenService.CreateCredential(refreshTokenCache.UserID, refreshTokenCache.Role, refreshTokenCache.AuthSessionID)
+
+	// the auth session is deleted when the sessions of a user are revoked
+	err = s.authRepo.FindById(ctx, refreshTokenCache.AuthSessionID, &model.AuthSession{})
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.UnauthorizedError(constant.InvalidTokenErrorMessage)
+		}
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
-	err = s.tokenService.RemoveRefreshTokenCache(request.RefreshToken)
+	credential, err := s.tokenService.CreateCredential(ctx, refreshTokenCache.UserID, refreshTokenCache.Role, refreshTokenCache.AuthSessionID)
+	if err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	err = s.tokenService.RemoveRefreshTokenCache(ctx, request.RefreshToken)
 	if err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
@@ -89,7 +118,7 @@ func (s *serviceImpl) RefreshToken(request *dto.RefreshTokenRequest) (*dto.Crede
 	return credential, nil
 }
 
-func (s *serviceImpl) Signup(request *dto.SignupRequest) (*dto.SignupResponse, *dto.ResponseErr) {
+func (s *serviceImpl) Signup(ctx context.Context, request *dto.SignupRequest) (*dto.SignupResponse, *dto.ResponseErr) {
 	hashPassword, err := s.bcryptUtil.GenerateHashedPassword(request.Password)
 	if err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
@@ -101,8 +130,9 @@ func (s *serviceImpl) Signup(request *dto.SignupRequest) (*dto.SignupResponse, *
 		Firstname: request.Firstname,
 		Lastname:  request.Lastname,
 		Role:      constant.USER,
+		Locale:    request.Locale,
 	}
-	err = s.userRepo.Create(createUser)
+	err = s.userRepo.Create(ctx, createUser)
 	if err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			return nil, dto.ConflictError(constant.DuplicateEmailErrorMessage)
@@ -118,37 +148,252 @@ func (s *serviceImpl) Signup(request *dto.SignupRequest) (*dto.SignupResponse, *
 	}, nil
 }
 
-func (s *serviceImpl) SignIn(request *dto.SignInRequest) (*dto.Credential, *dto.ResponseErr) {
+func (s *serviceImpl) SignIn(ctx context.Context, request *dto.SignInRequest, meta *dto.RequestMeta) (*dto.SignInResponse, *dto.ResponseErr) {
+	if apperr := s.checkThrottle(ctx, constant.SignInAction, request.Email, meta); apperr != nil {
+		return nil, apperr
+	}
+
 	user := &model.User{}
-	err := s.userRepo.FindByEmail(request.Email, user)
+	err := s.userRepo.FindByEmail(ctx, request.Email, user)
 	if err != nil {
-		return nil, dto.UnauthorizedError(constant.IncorrectEmailPasswordErrorMessage)
+		// unknown emails count too so they cannot be told apart from wrong passwords
+		return nil, s.failSignIn(ctx, request.Email, nil, meta)
 	}
 
 	err = s.bcryptUtil.CompareHashedPassword(user.Password, request.Password)
 	if err != nil {
-		return nil, dto.UnauthorizedError(constant.IncorrectEmailPasswordErrorMessage)
+		return nil, s.failSignIn(ctx, request.Email, user, meta)
+	}
+
+	if err := s.throttleService.Reset(ctx, constant.SignInAction, request.Email); err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", "signin").
+			Msg("Error resetting sign in failures")
+	}
+
+	return s.completeSignIn(ctx, user, constant.SignInEvent, meta, "signin")
+}
+
+// SignInOAuth signs in the user linked to the account of the oauth provider, 2fa is still required when enabled
+func (s *serviceImpl) SignInOAuth(ctx context.Context, request *dto.OAuthCallbackRequest, meta *dto.RequestMeta) (*dto.SignInResponse, *dto.ResponseErr) {
+	user, apperr := s.oauthService.Authenticate(ctx, request)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	return s.completeSignIn(ctx, user, constant.SignInOAuthEvent, meta, "signin oauth")
+}
+
+// RequestMagicLink emails a single use sign in link, unknown emails get the same response so they cannot be found out
+func (s *serviceImpl) RequestMagicLink(ctx context.Context, request *dto.MagicLinkRequest, meta *dto.RequestMeta) (*dto.MagicLinkResponse, *dto.ResponseErr) {
+	if apperr := s.checkThrottle(ctx, constant.MagicLinkAction, request.Email, meta); apperr != nil {
+		return nil, apperr
+	}
+
+	// every request counts since each one sends an email, the count is reset when a link is used to sign in
+	// so requests made in the name of the user cannot keep them from getting a new link
+	if _, err := s.throttleService.Hit(ctx, constant.MagicLinkAction, request.Email, meta.IP); err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", "magic link").
+			Msg("Error counting magic link request")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	user := &model.User{}
+	err := s.userRepo.FindByEmail(ctx, request.Email, user)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.securityEventService.Record(ctx, constant.MagicLinkEvent, "", request.Email, meta, false)
+			return &dto.MagicLinkResponse{IsSuccess: true}, nil
+		}
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	magicLinkToken, err := s.tokenService.CreateMagicLinkToken(ctx, user.ID.String())
+	if err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	recipient := &email.Recipient{Name: user.Firstname, Address: user.Email, Locale: user.Locale}
+	data := &email.LinkData{
+		Name: user.Firstname,
+		URL:  fmt.Sprintf("%s/signin/magic-link/%s", s.config.ClientURL, magicLinkToken),
+	}
+	if err := s.emailService.Send(ctx, constant.MagicLinkTemplate, recipient, data); err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
+	s.securityEventService.Record(ctx, constant.MagicLinkEvent, user.ID.String(), user.Email, meta, true)
 
+	return &dto.MagicLinkResponse{IsSuccess: true}, nil
+}
+
+// SignInMagicLink uses up the token of the link and signs the user in the same way as SignIn
+func (s *serviceImpl) SignInMagicLink(ctx context.Context, request *dto.SignInMagicLinkRequest, meta *dto.RequestMeta) (*dto.SignInResponse, *dto.ResponseErr) {
+	tokenCache, err := s.tokenService.ConsumeMagicLinkToken(ctx, request.Token)
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.InvalidArgument:
+			return nil, dto.UnauthorizedError(constant.InvalidTokenErrorMessage)
+		default:
+			return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+	}
+
+	user := &model.User{}
+	if err := s.userRepo.FindById(ctx, tokenCache.UserID, user); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.UnauthorizedError(constant.InvalidTokenErrorMessage)
+		}
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	if err := s.throttleService.Reset(ctx, constant.MagicLinkAction, user.Email); err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", "signin magic link").
+			Msg("Error resetting magic link requests")
+	}
+
+	return s.completeSignIn(ctx, user, constant.SignInMagicLinkEvent, meta, "signin magic link")
+}
+
+// completeSignIn issues the credential once the user is authenticated, or the challenge when the user has 2fa.
+// The event is recorded once the credential is issued, the 2fa step records its own
+func (s *serviceImpl) completeSignIn(ctx context.Context, user *model.User, event constant.SecurityEventType, meta *dto.RequestMeta, module string) (*dto.SignInResponse, *dto.ResponseErr) {
+	if user.IsSuspended {
+		s.securityEventService.Record(ctx, event, user.ID.String(), user.Email, meta, false)
+		return nil, dto.ForbiddenError(constant.SuspendedUserErrorMessage)
+	}
+
+	if user.TotpEnabled {
+		challengeToken, err := s.tokenService.CreateTwoFactorChallenge(ctx, user.ID.String())
+		if err != nil {
+			log.Error().
+				Ctx(ctx).
+				Err(err).
+				Str("service", "auth").
+				Str("module", module).
+				Msg("Error creating two factor challenge")
+			return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+
+		return &dto.SignInResponse{TwoFactorRequired: true, ChallengeToken: challengeToken}, nil
+	}
+
+	// admins without 2fa only get the user role so they can enroll but not use the admin endpoints
+	role := user.Role
+	setupRequired := role == constant.ADMIN && s.config.RequireAdminTwoFactor
+	if setupRequired {
+		role = constant.USER
+	}
+
+	credential, apperr := s.createCredential(ctx, user, role, module)
+	if apperr != nil {
+		return nil, apperr
+	}
+	s.securityEventService.Record(ctx, event, user.ID.String(), user.Email, meta, true)
+
+	return &dto.SignInResponse{Credential: credential, TwoFactorSetupRequired: setupRequired}, nil
+}
+
+// SignInTwoFactor finishes the sign in of a user with 2fa, the challenge is removed once the code is accepted
+func (s *serviceImpl) SignInTwoFactor(ctx context.Context, request *dto.SignInTwoFactorRequest, meta *dto.RequestMeta) (*dto.Credential, *dto.ResponseErr) {
+	challenge, err := s.tokenService.FindTwoFactorChallenge(ctx, request.ChallengeToken)
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.InvalidArgument:
+			return nil, dto.UnauthorizedError(constant.InvalidTokenErrorMessage)
+		default:
+			return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+	}
+
+	user := &model.User{}
+	if err := s.userRepo.FindById(ctx, challenge.UserID, user); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.UnauthorizedError(constant.InvalidTokenErrorMessage)
+		}
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	if apperr := s.checkThrottle(ctx, constant.TwoFactorAction, user.Email, meta); apperr != nil {
+		return nil, apperr
+	}
+
+	ok, err := s.twoFactorService.Verify(ctx, user, request.Code)
+	if err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+	if !ok {
+		s.securityEventService.Record(ctx, constant.SignInTwoFactorEvent, user.ID.String(), user.Email, meta, false)
+		result, err := s.throttleService.Hit(ctx, constant.TwoFactorAction, user.Email, meta.IP)
+		if err != nil {
+			return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+		if result.Locked || result.IpLocked {
+			// the password has to be entered again after the lockout
+			s.removeTwoFactorChallenge(ctx, request.ChallengeToken)
+			return nil, dto.TooManyRequestsError(constant.TooManyRequestsErrorMessage, result.RetryAfter)
+		}
+		return nil, dto.UnauthorizedError(constant.InvalidTwoFactorCodeErrorMessage)
+	}
+
+	if err := s.throttleService.Reset(ctx, constant.TwoFactorAction, user.Email); err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", "signin two factor").
+			Msg("Error resetting two factor failures")
+	}
+	s.removeTwoFactorChallenge(ctx, request.ChallengeToken)
+
+	if user.IsSuspended {
+		s.securityEventService.Record(ctx, constant.SignInTwoFactorEvent, user.ID.String(), user.Email, meta, false)
+		return nil, dto.ForbiddenError(constant.SuspendedUserErrorMessage)
+	}
+
+	credential, apperr := s.createCredential(ctx, user, user.Role, "signin two factor")
+	if apperr != nil {
+		return nil, apperr
+	}
+	s.securityEventService.Record(ctx, constant.SignInTwoFactorEvent, user.ID.String(), user.Email, meta, true)
+
+	return credential, nil
+}
+
+func (s *serviceImpl) createCredential(ctx context.Context, user *model.User, role constant.Role, module string) (*dto.Credential, *dto.ResponseErr) {
 	createAuthSession := &model.AuthSession{
 		UserID: user.ID,
 	}
-	err = s.authRepo.Create(createAuthSession)
+	err := s.authRepo.Create(ctx, createAuthSession)
 	if err != nil {
 		log.Error().
+			Ctx(ctx).
 			Err(err).
 			Str("service", "auth").
-			Str("module", "signin").
+			Str("module", module).
 			Msg("Error creating auth session")
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
-	credential, err := s.tokenService.CreateCredential(user.ID.String(), user.Role, createAuthSession.ID.String())
+	credential, err := s.tokenService.CreateCredential(ctx, user.ID.String(), role, createAuthSession.ID.String())
 	if err != nil {
 		log.Error().
+			Ctx(ctx).
 			Err(err).
 			Str("service", "auth").
-			Str("module", "signin").
+			Str("module", module).
 			Msg("Error creating credential")
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
@@ -156,62 +401,94 @@ func (s *serviceImpl) SignIn(request *dto.SignInRequest) (*dto.Credential, *dto.
 	return credential, nil
 }
 
-func (s *serviceImpl) SignOut(accessToken string) (*dto.SignOutResponse, *dto.ResponseErr) {
-	userCredential, err := s.tokenService.Validate(accessToken)
+func (s *serviceImpl) removeTwoFactorChallenge(ctx context.Context, challengeToken string) {
+	if err := s.tokenService.RemoveTwoFactorChallenge(ctx, challengeToken); err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", "signin two factor").
+			Msg("Error removing two factor challenge")
+	}
+}
+
+func (s *serviceImpl) SignOut(ctx context.Context, accessToken string, meta *dto.RequestMeta) (*dto.SignOutResponse, *dto.ResponseErr) {
+	userCredential, err := s.tokenService.Validate(ctx, accessToken)
 	if err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
-	err = s.tokenService.RemoveRefreshTokenCache(userCredential.RefreshToken)
+	err = s.tokenService.RemoveRefreshTokenCache(ctx, userCredential.RefreshToken)
 	if err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
-	err = s.tokenService.RemoveAccessTokenCache(userCredential.AuthSessionID)
+	err = s.tokenService.RemoveAccessTokenCache(ctx, userCredential.AuthSessionID)
 	if err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
-	err = s.authRepo.Delete(userCredential.AuthSessionID)
+	err = s.authRepo.Delete(ctx, userCredential.AuthSessionID)
 	if err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
+	s.securityEventService.Record(ctx, constant.SignOutEvent, userCredential.UserID, "", meta, true)
 
 	return &dto.SignOutResponse{IsSuccess: true}, nil
 }
 
-func (s *serviceImpl) ForgotPassword(request *dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, *dto.ResponseErr) {
+func (s *serviceImpl) ForgotPassword(ctx context.Context, request *dto.ForgotPasswordRequest, meta *dto.RequestMeta) (*dto.ForgotPasswordResponse, *dto.ResponseErr) {
+	if apperr := s.checkThrottle(ctx, constant.ForgotPasswordAction, request.Email, meta); apperr != nil {
+		return nil, apperr
+	}
+
+	// every request counts since each one sends an email, the count is reset when the password is reset
+	// so requests made in the name of the user cannot keep them from getting a new link
+	if _, err := s.throttleService.Hit(ctx, constant.ForgotPasswordAction, request.Email, meta.IP); err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", "forgot password").
+			Msg("Error counting forgot password request")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
 	user := &model.User{}
-	err := s.userRepo.FindByEmail(request.Email, user)
+	err := s.userRepo.FindByEmail(ctx, request.Email, user)
 	if err != nil {
+		s.securityEventService.Record(ctx, constant.ForgotPasswordEvent, "", request.Email, meta, false)
 		return nil, dto.NotFoundError(constant.UserNotFoundErrorMessage)
 	}
 
-	resetPasswordToken, err := s.tokenService.CreateResetPasswordToken(user.ID.String())
+	resetPasswordToken, err := s.tokenService.CreateResetPasswordToken(ctx, user.ID.String())
 	if err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
-	resetPasswordURL := fmt.Sprintf("%s/admin/reset-password/%s", s.config.ClientURL, resetPasswordToken)
-	emailSubject := constant.ResetPasswordSubject
-	emailContent := fmt.Sprintf("Please click the following url to reset password %s", resetPasswordURL)
-	if err := s.emailService.SendEmail(emailSubject, user.Firstname, user.Email, emailContent); err != nil {
+	recipient := &email.Recipient{Name: user.Firstname, Address: user.Email, Locale: user.Locale}
+	data := &email.LinkData{
+		Name: user.Firstname,
+		URL:  fmt.Sprintf("%s/admin/reset-password/%s", s.config.ClientURL, resetPasswordToken),
+	}
+	if err := s.emailService.Send(ctx, constant.ResetPasswordTemplate, recipient, data); err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
+	s.securityEventService.Record(ctx, constant.ForgotPasswordEvent, user.ID.String(), user.Email, meta, true)
 
 	return &dto.ForgotPasswordResponse{
 		IsSuccess: true,
 	}, nil
 }
 
-func (s *serviceImpl) ResetPassword(request *dto.ResetPasswordRequest) (*dto.ResetPasswordResponse, *dto.ResponseErr) {
-	resetTokenCache, err := s.tokenService.FindResetPasswordToken(request.Token)
+func (s *serviceImpl) ResetPassword(ctx context.Context, request *dto.ResetPasswordRequest, meta *dto.RequestMeta) (*dto.ResetPasswordResponse, *dto.ResponseErr) {
+	resetTokenCache, err := s.tokenService.FindResetPasswordToken(ctx, request.Token)
 	if err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
 	userDb := &model.User{}
-	if err := s.userRepo.FindById(resetTokenCache.UserID, userDb); err != nil {
+	if err := s.userRepo.FindById(ctx, resetTokenCache.UserID, userDb); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, dto.NotFoundError(constant.UserNotFoundErrorMessage)
 		}
@@ -229,15 +506,94 @@ func (s *serviceImpl) ResetPassword(request *dto.ResetPasswordRequest) (*dto.Res
 	}
 
 	userDb.Password = hashPassword
-	if err := s.userRepo.Update(resetTokenCache.UserID, userDb); err != nil {
+	if err := s.userRepo.Update(ctx, resetTokenCache.UserID, userDb); err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
-	if err := s.tokenService.RemoveResetPasswordToken(request.Token); err != nil {
+	if err := s.tokenService.RemoveResetPasswordToken(ctx, request.Token); err != nil {
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
+	if err := s.throttleService.Reset(ctx, constant.ForgotPasswordAction, userDb.Email); err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", "reset password").
+			Msg("Error resetting forgot password requests")
+	}
+	s.securityEventService.Record(ctx, constant.ResetPasswordEvent, userDb.ID.String(), userDb.Email, meta, true)
+
 	return &dto.ResetPasswordResponse{
 		IsSuccess: true,
 	}, nil
 }
+
+func (s *serviceImpl) checkThrottle(ctx context.Context, action constant.ThrottleAction, email string, meta *dto.RequestMeta) *dto.ResponseErr {
+	wait, err := s.throttleService.Check(ctx, action, email, meta.IP)
+	if err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", string(action)).
+			Msg("Error checking throttle")
+		return dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+	if wait > 0 {
+		return dto.TooManyRequestsError(constant.TooManyRequestsErrorMessage, wait)
+	}
+
+	return nil
+}
+
+// failSignIn counts the failure and notifies the owner when it locks the account, user is nil for unknown emails
+func (s *serviceImpl) failSignIn(ctx context.Context, email string, user *model.User, meta *dto.RequestMeta) *dto.ResponseErr {
+	userId := ""
+	if user != nil {
+		userId = user.ID.String()
+	}
+	s.securityEventService.Record(ctx, constant.SignInEvent, userId, email, meta, false)
+
+	result, err := s.throttleService.Hit(ctx, constant.SignInAction, email, meta.IP)
+	if err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", "signin").
+			Msg("Error counting sign in failure")
+		return dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	if result.IpLocked {
+		return dto.TooManyRequestsError(constant.TooManyRequestsErrorMessage, result.RetryAfter)
+	}
+	if !result.Locked {
+		return dto.UnauthorizedError(constant.IncorrectEmailPasswordErrorMessage)
+	}
+
+	if user != nil {
+		s.sendAccountLocked(ctx, user, result.RetryAfter)
+	}
+
+	return dto.TooManyRequestsError(constant.AccountLockedErrorMessage, result.RetryAfter)
+}
+
+func (s *serviceImpl) sendAccountLocked(ctx context.Context, user *model.User, lockout time.Duration) {
+	recipient := &email.Recipient{Name: user.Firstname, Address: user.Email, Locale: user.Locale}
+	data := &email.AccountLockedData{
+		Name:    user.Firstname,
+		Minutes: int(lockout.Minutes()),
+		URL:     fmt.Sprintf("%s/forgot-password", s.config.ClientURL),
+	}
+	if err := s.emailService.Send(ctx, constant.AccountLockedTemplate, recipient, data); err != nil {
+		log.Error().
+			Ctx(ctx).
+			Err(err).
+			Str("service", "auth").
+			Str("module", "signin").
+			Str("user_id", user.ID.String()).
+			Msg("Error sending account locked email")
+	}
+}
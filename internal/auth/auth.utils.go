@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/internal/utils"
 )
 
 func IsExisted(e map[string]struct{}, key string) bool {
@@ -21,6 +22,14 @@ func FormatPath(method string, path string, keys []string) string {
 	return fmt.Sprintf("%v %v", method, path)
 }
 
+// FormatRoute turns a request path into the route the guard and the rate limit match, e.g. GET /v1/pets/1
+// becomes GET /pets/:id
+func FormatRoute(method string, path string, versionList map[string]struct{}) string {
+	path = utils.TrimInList(path, "/", versionList)
+	ids := FindIDFromPath(path)
+	return FormatPath(method, path, ids)
+}
+
 func FindIntFromStr(s string, sep string) []string {
 	spliteds := strings.Split(s, sep)
 
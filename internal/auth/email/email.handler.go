@@ -0,0 +1,133 @@
+package email
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/router"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service}
+}
+
+// Preview is a function that renders an email template with sample data
+// @Summary previews email template
+// @Description Returns the subject, text and html of the template rendered with sample data
+// @Param template query string true "reset_password, verify_email, adoption_status or account_locked"
+// @Param locale query string false "th or en" default(en)
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.EmailPreviewResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid template or locale"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/admin/email-preview [get]
+func (h *Handler) Preview(c router.IContext) {
+	queries := c.Queries()
+
+	locale := constant.Locale(queries["locale"])
+	if locale == "" {
+		locale = constant.DefaultLocale
+	}
+
+	response, respErr := h.service.Preview(c.UserContext(), constant.EmailTemplate(queries["template"]), locale)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// FindAll is a function that lists the outbox emails in a status
+// @Summary finds outbox emails
+// @Description Returns the outbox emails in the status, the dead ones by default
+// @Param status query string false "pending, sent or dead" default(dead)
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.FindAllEmailResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid status"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/admin/emails [get]
+func (h *Handler) FindAll(c router.IContext) {
+	request, err := QueriesToFindAllDto(c.Queries())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	response, respErr := h.service.FindAll(c.UserContext(), request)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Retry is a function that moves a dead outbox email back to pending
+// @Summary retries outbox email
+// @Description Resets the attempts of a dead email so the worker delivers it again
+// @Param id path string true "email id"
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.Email
+// @Failure 404 {object} dto.ResponseNotfoundErr "Email not found or not dead"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/admin/emails/{id}/retry [post]
+func (h *Handler) Retry(c router.IContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequestError(constant.InvalidIDMessage))
+		return
+	}
+
+	response, respErr := h.service.Retry(c.UserContext(), id)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func QueriesToFindAllDto(queries map[string]string) (*dto.FindAllEmailRequest, error) {
+	request := &dto.FindAllEmailRequest{Status: constant.DEAD}
+
+	for q, v := range queries {
+		switch q {
+		case "status":
+			request.Status = constant.EmailStatus(v)
+		case "page":
+			page, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errors.New("error parsing page")
+			}
+			request.Page = page
+		case "pageSize":
+			pageSize, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errors.New("error parsing pageSize")
+			}
+			request.PageSize = pageSize
+		}
+	}
+
+	return request, nil
+}
@@ -0,0 +1,109 @@
+package email
+
+import (
+	"context"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	Create(ctx context.Context, email *model.EmailOutbox) error
+	FindByStatus(ctx context.Context, status constant.EmailStatus, limit int, offset int, result *[]*model.EmailOutbox, total *int64) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration, result *[]*model.EmailOutbox) error
+	MarkSent(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id string, status constant.EmailStatus, nextAttemptAt time.Time, lastError string) error
+	Retry(ctx context.Context, id string, result *model.EmailOutbox) error
+}
+
+type repositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repositoryImpl{Db: db}
+}
+
+func (r *repositoryImpl) Create(ctx context.Context, email *model.EmailOutbox) error {
+	return r.Db.WithContext(ctx).Create(email).Error
+}
+
+func (r *repositoryImpl) FindByStatus(ctx context.Context, status constant.EmailStatus, limit int, offset int, result *[]*model.EmailOutbox, total *int64) error {
+	query := r.Db.WithContext(ctx).Model(&model.EmailOutbox{}).Where("status = ?", status)
+
+	if err := query.Count(total).Error; err != nil {
+		return err
+	}
+
+	return query.Order("created_at desc").Limit(limit).Offset(offset).Find(result).Error
+}
+
+// ClaimDue locks the due emails, skipping the ones claimed by other workers, and pushes their next attempt
+// past the lease so an email is picked up again if the worker dies before marking it
+func (r *repositoryImpl) ClaimDue(ctx context.Context, limit int, lease time.Duration, result *[]*model.EmailOutbox) error {
+	return r.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", constant.PENDING, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(result).Error
+		if err != nil {
+			return err
+		}
+		if len(*result) == 0 {
+			return nil
+		}
+
+		var ids []string
+		for _, email := range *result {
+			ids = append(ids, email.ID.String())
+			email.Attempts++
+		}
+
+		return tx.Model(&model.EmailOutbox{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+}
+
+// MarkSent clears the bodies because they hold the links of reset, verification and magic link tokens
+func (r *repositoryImpl) MarkSent(ctx context.Context, id string) error {
+	return r.Db.WithContext(ctx).Model(&model.EmailOutbox{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     constant.SENT,
+		"sent_at":    time.Now(),
+		"last_error": "",
+		"text":       "",
+		"html":       "",
+	}).Error
+}
+
+func (r *repositoryImpl) MarkFailed(ctx context.Context, id string, status constant.EmailStatus, nextAttemptAt time.Time, lastError string) error {
+	return r.Db.WithContext(ctx).Model(&model.EmailOutbox{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          status,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	}).Error
+}
+
+// Retry moves a dead email back to pending with a fresh attempt count
+func (r *repositoryImpl) Retry(ctx context.Context, id string, result *model.EmailOutbox) error {
+	update := r.Db.WithContext(ctx).Model(&model.EmailOutbox{}).Where("id = ? AND status = ?", id, constant.DEAD).Updates(map[string]interface{}{
+		"status":          constant.PENDING,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	})
+	if update.Error != nil {
+		return update.Error
+	}
+	if update.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return r.Db.WithContext(ctx).First(result, "id = ?", id).Error
+}
@@ -1,41 +1,189 @@
 package email
 
 import (
-	"fmt"
-	"net/http"
+	"context"
+	"errors"
+	"math"
+	"time"
 
 	"github.com/isd-sgcu/johnjud-backend/config"
-	"github.com/pkg/errors"
-	"github.com/sendgrid/sendgrid-go"
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type Recipient struct {
+	Name    string
+	Address string
+	Locale  constant.Locale
+}
+
 type Service interface {
-	SendEmail(subject string, toName string, toAddress string, content string) error
+	Send(ctx context.Context, template constant.EmailTemplate, recipient *Recipient, data interface{}) error
+	Preview(ctx context.Context, template constant.EmailTemplate, locale constant.Locale) (*dto.EmailPreviewResponse, *dto.ResponseErr)
+	FindAll(ctx context.Context, request *dto.FindAllEmailRequest) (*dto.FindAllEmailResponse, *dto.ResponseErr)
+	Retry(ctx context.Context, id string) (*dto.Email, *dto.ResponseErr)
 }
 
 type serviceImpl struct {
-	config config.Sendgrid
-	client *sendgrid.Client
+	config     config.Email
+	repository Repository
+	renderer   Renderer
 }
 
-func NewService(config config.Sendgrid) Service {
-	client := sendgrid.NewSendClient(config.ApiKey)
-	return &serviceImpl{config: config, client: client}
+func NewService(config config.Email, repository Repository, renderer Renderer) Service {
+	return &serviceImpl{config: config, repository: repository, renderer: renderer}
 }
 
-func (s *serviceImpl) SendEmail(subject string, toName string, toAddress string, content string) error {
-	from := mail.NewEmail(s.config.Name, s.config.Address)
-	to := mail.NewEmail(toName, toAddress)
-	message := mail.NewSingleEmail(from, subject, to, content, content)
-
-	resp, err := s.client.Send(message)
+// Send renders the template in the locale of the recipient and writes both the html and the text variant to the outbox,
+// the worker delivers it later so a transport failure never fails the caller
+func (s *serviceImpl) Send(ctx context.Context, template constant.EmailTemplate, recipient *Recipient, data interface{}) error {
+	rendered, err := s.renderer.Render(template, recipient.Locale, data)
 	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "email").
+			Str("module", "send").
+			Str("template", string(template)).
+			Msg("Error rendering email template")
 		return err
 	}
-	if resp.StatusCode != http.StatusAccepted {
-		return errors.New(fmt.Sprintf("%d status code", resp.StatusCode))
+
+	email := &model.EmailOutbox{
+		Template:      template,
+		FromName:      s.config.FromName,
+		FromAddress:   s.config.FromAddress,
+		ToName:        recipient.Name,
+		ToAddress:     recipient.Address,
+		Subject:       rendered.Subject,
+		Text:          rendered.Text,
+		Html:          rendered.Html,
+		Status:        constant.PENDING,
+		NextAttemptAt: time.Now(),
+	}
+	if err := s.repository.Create(ctx, email); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "email").
+			Str("module", "send").
+			Str("template", string(template)).
+			Msg("Error writing email to the outbox")
+		return err
 	}
 
 	return nil
 }
+
+func (s *serviceImpl) Preview(ctx context.Context, template constant.EmailTemplate, locale constant.Locale) (*dto.EmailPreviewResponse, *dto.ResponseErr) {
+	if !IsSupportedTemplate(template) {
+		return nil, dto.BadRequestError(constant.InvalidEmailTemplateErrorMessage)
+	}
+	if !IsSupportedLocale(locale) {
+		return nil, dto.BadRequestError(constant.InvalidLocaleErrorMessage)
+	}
+
+	rendered, err := s.renderer.Render(template, locale, SampleData[template])
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "email").
+			Str("module", "preview").
+			Str("template", string(template)).
+			Msg("Error rendering email template")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return &dto.EmailPreviewResponse{
+		Template: string(template),
+		Locale:   string(locale),
+		Subject:  rendered.Subject,
+		Text:     rendered.Text,
+		Html:     rendered.Html,
+	}, nil
+}
+
+func (s *serviceImpl) FindAll(ctx context.Context, request *dto.FindAllEmailRequest) (*dto.FindAllEmailResponse, *dto.ResponseErr) {
+	if !IsSupportedStatus(request.Status) {
+		return nil, dto.BadRequestError(constant.InvalidEmailStatusErrorMessage)
+	}
+
+	page := request.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := request.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	var emails []*model.EmailOutbox
+	var total int64
+	err := s.repository.FindByStatus(ctx, request.Status, pageSize, (page-1)*pageSize, &emails, &total)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "email").
+			Str("module", "find all").
+			Msg("Error querying the outbox")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	result := []*dto.Email{}
+	for _, email := range emails {
+		result = append(result, RawToDto(email))
+	}
+
+	return &dto.FindAllEmailResponse{
+		Emails: result,
+		Metadata: &dto.FindAllMetadata{
+			Page:       page,
+			TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+			PageSize:   pageSize,
+			Total:      int(total),
+		},
+	}, nil
+}
+
+func (s *serviceImpl) Retry(ctx context.Context, id string) (*dto.Email, *dto.ResponseErr) {
+	var email model.EmailOutbox
+	err := s.repository.Retry(ctx, id, &email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.NotFoundError(constant.EmailNotFoundErrorMessage)
+		}
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "email").
+			Str("module", "retry").
+			Str("id", id).
+			Msg("Error retrying email")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return RawToDto(&email), nil
+}
+
+func IsSupportedStatus(status constant.EmailStatus) bool {
+	return status == constant.PENDING || status == constant.SENT || status == constant.DEAD
+}
+
+func RawToDto(in *model.EmailOutbox) *dto.Email {
+	return &dto.Email{
+		Id:            in.ID.String(),
+		Template:      string(in.Template),
+		ToName:        in.ToName,
+		ToAddress:     in.ToAddress,
+		Subject:       in.Subject,
+		Status:        in.Status,
+		Attempts:      in.Attempts,
+		LastError:     in.LastError,
+		NextAttemptAt: in.NextAttemptAt,
+		SentAt:        in.SentAt,
+		CreatedAt:     in.CreatedAt,
+	}
+}
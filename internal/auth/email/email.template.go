@@ -0,0 +1,168 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var Templates = []constant.EmailTemplate{
+	constant.ResetPasswordTemplate,
+	constant.VerifyEmailTemplate,
+	constant.AdoptionStatusTemplate,
+	constant.AccountLockedTemplate,
+	constant.MagicLinkTemplate,
+}
+
+var Locales = []constant.Locale{constant.TH, constant.EN}
+
+// LinkData is the data of the templates that ask the user to open a link
+type LinkData struct {
+	Name string
+	URL  string
+}
+
+type AdoptionStatusData struct {
+	Name    string
+	PetName string
+}
+
+type AccountLockedData struct {
+	Name    string
+	Minutes int
+	// URL leads to the forgot password page
+	URL string
+}
+
+// SampleData is rendered by the admin preview
+var SampleData = map[constant.EmailTemplate]interface{}{
+	constant.ResetPasswordTemplate:  &LinkData{Name: "Somchai", URL: "https://johnjud.example/admin/reset-password/sample-token"},
+	constant.VerifyEmailTemplate:    &LinkData{Name: "Somchai", URL: "https://johnjud.example/verify-email/sample-token"},
+	constant.AdoptionStatusTemplate: &AdoptionStatusData{Name: "Somchai", PetName: "Mali"},
+	constant.AccountLockedTemplate:  &AccountLockedData{Name: "Somchai", Minutes: 15, URL: "https://johnjud.example/forgot-password"},
+	constant.MagicLinkTemplate:      &LinkData{Name: "Somchai", URL: "https://johnjud.example/signin/magic-link/sample-token"},
+}
+
+type Rendered struct {
+	Subject string
+	Text    string
+	Html    string
+}
+
+type Renderer interface {
+	Render(name constant.EmailTemplate, locale constant.Locale, data interface{}) (*Rendered, error)
+}
+
+// the root data of both layouts, the template itself is executed with Data
+type layoutData struct {
+	Locale  constant.Locale
+	Subject string
+	Data    interface{}
+}
+
+type rendererImpl struct {
+	html map[string]*htmltemplate.Template
+	text map[string]*texttemplate.Template
+}
+
+// NewRenderer parses every template with its layout once, each template defines "content" in both variants and "subject" in the text one
+func NewRenderer() (Renderer, error) {
+	funcs := map[string]interface{}{
+		"button": func(url string, label string) map[string]string {
+			return map[string]string{"URL": url, "Label": label}
+		},
+	}
+
+	r := &rendererImpl{
+		html: make(map[string]*htmltemplate.Template),
+		text: make(map[string]*texttemplate.Template),
+	}
+	for _, locale := range Locales {
+		for _, name := range Templates {
+			key := templateKey(name, locale)
+
+			html, err := htmltemplate.New(key).Funcs(funcs).ParseFS(templateFS,
+				"templates/layout.html.tmpl", fmt.Sprintf("templates/%s/%s.html.tmpl", locale, name))
+			if err != nil {
+				return nil, err
+			}
+			r.html[key] = html
+
+			text, err := texttemplate.New(key).Funcs(funcs).ParseFS(templateFS,
+				"templates/layout.txt.tmpl", fmt.Sprintf("templates/%s/%s.txt.tmpl", locale, name))
+			if err != nil {
+				return nil, err
+			}
+			r.text[key] = text
+		}
+	}
+
+	return r, nil
+}
+
+// Render falls back to the default locale when the locale is not supported
+func (r *rendererImpl) Render(name constant.EmailTemplate, locale constant.Locale, data interface{}) (*Rendered, error) {
+	if !IsSupportedLocale(locale) {
+		locale = constant.DefaultLocale
+	}
+
+	key := templateKey(name, locale)
+	text, ok := r.text[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template: %s", name)
+	}
+	html := r.html[key]
+
+	subject := bytes.NewBuffer(nil)
+	if err := text.ExecuteTemplate(subject, "subject", data); err != nil {
+		return nil, err
+	}
+
+	root := &layoutData{Locale: locale, Subject: strings.TrimSpace(subject.String()), Data: data}
+
+	textBody := bytes.NewBuffer(nil)
+	if err := text.ExecuteTemplate(textBody, "layout", root); err != nil {
+		return nil, err
+	}
+
+	htmlBody := bytes.NewBuffer(nil)
+	if err := html.ExecuteTemplate(htmlBody, "layout", root); err != nil {
+		return nil, err
+	}
+
+	return &Rendered{
+		Subject: root.Subject,
+		Text:    strings.TrimSpace(textBody.String()),
+		Html:    htmlBody.String(),
+	}, nil
+}
+
+func IsSupportedLocale(locale constant.Locale) bool {
+	for _, l := range Locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+func IsSupportedTemplate(name constant.EmailTemplate) bool {
+	for _, t := range Templates {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+func templateKey(name constant.EmailTemplate, locale constant.Locale) string {
+	return fmt.Sprintf("%s/%s", locale, name)
+}
@@ -0,0 +1,39 @@
+package email
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+)
+
+type Message struct {
+	FromName    string
+	FromAddress string
+	ToName      string
+	ToAddress   string
+	Subject     string
+	Text        string
+	Html        string
+}
+
+type Transport interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// NewTransport returns the transport chosen by EMAIL_TRANSPORT, sendgrid is used when it is not set
+func NewTransport(emailConfig config.Email, sendgridConfig config.Sendgrid, smtpConfig config.Smtp) (Transport, error) {
+	switch constant.EmailTransport(emailConfig.Transport) {
+	case constant.SENDGRID, "":
+		return NewSendgridTransport(sendgridConfig), nil
+	case constant.SMTP:
+		return NewSmtpTransport(smtpConfig), nil
+	case constant.FILE:
+		return NewFileTransport(emailConfig.SinkDir)
+	case constant.LOG:
+		return NewLogTransport(), nil
+	default:
+		return nil, fmt.Errorf("unknown email transport: %s", emailConfig.Transport)
+	}
+}
@@ -0,0 +1,129 @@
+package email
+
+import (
+	"context"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// claimed emails are retried after the lease if the worker stops before marking them
+const outboxLease = 5 * time.Minute
+
+type Worker interface {
+	Run(ctx context.Context)
+	Process(ctx context.Context) int
+}
+
+type workerImpl struct {
+	config     config.Email
+	repository Repository
+	transport  Transport
+}
+
+func NewWorker(config config.Email, repository Repository, transport Transport) Worker {
+	return &workerImpl{config: config, repository: repository, transport: transport}
+}
+
+// Run polls the outbox until the context is cancelled. The batch being delivered when it is cancelled is finished
+// so the claimed emails are marked instead of waiting for their lease to run out
+func (w *workerImpl) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(w.config.OutboxPollInterval) * time.Second)
+	defer ticker.Stop()
+
+	batchCtx := context.WithoutCancel(ctx)
+	for {
+		// keep draining while the batches are full
+		for w.Process(batchCtx) >= w.config.OutboxBatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Process delivers one batch of due emails and returns how many were claimed
+func (w *workerImpl) Process(ctx context.Context) int {
+	var emails []*model.EmailOutbox
+	if err := w.repository.ClaimDue(ctx, w.config.OutboxBatchSize, outboxLease, &emails); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "email").
+			Str("module", "worker").
+			Msg("Error claiming due emails")
+		return 0
+	}
+
+	for _, email := range emails {
+		w.deliver(ctx, email)
+	}
+
+	return len(emails)
+}
+
+func (w *workerImpl) deliver(ctx context.Context, email *model.EmailOutbox) {
+	err := w.transport.Send(ctx, &Message{
+		FromName:    email.FromName,
+		FromAddress: email.FromAddress,
+		ToName:      email.ToName,
+		ToAddress:   email.ToAddress,
+		Subject:     email.Subject,
+		Text:        email.Text,
+		Html:        email.Html,
+	})
+	if err == nil {
+		if err := w.repository.MarkSent(ctx, email.ID.String()); err != nil {
+			log.Error().Ctx(ctx).Err(err).
+				Str("service", "email").
+				Str("module", "worker").
+				Str("id", email.ID.String()).
+				Msg("Error marking email as sent")
+		}
+		return
+	}
+
+	status := constant.PENDING
+	nextAttemptAt := time.Now().Add(Backoff(email.Attempts, w.config.OutboxBaseBackoff, w.config.OutboxMaxBackoff))
+	if email.Attempts >= w.config.OutboxMaxAttempts {
+		status = constant.DEAD
+		nextAttemptAt = time.Now()
+	}
+
+	log.Warn().Ctx(ctx).Err(err).
+		Str("service", "email").
+		Str("module", "worker").
+		Str("id", email.ID.String()).
+		Int("attempts", email.Attempts).
+		Str("status", string(status)).
+		Msg("Error delivering email")
+
+	if err := w.repository.MarkFailed(ctx, email.ID.String(), status, nextAttemptAt, err.Error()); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "email").
+			Str("module", "worker").
+			Str("id", email.ID.String()).
+			Msg("Error marking email as failed")
+	}
+}
+
+// Backoff doubles the base delay (in seconds) for every attempt after the first, capped at max
+func Backoff(attempts int, base int, max int) time.Duration {
+	delay := time.Duration(base) * time.Second
+	limit := time.Duration(max) * time.Second
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		delay = limit
+	}
+
+	return delay
+}
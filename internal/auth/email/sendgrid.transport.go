@@ -0,0 +1,42 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/pkg/errors"
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+type sendgridTransport struct {
+	client *sendgrid.Client
+}
+
+func NewSendgridTransport(config config.Sendgrid) Transport {
+	client := sendgrid.NewSendClient(config.ApiKey)
+	return &sendgridTransport{client: client}
+}
+
+func (t *sendgridTransport) Send(ctx context.Context, message *Message) error {
+	from := mail.NewEmail(message.FromName, message.FromAddress)
+	to := mail.NewEmail(message.ToName, message.ToAddress)
+
+	html := message.Html
+	if html == "" {
+		html = message.Text
+	}
+	content := mail.NewSingleEmail(from, message.Subject, to, message.Text, html)
+
+	resp, err := t.client.SendWithContext(ctx, content)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		return errors.New(fmt.Sprintf("%d status code", resp.StatusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type fileTransport struct {
+	dir string
+}
+
+// NewFileTransport writes every message as an .eml file into dir for development
+func NewFileTransport(dir string) (Transport, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("email sink directory is required by the file transport")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &fileTransport{dir: dir}, nil
+}
+
+func (t *fileTransport) Send(ctx context.Context, message *Message) error {
+	data, err := FormatMessage(message)
+	if err != nil {
+		return err
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(message.ToAddress)
+	filename := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405.000000000"), recipient)
+
+	return os.WriteFile(filepath.Join(t.dir, filename), data, 0o644)
+}
+
+type logTransport struct{}
+
+// NewLogTransport only logs the message for development
+func NewLogTransport() Transport {
+	return &logTransport{}
+}
+
+func (t *logTransport) Send(ctx context.Context, message *Message) error {
+	log.Info().
+		Ctx(ctx).
+		Str("service", "email").
+		Str("module", "log transport").
+		Str("to", message.ToAddress).
+		Str("subject", message.Subject).
+		Str("text", message.Text).
+		Msg("Email is not sent by the log transport")
+	return nil
+}
@@ -0,0 +1,161 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+)
+
+type smtpTransport struct {
+	config config.Smtp
+}
+
+// NewSmtpTransport sends through any smtp server, STARTTLS is used when the server supports it.
+// Authentication is skipped when no username is set, which is what local stand-ins like mailpit expect.
+func NewSmtpTransport(config config.Smtp) Transport {
+	return &smtpTransport{config: config}
+}
+
+func (t *smtpTransport) Send(ctx context.Context, message *Message) error {
+	data, err := FormatMessage(message)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if t.config.Username != "" {
+		auth = smtp.PlainAuth("", t.config.Username, t.config.Password, t.config.Host)
+	}
+
+	addr := t.config.Host + ":" + strconv.Itoa(t.config.Port)
+	return sendMail(ctx, addr, t.config.Host, auth, message.FromAddress, message.ToAddress, data)
+}
+
+// sendMail is smtp.SendMail with a connection that is dialed with the context and given up on at its deadline
+func sendMail(ctx context.Context, addr string, host string, auth smtp.Auth, from string, to string, data []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	// unblock the conversation when the context is cancelled without a deadline
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// FormatMessage builds the raw mime message, a multipart/alternative body is used when the message has html
+func FormatMessage(message *Message) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+
+	from := mail.Address{Name: message.FromName, Address: message.FromAddress}
+	to := mail.Address{Name: message.ToName, Address: message.ToAddress}
+	fmt.Fprintf(buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if message.Html == "" {
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(buf, message.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(buf)
+	fmt.Fprintf(buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.Html},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w interface{ Write([]byte) (int, error) }, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
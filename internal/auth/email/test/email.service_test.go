@@ -0,0 +1,436 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/email"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	mock_email_repo "github.com/isd-sgcu/johnjud-backend/mocks/repository/email"
+	mock_email "github.com/isd-sgcu/johnjud-backend/mocks/service/email"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type EmailServiceTest struct {
+	suite.Suite
+	config  config.Email
+	message *email.Message
+	outbox  *model.EmailOutbox
+}
+
+func TestEmailService(t *testing.T) {
+	suite.Run(t, new(EmailServiceTest))
+}
+
+func (t *EmailServiceTest) SetupTest() {
+	t.config = config.Email{
+		FromName:          "johnjud",
+		FromAddress:       "johnjud@example.com",
+		OutboxBatchSize:   20,
+		OutboxMaxAttempts: 8,
+		OutboxBaseBackoff: 30,
+		OutboxMaxBackoff:  3600,
+	}
+	t.message = &email.Message{
+		FromName:    t.config.FromName,
+		FromAddress: t.config.FromAddress,
+		ToName:      faker.FirstName(),
+		ToAddress:   faker.Email(),
+		Subject:     "Reset Password Request",
+		Text:        "Please click the following url to reset password",
+	}
+	t.outbox = &model.EmailOutbox{
+		Base:          model.Base{ID: uuid.New(), CreatedAt: time.Now()},
+		Template:      constant.ResetPasswordTemplate,
+		FromName:      t.message.FromName,
+		FromAddress:   t.message.FromAddress,
+		ToName:        t.message.ToName,
+		ToAddress:     t.message.ToAddress,
+		Subject:       t.message.Subject,
+		Text:          t.message.Text,
+		Status:        constant.DEAD,
+		Attempts:      1,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+func (t *EmailServiceTest) TestSendSuccess() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+
+	renderer, err := email.NewRenderer()
+	assert.Nil(t.T(), err)
+
+	var queued *model.EmailOutbox
+	repository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, email *model.EmailOutbox) error {
+		queued = email
+		return nil
+	})
+
+	recipient := &email.Recipient{Name: t.message.ToName, Address: t.message.ToAddress, Locale: constant.EN}
+	data := &email.LinkData{Name: t.message.ToName, URL: "https://johnjud.example/admin/reset-password/token"}
+
+	service := email.NewService(t.config, repository, renderer)
+	err = service.Send(context.Background(), constant.ResetPasswordTemplate, recipient, data)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), constant.PENDING, queued.Status)
+	assert.Equal(t.T(), t.config.FromAddress, queued.FromAddress)
+	assert.Equal(t.T(), t.message.ToAddress, queued.ToAddress)
+	assert.Equal(t.T(), "Reset your Johnjud password", queued.Subject)
+	assert.Contains(t.T(), queued.Text, data.URL)
+	assert.Contains(t.T(), queued.Html, data.URL)
+}
+
+func (t *EmailServiceTest) TestSendUnknownTemplate() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+
+	renderer, err := email.NewRenderer()
+	assert.Nil(t.T(), err)
+
+	recipient := &email.Recipient{Name: t.message.ToName, Address: t.message.ToAddress, Locale: constant.EN}
+
+	service := email.NewService(t.config, repository, renderer)
+	err = service.Send(context.Background(), "unknown", recipient, nil)
+
+	assert.NotNil(t.T(), err)
+}
+
+func (t *EmailServiceTest) TestSendOutboxError() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+
+	renderer, err := email.NewRenderer()
+	assert.Nil(t.T(), err)
+
+	repository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("Connection lost"))
+
+	recipient := &email.Recipient{Name: t.message.ToName, Address: t.message.ToAddress, Locale: constant.EN}
+	data := email.SampleData[constant.VerifyEmailTemplate]
+
+	service := email.NewService(t.config, repository, renderer)
+	err = service.Send(context.Background(), constant.VerifyEmailTemplate, recipient, data)
+
+	assert.NotNil(t.T(), err)
+}
+
+func (t *EmailServiceTest) TestFindAllSuccess() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+
+	emails := []*model.EmailOutbox{t.outbox}
+	repository.EXPECT().FindByStatus(gomock.Any(), constant.DEAD, 20, 20, gomock.Any(), gomock.Any()).
+		SetArg(4, emails).SetArg(5, int64(21)).Return(nil)
+
+	service := email.NewService(t.config, repository, nil)
+	actual, err := service.FindAll(context.Background(), &dto.FindAllEmailRequest{Status: constant.DEAD, Page: 2})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), []*dto.Email{email.RawToDto(t.outbox)}, actual.Emails)
+	assert.Equal(t.T(), &dto.FindAllMetadata{Page: 2, TotalPages: 2, PageSize: 20, Total: 21}, actual.Metadata)
+}
+
+func (t *EmailServiceTest) TestFindAllInvalidStatus() {
+	service := email.NewService(t.config, nil, nil)
+	actual, err := service.FindAll(context.Background(), &dto.FindAllEmailRequest{Status: "bounced"})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+	assert.Equal(t.T(), constant.InvalidEmailStatusErrorMessage, err.Message)
+}
+
+func (t *EmailServiceTest) TestRetrySuccess() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+
+	retried := *t.outbox
+	retried.Status = constant.PENDING
+	retried.Attempts = 0
+	repository.EXPECT().Retry(gomock.Any(), t.outbox.ID.String(), gomock.Any()).SetArg(2, retried).Return(nil)
+
+	service := email.NewService(t.config, repository, nil)
+	actual, err := service.Retry(context.Background(), t.outbox.ID.String())
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), email.RawToDto(&retried), actual)
+}
+
+func (t *EmailServiceTest) TestRetryNotFound() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+
+	repository.EXPECT().Retry(gomock.Any(), t.outbox.ID.String(), gomock.Any()).Return(gorm.ErrRecordNotFound)
+
+	service := email.NewService(t.config, repository, nil)
+	actual, err := service.Retry(context.Background(), t.outbox.ID.String())
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
+}
+
+func (t *EmailServiceTest) TestWorkerSendSuccess() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+	transport := mock_email.NewMockTransport(controller)
+
+	repository.EXPECT().ClaimDue(gomock.Any(), t.config.OutboxBatchSize, gomock.Any(), gomock.Any()).
+		SetArg(3, []*model.EmailOutbox{t.outbox}).Return(nil)
+	transport.EXPECT().Send(gomock.Any(), &email.Message{
+		FromName:    t.outbox.FromName,
+		FromAddress: t.outbox.FromAddress,
+		ToName:      t.outbox.ToName,
+		ToAddress:   t.outbox.ToAddress,
+		Subject:     t.outbox.Subject,
+		Text:        t.outbox.Text,
+		Html:        t.outbox.Html,
+	}).Return(nil)
+	repository.EXPECT().MarkSent(gomock.Any(), t.outbox.ID.String()).Return(nil)
+
+	worker := email.NewWorker(t.config, repository, transport)
+
+	assert.Equal(t.T(), 1, worker.Process(context.Background()))
+}
+
+func (t *EmailServiceTest) TestWorkerSendErrorRetry() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+	transport := mock_email.NewMockTransport(controller)
+
+	t.outbox.Attempts = 2
+	before := time.Now()
+
+	repository.EXPECT().ClaimDue(gomock.Any(), t.config.OutboxBatchSize, gomock.Any(), gomock.Any()).
+		SetArg(3, []*model.EmailOutbox{t.outbox}).Return(nil)
+	transport.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("Service unavailable"))
+	repository.EXPECT().MarkFailed(gomock.Any(), t.outbox.ID.String(), constant.PENDING, gomock.Any(), "Service unavailable").
+		DoAndReturn(func(_ context.Context, id string, status constant.EmailStatus, nextAttemptAt time.Time, lastError string) error {
+			// the second attempt waits twice the base backoff
+			assert.True(t.T(), !nextAttemptAt.Before(before.Add(2*time.Duration(t.config.OutboxBaseBackoff)*time.Second)))
+			return nil
+		})
+
+	worker := email.NewWorker(t.config, repository, transport)
+
+	assert.Equal(t.T(), 1, worker.Process(context.Background()))
+}
+
+func (t *EmailServiceTest) TestWorkerSendErrorDead() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+	transport := mock_email.NewMockTransport(controller)
+
+	t.outbox.Attempts = t.config.OutboxMaxAttempts
+
+	repository.EXPECT().ClaimDue(gomock.Any(), t.config.OutboxBatchSize, gomock.Any(), gomock.Any()).
+		SetArg(3, []*model.EmailOutbox{t.outbox}).Return(nil)
+	transport.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("Service unavailable"))
+	repository.EXPECT().MarkFailed(gomock.Any(), t.outbox.ID.String(), constant.DEAD, gomock.Any(), "Service unavailable").Return(nil)
+
+	worker := email.NewWorker(t.config, repository, transport)
+
+	assert.Equal(t.T(), 1, worker.Process(context.Background()))
+}
+
+func (t *EmailServiceTest) TestWorkerClaimError() {
+	controller := gomock.NewController(t.T())
+	repository := mock_email_repo.NewMockRepository(controller)
+	transport := mock_email.NewMockTransport(controller)
+
+	repository.EXPECT().ClaimDue(gomock.Any(), t.config.OutboxBatchSize, gomock.Any(), gomock.Any()).Return(errors.New("Connection lost"))
+
+	worker := email.NewWorker(t.config, repository, transport)
+
+	assert.Equal(t.T(), 0, worker.Process(context.Background()))
+}
+
+func (t *EmailServiceTest) TestBackoff() {
+	assert.Equal(t.T(), 30*time.Second, email.Backoff(1, 30, 3600))
+	assert.Equal(t.T(), 60*time.Second, email.Backoff(2, 30, 3600))
+	assert.Equal(t.T(), 240*time.Second, email.Backoff(4, 30, 3600))
+	assert.Equal(t.T(), time.Hour, email.Backoff(20, 30, 3600))
+}
+
+func (t *EmailServiceTest) TestRenderThai() {
+	renderer, err := email.NewRenderer()
+	assert.Nil(t.T(), err)
+
+	data := &email.AdoptionStatusData{Name: "Somchai", PetName: "Mali"}
+	rendered, err := renderer.Render(constant.AdoptionStatusTemplate, constant.TH, data)
+
+	assert.Nil(t.T(), err)
+	assert.Contains(t.T(), rendered.Html, `lang="th"`)
+	assert.Contains(t.T(), rendered.Text, "Mali")
+	assert.NotEqual(t.T(), "", rendered.Subject)
+}
+
+func (t *EmailServiceTest) TestRenderFallbackLocale() {
+	renderer, err := email.NewRenderer()
+	assert.Nil(t.T(), err)
+
+	data := email.SampleData[constant.VerifyEmailTemplate]
+	expected, err := renderer.Render(constant.VerifyEmailTemplate, constant.DefaultLocale, data)
+	assert.Nil(t.T(), err)
+
+	actual, err := renderer.Render(constant.VerifyEmailTemplate, "fr", data)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), expected, actual)
+}
+
+func (t *EmailServiceTest) TestRenderEscapeHtml() {
+	renderer, err := email.NewRenderer()
+	assert.Nil(t.T(), err)
+
+	data := &email.AdoptionStatusData{Name: "<script>", PetName: "Mali"}
+	rendered, err := renderer.Render(constant.AdoptionStatusTemplate, constant.EN, data)
+
+	assert.Nil(t.T(), err)
+	assert.NotContains(t.T(), rendered.Html, "<script>")
+	assert.Contains(t.T(), rendered.Text, "<script>")
+}
+
+func (t *EmailServiceTest) TestPreviewSuccess() {
+	renderer, err := email.NewRenderer()
+	assert.Nil(t.T(), err)
+
+	service := email.NewService(t.config, nil, renderer)
+	actual, apperr := service.Preview(context.Background(), constant.VerifyEmailTemplate, constant.TH)
+
+	assert.Nil(t.T(), apperr)
+	assert.Equal(t.T(), string(constant.VerifyEmailTemplate), actual.Template)
+	assert.Equal(t.T(), string(constant.TH), actual.Locale)
+	assert.NotEqual(t.T(), "", actual.Subject)
+	assert.NotEqual(t.T(), "", actual.Html)
+}
+
+func (t *EmailServiceTest) TestPreviewInvalidTemplate() {
+	renderer, err := email.NewRenderer()
+	assert.Nil(t.T(), err)
+
+	service := email.NewService(t.config, nil, renderer)
+	actual, apperr := service.Preview(context.Background(), "unknown", constant.EN)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, apperr.StatusCode)
+	assert.Equal(t.T(), constant.InvalidEmailTemplateErrorMessage, apperr.Message)
+}
+
+func (t *EmailServiceTest) TestPreviewInvalidLocale() {
+	renderer, err := email.NewRenderer()
+	assert.Nil(t.T(), err)
+
+	service := email.NewService(t.config, nil, renderer)
+	actual, apperr := service.Preview(context.Background(), constant.VerifyEmailTemplate, "fr")
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, apperr.StatusCode)
+	assert.Equal(t.T(), constant.InvalidLocaleErrorMessage, apperr.Message)
+}
+
+func (t *EmailServiceTest) TestNewTransportUnknown() {
+	transport, err := email.NewTransport(config.Email{Transport: "pigeon"}, config.Sendgrid{}, config.Smtp{})
+
+	assert.Nil(t.T(), transport)
+	assert.NotNil(t.T(), err)
+}
+
+func (t *EmailServiceTest) TestFileTransportSuccess() {
+	dir := t.T().TempDir()
+	transport, err := email.NewTransport(config.Email{Transport: "file", SinkDir: dir}, config.Sendgrid{}, config.Smtp{})
+	assert.Nil(t.T(), err)
+
+	err = transport.Send(context.Background(), t.message)
+	assert.Nil(t.T(), err)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.Len(t.T(), files, 1)
+
+	data, _ := os.ReadFile(files[0])
+	assert.Contains(t.T(), string(data), "Subject: Reset Password Request")
+	assert.Contains(t.T(), string(data), t.message.ToAddress)
+}
+
+func (t *EmailServiceTest) TestFormatMessageWithHtml() {
+	t.message.Html = "<p>Please click</p>"
+
+	data, err := email.FormatMessage(t.message)
+
+	assert.Nil(t.T(), err)
+	assert.Contains(t.T(), string(data), "multipart/alternative")
+	assert.Contains(t.T(), string(data), "text/plain; charset=utf-8")
+	assert.Contains(t.T(), string(data), "text/html; charset=utf-8")
+}
+
+func (t *EmailServiceTest) TestSmtpTransportSuccess() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t.T(), err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go serveSmtp(listener, received)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	transport, err := email.NewTransport(config.Email{Transport: "smtp"}, config.Sendgrid{}, config.Smtp{Host: "127.0.0.1", Port: port})
+	assert.Nil(t.T(), err)
+
+	err = transport.Send(context.Background(), t.message)
+
+	assert.Nil(t.T(), err)
+	assert.Contains(t.T(), <-received, "Subject: Reset Password Request")
+}
+
+// serveSmtp accepts a single message with the smallest set of commands net/smtp needs
+func serveSmtp(listener net.Listener, received chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "MAIL", "RCPT", "RSET", "NOOP":
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			lines, err := text.ReadDotLines()
+			if err != nil {
+				return
+			}
+			received <- strings.Join(lines, "\n")
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("502 Command not implemented")
+		}
+	}
+}
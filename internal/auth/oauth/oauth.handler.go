@@ -0,0 +1,49 @@
+package oauth
+
+import (
+	"net/http"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/router"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service}
+}
+
+// Authorize is a function that starts the sign in with an oauth provider
+// @Summary starts oauth sign in
+// @Description Returns the consent page url of the provider, the provider redirects back with the code and state that are posted to /auth/oauth/callback
+// @Param provider query string true "provider name, e.g. google, line or facebook"
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.OAuthAuthorizeResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid provider"
+// @Failure 404 {object} dto.ResponseNotfoundErr "Provider not found"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/oauth/authorize [get]
+func (h *Handler) Authorize(c router.IContext) {
+	provider := c.Queries()["provider"]
+	if provider == "" {
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.InvalidRequestBodyMessage + "provider is required",
+			Data:       nil,
+		})
+		return
+	}
+
+	response, respErr := h.service.Authorize(c.UserContext(), provider)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,330 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/pkg/errors"
+)
+
+// Identity is the account of the user at the provider
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Firstname     string
+	Lastname      string
+}
+
+type provider struct {
+	name        string
+	config      config.OAuthProvider
+	redirectURL string
+	client      *http.Client
+
+	mu        sync.Mutex
+	endpoints *endpoints
+}
+
+type endpoints struct {
+	Issuer      string `json:"issuer"`
+	AuthURL     string `json:"authorization_endpoint"`
+	TokenURL    string `json:"token_endpoint"`
+	UserInfoURL string `json:"userinfo_endpoint"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IdToken     string `json:"id_token"`
+}
+
+// claims covers both the oidc standard claims and the fields of providers that are only oauth2, e.g. facebook
+type claims struct {
+	Subject       string          `json:"sub"`
+	Id            string          `json:"id"`
+	Issuer        string          `json:"iss"`
+	Audience      json.RawMessage `json:"aud"`
+	ExpiresAt     int64           `json:"exp"`
+	Nonce         string          `json:"nonce"`
+	Email         string          `json:"email"`
+	EmailVerified interface{}     `json:"email_verified"`
+	Name          string          `json:"name"`
+	GivenName     string          `json:"given_name"`
+	FamilyName    string          `json:"family_name"`
+	FirstName     string          `json:"first_name"`
+	LastName      string          `json:"last_name"`
+}
+
+func newProvider(name string, config config.OAuthProvider, redirectURL string, client *http.Client) *provider {
+	return &provider{name: name, config: config, redirectURL: redirectURL, client: client}
+}
+
+func (p *provider) authCodeURL(ctx context.Context, state string, codeVerifier string, nonce string) (string, error) {
+	e, err := p.getEndpoints(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.redirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(e.AuthURL, "?") {
+		separator = "&"
+	}
+
+	return e.AuthURL + separator + query.Encode(), nil
+}
+
+// exchange trades the code for the tokens and reads the identity from the id token, or from the userinfo
+// endpoint when the provider does not return one
+func (p *provider) exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*Identity, error) {
+	e, err := p.getEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	token := &tokenResponse{}
+	if err := p.do(req, token); err != nil {
+		return nil, errors.Wrap(err, "token request")
+	}
+
+	var c *claims
+	if token.IdToken != "" {
+		c, err = p.verifyIdToken(token.IdToken, e.Issuer, nonce)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if (c == nil || c.Email == "") && e.UserInfoURL != "" {
+		if token.AccessToken == "" {
+			return nil, errors.New("no access token for the userinfo request")
+		}
+		info, err := p.userInfo(ctx, e.UserInfoURL, token.AccessToken)
+		if err != nil {
+			return nil, errors.Wrap(err, "userinfo request")
+		}
+		// the subject of the userinfo response must be the same user as the id token
+		if c != nil && info.subject() != c.subject() {
+			return nil, errors.New("userinfo subject does not match the id token")
+		}
+		c = info
+	}
+
+	if c == nil || c.subject() == "" {
+		return nil, errors.New("provider returned no subject")
+	}
+
+	return p.toIdentity(c), nil
+}
+
+// verifyIdToken checks the claims of an id token received directly from the token endpoint, the tls connection
+// authenticates the issuer so the signature is not verified (OpenID Connect Core 3.1.3.7)
+func (p *provider) verifyIdToken(idToken string, issuer string, nonce string) (*claims, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed id token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed id token")
+	}
+
+	c := &claims{}
+	if err := json.Unmarshal(payload, c); err != nil {
+		return nil, errors.Wrap(err, "malformed id token")
+	}
+
+	if issuer != "" && c.Issuer != issuer {
+		return nil, errors.New("id token issuer mismatch")
+	}
+	if !c.hasAudience(p.config.ClientID) {
+		return nil, errors.New("id token audience mismatch")
+	}
+	if c.ExpiresAt != 0 && time.Now().Unix() > c.ExpiresAt {
+		return nil, errors.New("id token expired")
+	}
+	if c.Nonce != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+
+	return c, nil
+}
+
+func (p *provider) userInfo(ctx context.Context, userInfoURL string, accessToken string) (*claims, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	c := &claims{}
+	if err := p.do(req, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// getEndpoints uses the configured urls, falling back to the discovery document of the issuer
+func (p *provider) getEndpoints(ctx context.Context) (*endpoints, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.endpoints != nil {
+		return p.endpoints, nil
+	}
+
+	e := &endpoints{
+		Issuer:      p.config.Issuer,
+		AuthURL:     p.config.AuthURL,
+		TokenURL:    p.config.TokenURL,
+		UserInfoURL: p.config.UserInfoURL,
+	}
+
+	if p.config.Issuer != "" && (e.AuthURL == "" || e.TokenURL == "") {
+		discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		discovered := &endpoints{}
+		if err := p.do(req, discovered); err != nil {
+			return nil, errors.Wrap(err, "discovery request")
+		}
+		if discovered.Issuer != "" && discovered.Issuer != p.config.Issuer {
+			return nil, errors.New("discovered issuer mismatch")
+		}
+
+		if e.AuthURL == "" {
+			e.AuthURL = discovered.AuthURL
+		}
+		if e.TokenURL == "" {
+			e.TokenURL = discovered.TokenURL
+		}
+		if e.UserInfoURL == "" {
+			e.UserInfoURL = discovered.UserInfoURL
+		}
+	}
+
+	if e.AuthURL == "" || e.TokenURL == "" {
+		return nil, errors.New(fmt.Sprintf("oauth provider %s has no authorization or token endpoint", p.name))
+	}
+
+	p.endpoints = e
+	return e, nil
+}
+
+func (p *provider) do(req *http.Request, v interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(fmt.Sprintf("%d status code: %s", resp.StatusCode, body))
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+func (p *provider) toIdentity(c *claims) *Identity {
+	identity := &Identity{
+		Subject:   c.subject(),
+		Email:     strings.ToLower(strings.TrimSpace(c.Email)),
+		Firstname: c.GivenName,
+		Lastname:  c.FamilyName,
+	}
+
+	switch verified := c.EmailVerified.(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	default:
+		identity.EmailVerified = p.config.TrustEmail
+	}
+
+	if identity.Firstname == "" {
+		identity.Firstname = c.FirstName
+	}
+	if identity.Lastname == "" {
+		identity.Lastname = c.LastName
+	}
+	if identity.Firstname == "" && c.Name != "" {
+		names := strings.SplitN(strings.TrimSpace(c.Name), " ", 2)
+		identity.Firstname = names[0]
+		if len(names) > 1 && identity.Lastname == "" {
+			identity.Lastname = names[1]
+		}
+	}
+
+	return identity
+}
+
+func (c *claims) subject() string {
+	if c.Subject != "" {
+		return c.Subject
+	}
+	return c.Id
+}
+
+func (c *claims) hasAudience(clientId string) bool {
+	var single string
+	if err := json.Unmarshal(c.Audience, &single); err == nil {
+		return single == clientId
+	}
+
+	var many []string
+	if err := json.Unmarshal(c.Audience, &many); err == nil {
+		for _, aud := range many {
+			if aud == clientId {
+				return true
+			}
+		}
+	}
+
+	return false
+}
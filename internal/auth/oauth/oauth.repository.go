@@ -0,0 +1,46 @@
+package oauth
+
+import (
+	"context"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindByProviderAndSubject(ctx context.Context, provider string, subject string, identity *model.OAuthIdentity) error
+	Create(ctx context.Context, identity *model.OAuthIdentity) error
+	CreateWithUser(ctx context.Context, user *model.User, identity *model.OAuthIdentity) error
+	Delete(ctx context.Context, id string) error
+}
+
+type repositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repositoryImpl{Db: db}
+}
+
+func (r *repositoryImpl) FindByProviderAndSubject(ctx context.Context, provider string, subject string, identity *model.OAuthIdentity) error {
+	return r.Db.WithContext(ctx).First(identity, "provider = ? AND subject = ?", provider, subject).Error
+}
+
+func (r *repositoryImpl) Create(ctx context.Context, identity *model.OAuthIdentity) error {
+	return r.Db.WithContext(ctx).Create(identity).Error
+}
+
+// CreateWithUser signs up a new user together with the identity
+func (r *repositoryImpl) CreateWithUser(ctx context.Context, user *model.User, identity *model.OAuthIdentity) error {
+	return r.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
+
+func (r *repositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.Db.WithContext(ctx).Unscoped().Delete(&model.OAuthIdentity{}, "id = ?", id).Error
+}
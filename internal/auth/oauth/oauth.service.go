@@ -0,0 +1,207 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/cache"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/user"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Authorize(ctx context.Context, provider string) (*dto.OAuthAuthorizeResponse, *dto.ResponseErr)
+	Authenticate(ctx context.Context, request *dto.OAuthCallbackRequest) (*model.User, *dto.ResponseErr)
+}
+
+type serviceImpl struct {
+	config     config.OAuth
+	repository Repository
+	userRepo   user.Repository
+	stateCache cache.Repository
+	providers  map[string]*provider
+}
+
+func NewService(config config.OAuth, repository Repository, userRepo user.Repository, stateCache cache.Repository, client *http.Client) Service {
+	providers := map[string]*provider{}
+	for name, providerConfig := range config.Providers {
+		providers[name] = newProvider(name, providerConfig, config.RedirectURL, client)
+	}
+
+	return &serviceImpl{
+		config:     config,
+		repository: repository,
+		userRepo:   userRepo,
+		stateCache: stateCache,
+		providers:  providers,
+	}
+}
+
+// Authorize returns the url of the consent page of the provider, the state and the pkce verifier are kept
+// in the cache until the callback
+func (s *serviceImpl) Authorize(ctx context.Context, providerName string) (*dto.OAuthAuthorizeResponse, *dto.ResponseErr) {
+	p, ok := s.providers[providerName]
+	if !ok {
+		return nil, dto.NotFoundError(constant.OAuthProviderNotFoundErrorMessage)
+	}
+
+	values, err := randomStrings(3)
+	if err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+	state, codeVerifier, nonce := values[0], values[1], values[2]
+
+	authURL, err := p.authCodeURL(ctx, state, codeVerifier, nonce)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "oauth").
+			Str("module", "authorize").
+			Str("provider", providerName).
+			Msg("Error building authorization url")
+		return nil, dto.ServiceUnavailableError(constant.OAuthProviderErrorMessage)
+	}
+
+	stateCache := &dto.OAuthStateCache{
+		Provider:     providerName,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+	}
+	if err := s.stateCache.SetValue(ctx, stateKey(state), stateCache, s.config.StateTTL); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "oauth").
+			Str("module", "authorize").
+			Msg("Error saving oauth state")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return &dto.OAuthAuthorizeResponse{URL: authURL}, nil
+}
+
+// Authenticate exchanges the code and returns the user linked to the identity. Identities are linked by their
+// verified email and a new user is signed up when no user has the email
+func (s *serviceImpl) Authenticate(ctx context.Context, request *dto.OAuthCallbackRequest) (*model.User, *dto.ResponseErr) {
+	// the state is taken out in one step so it is single use even for concurrent callbacks or a failed exchange
+	state := &dto.OAuthStateCache{}
+	if err := s.stateCache.PopValue(ctx, stateKey(request.State), state); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, dto.UnauthorizedError(constant.InvalidOAuthStateErrorMessage)
+		}
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	p, ok := s.providers[state.Provider]
+	if !ok {
+		return nil, dto.UnauthorizedError(constant.InvalidOAuthStateErrorMessage)
+	}
+
+	identity, err := p.exchange(ctx, request.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Warn().Ctx(ctx).Err(err).
+			Str("service", "oauth").
+			Str("module", "authenticate").
+			Str("provider", state.Provider).
+			Msg("Error exchanging oauth code")
+		return nil, dto.UnauthorizedError(constant.OAuthProviderErrorMessage)
+	}
+
+	return s.findOrCreateUser(ctx, state.Provider, identity)
+}
+
+func (s *serviceImpl) findOrCreateUser(ctx context.Context, providerName string, identity *Identity) (*model.User, *dto.ResponseErr) {
+	linked := &model.OAuthIdentity{}
+	err := s.repository.FindByProviderAndSubject(ctx, providerName, identity.Subject, linked)
+	if err == nil {
+		user := &model.User{}
+		err = s.userRepo.FindById(ctx, linked.UserID.String(), user)
+		if err == nil {
+			return user, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+
+		// the user was deleted, the identity is linked again below
+		if err := s.repository.Delete(ctx, linked.ID.String()); err != nil {
+			return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, dto.ForbiddenError(constant.OAuthEmailNotVerifiedErrorMessage)
+	}
+
+	newIdentity := &model.OAuthIdentity{
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	user := &model.User{}
+	err = s.userRepo.FindByEmail(ctx, identity.Email, user)
+	if err == nil {
+		newIdentity.UserID = user.ID
+		if err := s.repository.Create(ctx, newIdentity); err != nil {
+			log.Error().Ctx(ctx).Err(err).
+				Str("service", "oauth").
+				Str("module", "link").
+				Str("provider", providerName).
+				Str("user_id", user.ID.String()).
+				Msg("Error linking oauth identity")
+			return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	// the user has no password until one is set with forgot password
+	user = &model.User{
+		Email:     identity.Email,
+		Firstname: identity.Firstname,
+		Lastname:  identity.Lastname,
+		Role:      constant.USER,
+	}
+	if err := s.repository.CreateWithUser(ctx, user, newIdentity); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, dto.ConflictError(constant.DuplicateEmailErrorMessage)
+		}
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "oauth").
+			Str("module", "signup").
+			Str("provider", providerName).
+			Msg("Error creating user from oauth identity")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return user, nil
+}
+
+func randomStrings(count int) ([]string, error) {
+	var values []string
+	for i := 0; i < count; i++ {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		values = append(values, base64.RawURLEncoding.EncodeToString(raw))
+	}
+
+	return values, nil
+}
+
+func stateKey(state string) string {
+	return fmt.Sprintf("oauth:state:%s", state)
+}
@@ -0,0 +1,348 @@
+package test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/oauth"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	mock_cache "github.com/isd-sgcu/johnjud-backend/mocks/repository/cache"
+	mock_oauth "github.com/isd-sgcu/johnjud-backend/mocks/repository/oauth"
+	user_mock "github.com/isd-sgcu/johnjud-backend/mocks/repository/user"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+const (
+	clientId  = "johnjud"
+	validCode = "valid-code"
+)
+
+// mockProvider is a minimal oidc provider with discovery, token and userinfo endpoints
+type mockProvider struct {
+	server        *httptest.Server
+	subject       string
+	email         string
+	emailVerified bool
+	// withIdToken is false for providers that are only oauth2
+	withIdToken bool
+	challenge   string
+	nonce       string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	p := &mockProvider{
+		subject:       faker.UUIDDigit(),
+		email:         "adopter@example.com",
+		emailVerified: true,
+		withIdToken:   true,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"userinfo_endpoint":      p.server.URL + "/userinfo",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != validCode || r.PostForm.Get("client_id") != clientId ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != p.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		response := map[string]string{"access_token": "access-token", "token_type": "Bearer"}
+		if p.withIdToken {
+			response["id_token"] = p.idToken()
+		}
+		json.NewEncoder(w).Encode(response)
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"id":         p.subject,
+			"email":      p.email,
+			"first_name": "Somchai",
+			"last_name":  "Jaidee",
+		})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+func (p *mockProvider) idToken() string {
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":            p.server.URL,
+		"aud":            clientId,
+		"sub":            p.subject,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"nonce":          p.nonce,
+		"email":          p.email,
+		"email_verified": p.emailVerified,
+		"given_name":     "Somchai",
+		"family_name":    "Jaidee",
+	})
+	encode := base64.RawURLEncoding.EncodeToString
+
+	return encode([]byte(`{"alg":"RS256"}`)) + "." + encode(claims) + "." + encode([]byte("signature"))
+}
+
+type OAuthServiceTest struct {
+	suite.Suite
+	provider *mockProvider
+	config   config.OAuth
+	user     *model.User
+}
+
+func TestOAuthService(t *testing.T) {
+	suite.Run(t, new(OAuthServiceTest))
+}
+
+func (t *OAuthServiceTest) SetupTest() {
+	t.provider = newMockProvider(t.T())
+	t.config = config.OAuth{
+		RedirectURL: "http://localhost:3000/oauth/callback",
+		StateTTL:    600,
+		Providers: map[string]config.OAuthProvider{
+			"mock": {
+				ClientID:     clientId,
+				ClientSecret: "secret",
+				Issuer:       t.provider.server.URL,
+				Scopes:       []string{"openid", "email", "profile"},
+			},
+			"graph": {
+				ClientID:     clientId,
+				ClientSecret: "secret",
+				AuthURL:      t.provider.server.URL + "/authorize",
+				TokenURL:     t.provider.server.URL + "/token",
+				UserInfoURL:  t.provider.server.URL + "/userinfo?fields=id,email,first_name,last_name",
+				TrustEmail:   true,
+			},
+		},
+	}
+	t.user = &model.User{
+		Base:      model.Base{ID: uuid.New()},
+		Email:     t.provider.email,
+		Firstname: faker.FirstName(),
+		Lastname:  faker.LastName(),
+		Role:      constant.USER,
+	}
+}
+
+// authorize runs the authorize step and returns the callback request the client would post back
+func (t *OAuthServiceTest) authorize(service oauth.Service, cache *mock_cache.MockRepository, providerName string) *dto.OAuthCallbackRequest {
+	saved := &dto.OAuthStateCache{}
+	cache.EXPECT().SetValue(gomock.Any(), gomock.Any(), gomock.Any(), t.config.StateTTL).DoAndReturn(func(_ context.Context, key string, value interface{}, ttl int) error {
+		*saved = *value.(*dto.OAuthStateCache)
+		return nil
+	})
+
+	response, err := service.Authorize(context.Background(), providerName)
+	assert.Nil(t.T(), err)
+
+	authURL, _ := url.Parse(response.URL)
+	query := authURL.Query()
+	assert.Equal(t.T(), t.provider.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	assert.Equal(t.T(), "S256", query.Get("code_challenge_method"))
+	assert.Equal(t.T(), t.config.RedirectURL, query.Get("redirect_uri"))
+	assert.Equal(t.T(), saved.Nonce, query.Get("nonce"))
+
+	t.provider.challenge = query.Get("code_challenge")
+	t.provider.nonce = saved.Nonce
+
+	state := query.Get("state")
+	cache.EXPECT().PopValue(gomock.Any(), "oauth:state:"+state, gomock.Any()).DoAndReturn(func(_ context.Context, key string, value interface{}) error {
+		*value.(*dto.OAuthStateCache) = *saved
+		return nil
+	})
+
+	return &dto.OAuthCallbackRequest{State: state, Code: validCode}
+}
+
+func (t *OAuthServiceTest) TestAuthorizeProviderNotFound() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+	repo := mock_oauth.NewMockRepository(controller)
+
+	service := oauth.NewService(t.config, repo, &user_mock.UserRepositoryMock{}, cache, http.DefaultClient)
+	actual, err := service.Authorize(context.Background(), "unknown")
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
+}
+
+func (t *OAuthServiceTest) TestAuthenticateLinkedIdentity() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+	repo := mock_oauth.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	identity := &model.OAuthIdentity{UserID: t.user.ID, Provider: "mock", Subject: t.provider.subject}
+	repo.EXPECT().FindByProviderAndSubject(gomock.Any(), "mock", t.provider.subject, gomock.Any()).SetArg(3, *identity).Return(nil)
+	userRepo.On("FindById", testifyMock.Anything, t.user.ID.String(), &model.User{}).Return(t.user, nil)
+
+	service := oauth.NewService(t.config, repo, userRepo, cache, http.DefaultClient)
+	request := t.authorize(service, cache, "mock")
+	actual, err := service.Authenticate(context.Background(), request)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), t.user, actual)
+}
+
+func (t *OAuthServiceTest) TestAuthenticateLinkByVerifiedEmail() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+	repo := mock_oauth.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	repo.EXPECT().FindByProviderAndSubject(gomock.Any(), "mock", t.provider.subject, gomock.Any()).Return(gorm.ErrRecordNotFound)
+	userRepo.On("FindByEmail", testifyMock.Anything, t.provider.email, &model.User{}).Return(t.user, nil)
+	repo.EXPECT().Create(gomock.Any(), &model.OAuthIdentity{
+		UserID:   t.user.ID,
+		Provider: "mock",
+		Subject:  t.provider.subject,
+		Email:    t.provider.email,
+	}).Return(nil)
+
+	service := oauth.NewService(t.config, repo, userRepo, cache, http.DefaultClient)
+	request := t.authorize(service, cache, "mock")
+	actual, err := service.Authenticate(context.Background(), request)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), t.user, actual)
+}
+
+func (t *OAuthServiceTest) TestAuthenticateSignUpNewUser() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+	repo := mock_oauth.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	repo.EXPECT().FindByProviderAndSubject(gomock.Any(), "mock", t.provider.subject, gomock.Any()).Return(gorm.ErrRecordNotFound)
+	userRepo.On("FindByEmail", testifyMock.Anything, t.provider.email, &model.User{}).Return(nil, gorm.ErrRecordNotFound)
+	repo.EXPECT().CreateWithUser(gomock.Any(), &model.User{
+		Email:     t.provider.email,
+		Firstname: "Somchai",
+		Lastname:  "Jaidee",
+		Role:      constant.USER,
+	}, gomock.Any()).Return(nil)
+
+	service := oauth.NewService(t.config, repo, userRepo, cache, http.DefaultClient)
+	request := t.authorize(service, cache, "mock")
+	actual, err := service.Authenticate(context.Background(), request)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), t.provider.email, actual.Email)
+	assert.Equal(t.T(), constant.USER, actual.Role)
+}
+
+func (t *OAuthServiceTest) TestAuthenticateEmailNotVerified() {
+	t.provider.emailVerified = false
+
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+	repo := mock_oauth.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	repo.EXPECT().FindByProviderAndSubject(gomock.Any(), "mock", t.provider.subject, gomock.Any()).Return(gorm.ErrRecordNotFound)
+
+	service := oauth.NewService(t.config, repo, userRepo, cache, http.DefaultClient)
+	request := t.authorize(service, cache, "mock")
+	actual, err := service.Authenticate(context.Background(), request)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusForbidden, err.StatusCode)
+	assert.Equal(t.T(), constant.OAuthEmailNotVerifiedErrorMessage, err.Message)
+}
+
+func (t *OAuthServiceTest) TestAuthenticateUserInfoOnlyProvider() {
+	t.provider.withIdToken = false
+
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+	repo := mock_oauth.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	repo.EXPECT().FindByProviderAndSubject(gomock.Any(), "graph", t.provider.subject, gomock.Any()).Return(gorm.ErrRecordNotFound)
+	userRepo.On("FindByEmail", testifyMock.Anything, t.provider.email, &model.User{}).Return(t.user, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	service := oauth.NewService(t.config, repo, userRepo, cache, http.DefaultClient)
+	request := t.authorize(service, cache, "graph")
+	actual, err := service.Authenticate(context.Background(), request)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), t.user, actual)
+}
+
+func (t *OAuthServiceTest) TestAuthenticateNonceMismatch() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+	repo := mock_oauth.NewMockRepository(controller)
+
+	service := oauth.NewService(t.config, repo, &user_mock.UserRepositoryMock{}, cache, http.DefaultClient)
+	request := t.authorize(service, cache, "mock")
+	t.provider.nonce = "replayed"
+	actual, err := service.Authenticate(context.Background(), request)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusUnauthorized, err.StatusCode)
+	assert.Equal(t.T(), constant.OAuthProviderErrorMessage, err.Message)
+}
+
+func (t *OAuthServiceTest) TestAuthenticateInvalidCode() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+	repo := mock_oauth.NewMockRepository(controller)
+
+	service := oauth.NewService(t.config, repo, &user_mock.UserRepositoryMock{}, cache, http.DefaultClient)
+	request := t.authorize(service, cache, "mock")
+	request.Code = "stolen-code"
+	actual, err := service.Authenticate(context.Background(), request)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusUnauthorized, err.StatusCode)
+}
+
+func (t *OAuthServiceTest) TestAuthenticateInvalidState() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+	repo := mock_oauth.NewMockRepository(controller)
+
+	cache.EXPECT().PopValue(gomock.Any(), "oauth:state:unknown", gomock.Any()).Return(redis.Nil)
+
+	service := oauth.NewService(t.config, repo, &user_mock.UserRepositoryMock{}, cache, http.DefaultClient)
+	actual, err := service.Authenticate(context.Background(), &dto.OAuthCallbackRequest{State: "unknown", Code: validCode})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusUnauthorized, err.StatusCode)
+	assert.Equal(t.T(), constant.InvalidOAuthStateErrorMessage, err.Message)
+}
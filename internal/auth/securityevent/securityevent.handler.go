@@ -0,0 +1,113 @@
+package securityevent
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/router"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service}
+}
+
+// FindMine is a function that returns the recent security events of the signed in user
+// @Summary finds own security events
+// @Description Returns the sign ins, failed attempts, password resets and sign outs of the user, newest first
+// @Param type query string false "event type, e.g. signin or reset_password"
+// @Param success query bool false "only successful or failed events"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.FindAllSecurityEventResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid query"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/security-events [get]
+func (h *Handler) FindMine(c router.IContext) {
+	request, err := QueriesToFindAllDto(c.Queries())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequestError(err.Error()))
+		return
+	}
+
+	response, respErr := h.service.FindByUser(c.UserContext(), c.UserID(), request)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// FindAll is a function that returns the security events of all users
+// @Summary finds security events
+// @Description Returns the security events across users, newest first
+// @Param userId query string false "user id"
+// @Param type query string false "event type, e.g. signin or reset_password"
+// @Param success query bool false "only successful or failed events"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.FindAllSecurityEventResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid query"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/admin/security-events [get]
+func (h *Handler) FindAll(c router.IContext) {
+	request, err := QueriesToFindAllDto(c.Queries())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequestError(err.Error()))
+		return
+	}
+
+	response, respErr := h.service.FindAll(c.UserContext(), request)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func QueriesToFindAllDto(queries map[string]string) (*dto.FindAllSecurityEventRequest, error) {
+	request := &dto.FindAllSecurityEventRequest{}
+
+	for q, v := range queries {
+		switch q {
+		case "userId":
+			request.UserId = v
+		case "type":
+			request.Type = constant.SecurityEventType(v)
+		case "success":
+			success, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.New("error parsing success")
+			}
+			request.Success = &success
+		case "page":
+			page, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errors.New("error parsing page")
+			}
+			request.Page = page
+		case "pageSize":
+			pageSize, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errors.New("error parsing pageSize")
+			}
+			request.PageSize = pageSize
+		}
+	}
+
+	return request, nil
+}
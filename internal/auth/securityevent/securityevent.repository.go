@@ -0,0 +1,49 @@
+package securityevent
+
+import (
+	"context"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, event *model.SecurityEvent) error
+	FindAll(ctx context.Context, filter *dto.FindAllSecurityEventRequest, limit int, offset int, result *[]*model.SecurityEvent, total *int64) error
+	FindByUserId(ctx context.Context, userId string, result *[]*model.SecurityEvent) error
+}
+
+type repositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repositoryImpl{Db: db}
+}
+
+func (r *repositoryImpl) Create(ctx context.Context, event *model.SecurityEvent) error {
+	return r.Db.WithContext(ctx).Create(event).Error
+}
+
+func (r *repositoryImpl) FindAll(ctx context.Context, filter *dto.FindAllSecurityEventRequest, limit int, offset int, result *[]*model.SecurityEvent, total *int64) error {
+	query := r.Db.WithContext(ctx).Model(&model.SecurityEvent{})
+	if filter.UserId != "" {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+
+	if err := query.Count(total).Error; err != nil {
+		return err
+	}
+
+	return query.Order("created_at desc").Limit(limit).Offset(offset).Find(result).Error
+}
+
+func (r *repositoryImpl) FindByUserId(ctx context.Context, userId string, result *[]*model.SecurityEvent) error {
+	return r.Db.WithContext(ctx).Where("user_id = ?", userId).Order("created_at desc").Find(result).Error
+}
@@ -0,0 +1,169 @@
+package securityevent
+
+import (
+	"context"
+	"math"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	// user agents are sent by the client so they are cut to this many characters before being stored
+	maxUserAgentLength = 512
+)
+
+type Service interface {
+	Record(ctx context.Context, eventType constant.SecurityEventType, userId string, email string, meta *dto.RequestMeta, success bool)
+	FindByUser(ctx context.Context, userId string, request *dto.FindAllSecurityEventRequest) (*dto.FindAllSecurityEventResponse, *dto.ResponseErr)
+	FindAll(ctx context.Context, request *dto.FindAllSecurityEventRequest) (*dto.FindAllSecurityEventResponse, *dto.ResponseErr)
+	FindAllByUser(ctx context.Context, userId string) ([]*dto.SecurityEvent, *dto.ResponseErr)
+}
+
+type serviceImpl struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &serviceImpl{repository: repository}
+}
+
+// Record saves the event, userId is empty when the email is not of any user. Errors are only logged so that
+// the audit log never fails the action itself. The event is saved even when the request is cancelled after the action
+func (s *serviceImpl) Record(ctx context.Context, eventType constant.SecurityEventType, userId string, email string, meta *dto.RequestMeta, success bool) {
+	event := &model.SecurityEvent{
+		Email:   email,
+		Type:    eventType,
+		Success: success,
+	}
+	if userId != "" {
+		id, err := uuid.Parse(userId)
+		if err == nil {
+			event.UserID = &id
+		}
+	}
+	if meta != nil {
+		event.IP = meta.IP
+		event.UserAgent = truncate(meta.UserAgent, maxUserAgentLength)
+	}
+
+	if err := s.repository.Create(context.WithoutCancel(ctx), event); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "security event").
+			Str("module", "record").
+			Str("type", string(eventType)).
+			Str("user_id", userId).
+			Msg("Error recording security event")
+	}
+}
+
+// FindByUser returns the recent activity of the user, newest first
+func (s *serviceImpl) FindByUser(ctx context.Context, userId string, request *dto.FindAllSecurityEventRequest) (*dto.FindAllSecurityEventResponse, *dto.ResponseErr) {
+	filter := *request
+	filter.UserId = userId
+
+	return s.FindAll(ctx, &filter)
+}
+
+func (s *serviceImpl) FindAll(ctx context.Context, request *dto.FindAllSecurityEventRequest) (*dto.FindAllSecurityEventResponse, *dto.ResponseErr) {
+	if request.UserId != "" {
+		if _, err := uuid.Parse(request.UserId); err != nil {
+			return nil, dto.BadRequestError(constant.InvalidIDMessage)
+		}
+	}
+	if request.Type != "" {
+		if _, ok := constant.SecurityEventTypes[request.Type]; !ok {
+			return nil, dto.BadRequestError(constant.InvalidSecurityEventTypeErrorMessage)
+		}
+	}
+
+	page := request.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := request.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	var events []*model.SecurityEvent
+	var total int64
+	err := s.repository.FindAll(ctx, request, pageSize, (page-1)*pageSize, &events, &total)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "security event").
+			Str("module", "find all").
+			Msg("Error querying security events")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	result := []*dto.SecurityEvent{}
+	for _, event := range events {
+		result = append(result, RawToDto(event))
+	}
+
+	return &dto.FindAllSecurityEventResponse{
+		Events: result,
+		Metadata: &dto.FindAllMetadata{
+			Page:       page,
+			TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+			PageSize:   pageSize,
+			Total:      int(total),
+		},
+	}, nil
+}
+
+// FindAllByUser returns every event of the user without paging, newest first, for the export of the user data
+func (s *serviceImpl) FindAllByUser(ctx context.Context, userId string) ([]*dto.SecurityEvent, *dto.ResponseErr) {
+	var events []*model.SecurityEvent
+	if err := s.repository.FindByUserId(ctx, userId, &events); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "security event").
+			Str("module", "find all by user").
+			Str("user_id", userId).
+			Msg("Error querying security events of user")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	result := []*dto.SecurityEvent{}
+	for _, event := range events {
+		result = append(result, RawToDto(event))
+	}
+
+	return result, nil
+}
+
+func RawToDto(in *model.SecurityEvent) *dto.SecurityEvent {
+	event := &dto.SecurityEvent{
+		Id:        in.ID.String(),
+		Email:     in.Email,
+		Type:      in.Type,
+		IP:        in.IP,
+		UserAgent: in.UserAgent,
+		Success:   in.Success,
+		CreatedAt: in.CreatedAt,
+	}
+	if in.UserID != nil {
+		event.UserId = in.UserID.String()
+	}
+
+	return event
+}
+
+// truncate cuts s to at most max runes so a multibyte character is never split
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	return string([]rune(s)[:max])
+}
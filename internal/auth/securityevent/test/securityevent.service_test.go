@@ -0,0 +1,221 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/securityevent"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	mock_securityevent "github.com/isd-sgcu/johnjud-backend/mocks/repository/securityevent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SecurityEventServiceTest struct {
+	suite.Suite
+	userId string
+	meta   *dto.RequestMeta
+	event  *model.SecurityEvent
+}
+
+func TestSecurityEventService(t *testing.T) {
+	suite.Run(t, new(SecurityEventServiceTest))
+}
+
+func (t *SecurityEventServiceTest) SetupTest() {
+	id := uuid.New()
+	t.userId = id.String()
+	t.meta = &dto.RequestMeta{IP: faker.IPv4(), UserAgent: faker.Word()}
+	t.event = &model.SecurityEvent{
+		Base:      model.Base{ID: uuid.New()},
+		UserID:    &id,
+		Email:     faker.Email(),
+		Type:      constant.SignInEvent,
+		IP:        t.meta.IP,
+		UserAgent: t.meta.UserAgent,
+		Success:   true,
+	}
+}
+
+func (t *SecurityEventServiceTest) TestRecordSuccess() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	var created *model.SecurityEvent
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *model.SecurityEvent) error {
+		created = event
+		return nil
+	})
+
+	service := securityevent.NewService(repo)
+	service.Record(context.Background(), constant.SignInEvent, t.userId, t.event.Email, t.meta, true)
+
+	assert.Equal(t.T(), t.userId, created.UserID.String())
+	assert.Equal(t.T(), t.event.Email, created.Email)
+	assert.Equal(t.T(), constant.SignInEvent, created.Type)
+	assert.Equal(t.T(), t.meta.IP, created.IP)
+	assert.Equal(t.T(), t.meta.UserAgent, created.UserAgent)
+	assert.True(t.T(), created.Success)
+}
+
+func (t *SecurityEventServiceTest) TestRecordUnknownUser() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	var created *model.SecurityEvent
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *model.SecurityEvent) error {
+		created = event
+		return nil
+	})
+
+	service := securityevent.NewService(repo)
+	service.Record(context.Background(), constant.SignInEvent, "", t.event.Email, &dto.RequestMeta{IP: t.meta.IP, UserAgent: strings.Repeat("a", 2000)}, false)
+
+	assert.Nil(t.T(), created.UserID)
+	assert.False(t.T(), created.Success)
+	assert.Len(t.T(), created.UserAgent, 512)
+}
+
+func (t *SecurityEventServiceTest) TestRecordMultibyteUserAgent() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	var created *model.SecurityEvent
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *model.SecurityEvent) error {
+		created = event
+		return nil
+	})
+
+	service := securityevent.NewService(repo)
+	service.Record(context.Background(), constant.SignInEvent, t.userId, t.event.Email, &dto.RequestMeta{IP: t.meta.IP, UserAgent: strings.Repeat("แมว", 300)}, true)
+
+	assert.True(t.T(), utf8.ValidString(created.UserAgent))
+	assert.Equal(t.T(), 512, utf8.RuneCountInString(created.UserAgent))
+}
+
+func (t *SecurityEventServiceTest) TestRecordError() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database is down"))
+
+	service := securityevent.NewService(repo)
+
+	assert.NotPanics(t.T(), func() {
+		service.Record(context.Background(), constant.SignOutEvent, t.userId, "", t.meta, true)
+	})
+}
+
+func (t *SecurityEventServiceTest) TestFindByUserSuccess() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	// the user id of the query cannot widen the search to other users
+	request := &dto.FindAllSecurityEventRequest{UserId: uuid.NewString(), Page: 2, PageSize: 1}
+	filter := &dto.FindAllSecurityEventRequest{UserId: t.userId, Page: 2, PageSize: 1}
+	repo.EXPECT().FindAll(gomock.Any(), filter, 1, 1, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *dto.FindAllSecurityEventRequest, _ int, _ int, result *[]*model.SecurityEvent, total *int64) error {
+			*result = []*model.SecurityEvent{t.event}
+			*total = 3
+			return nil
+		})
+
+	service := securityevent.NewService(repo)
+	actual, err := service.FindByUser(context.Background(), t.userId, request)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), []*dto.SecurityEvent{securityevent.RawToDto(t.event)}, actual.Events)
+	assert.Equal(t.T(), &dto.FindAllMetadata{Page: 2, TotalPages: 3, PageSize: 1, Total: 3}, actual.Metadata)
+}
+
+func (t *SecurityEventServiceTest) TestFindAllDefaultPage() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	success := false
+	request := &dto.FindAllSecurityEventRequest{Type: constant.SignInEvent, Success: &success, PageSize: 1000}
+	repo.EXPECT().FindAll(gomock.Any(), request, 100, 0, gomock.Any(), gomock.Any()).Return(nil)
+
+	service := securityevent.NewService(repo)
+	actual, err := service.FindAll(context.Background(), request)
+
+	assert.Nil(t.T(), err)
+	assert.Empty(t.T(), actual.Events)
+	assert.Equal(t.T(), 1, actual.Metadata.Page)
+	assert.Equal(t.T(), 100, actual.Metadata.PageSize)
+}
+
+func (t *SecurityEventServiceTest) TestFindAllInvalidType() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	service := securityevent.NewService(repo)
+	actual, err := service.FindAll(context.Background(), &dto.FindAllSecurityEventRequest{Type: "unknown"})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+	assert.Equal(t.T(), constant.InvalidSecurityEventTypeErrorMessage, err.Message)
+}
+
+func (t *SecurityEventServiceTest) TestFindAllInvalidUserId() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	service := securityevent.NewService(repo)
+	actual, err := service.FindAll(context.Background(), &dto.FindAllSecurityEventRequest{UserId: "not-a-uuid"})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+}
+
+func (t *SecurityEventServiceTest) TestFindAllInternalError() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	repo.EXPECT().FindAll(gomock.Any(), gomock.Any(), 20, 0, gomock.Any(), gomock.Any()).Return(errors.New("database is down"))
+
+	service := securityevent.NewService(repo)
+	actual, err := service.FindAll(context.Background(), &dto.FindAllSecurityEventRequest{})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
+}
+
+func (t *SecurityEventServiceTest) TestFindAllByUserSuccess() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	repo.EXPECT().FindByUserId(gomock.Any(), t.userId, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, result *[]*model.SecurityEvent) error {
+			*result = []*model.SecurityEvent{t.event}
+			return nil
+		})
+
+	service := securityevent.NewService(repo)
+	actual, err := service.FindAllByUser(context.Background(), t.userId)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), []*dto.SecurityEvent{securityevent.RawToDto(t.event)}, actual)
+}
+
+func (t *SecurityEventServiceTest) TestFindAllByUserInternalError() {
+	controller := gomock.NewController(t.T())
+	repo := mock_securityevent.NewMockRepository(controller)
+
+	repo.EXPECT().FindByUserId(gomock.Any(), t.userId, gomock.Any()).Return(errors.New("database is down"))
+
+	service := securityevent.NewService(repo)
+	actual, err := service.FindAllByUser(context.Background(), t.userId)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
+}
@@ -1,9 +1,11 @@
 package test
 
 import (
+	ctx "context"
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/go-faker/faker/v4"
 	"github.com/golang/mock/gomock"
@@ -24,6 +26,7 @@ type AuthHandlerTest struct {
 	refreshTokenRequest   *dto.RefreshTokenRequest
 	forgotPasswordRequest *dto.ForgotPasswordRequest
 	resetPasswordRequest  *dto.ResetPasswordRequest
+	requestMeta           *dto.RequestMeta
 	bindErr               error
 	validateErr           []*dto.BadReqErrResponse
 }
@@ -57,6 +60,7 @@ func (t *AuthHandlerTest) SetupTest() {
 	t.refreshTokenRequest = refreshTokenRequest
 	t.forgotPasswordRequest = forgotPasswordRequest
 	t.resetPasswordRequest = resetPasswordRequest
+	t.requestMeta = &dto.RequestMeta{IP: faker.IPv4(), UserAgent: faker.Word()}
 	t.bindErr = bindErr
 	t.validateErr = validateErr
 }
@@ -76,8 +80,9 @@ func (t *AuthHandlerTest) TestSignupSuccess() {
 	context := routerMock.NewMockIContext(controller)
 
 	context.EXPECT().Bind(t.signupRequest).Return(nil)
-	validator.EXPECT().Validate(t.signupRequest).Return(nil)
-	authSvc.EXPECT().Signup(t.signupRequest).Return(signupResponse, nil)
+	validator.EXPECT().Validate(gomock.Any(), t.signupRequest).Return(nil)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().Signup(gomock.Any(), t.signupRequest).Return(signupResponse, nil)
 	context.EXPECT().JSON(http.StatusOK, signupResponse)
 
 	handler := auth.NewHandler(authSvc, userSvc, validator)
@@ -122,7 +127,8 @@ func (t *AuthHandlerTest) TestSignupValidateFailed() {
 	context := routerMock.NewMockIContext(controller)
 
 	context.EXPECT().Bind(t.signupRequest).Return(nil)
-	validator.EXPECT().Validate(t.signupRequest).Return(t.validateErr)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	validator.EXPECT().Validate(gomock.Any(), t.signupRequest).Return(t.validateErr)
 	context.EXPECT().JSON(http.StatusBadRequest, errResponse)
 
 	handler := auth.NewHandler(authSvc, userSvc, validator)
@@ -145,8 +151,9 @@ func (t *AuthHandlerTest) TestSignupServiceError() {
 	context := routerMock.NewMockIContext(controller)
 
 	context.EXPECT().Bind(t.signupRequest).Return(nil)
-	validator.EXPECT().Validate(t.signupRequest).Return(nil)
-	authSvc.EXPECT().Signup(t.signupRequest).Return(nil, signupError)
+	validator.EXPECT().Validate(gomock.Any(), t.signupRequest).Return(nil)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().Signup(gomock.Any(), t.signupRequest).Return(nil, signupError)
 	context.EXPECT().JSON(http.StatusInternalServerError, signupError)
 
 	handler := auth.NewHandler(authSvc, userSvc, validator)
@@ -155,7 +162,40 @@ func (t *AuthHandlerTest) TestSignupServiceError() {
 }
 
 func (t *AuthHandlerTest) TestSignInSuccess() {
-	signInResponse := &dto.Credential{
+	signInResponse := &dto.SignInResponse{
+		Credential: &dto.Credential{
+			AccessToken:  faker.Word(),
+			RefreshToken: faker.UUIDDigit(),
+			ExpiresIn:    3600,
+		},
+	}
+
+	controller := gomock.NewController(t.T())
+
+	authSvc := authMock.NewMockService(controller)
+	userSvc := userMock.NewMockService(controller)
+	validator := validatorMock.NewMockIDtoValidator(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Bind(t.signInRequest).Return(nil)
+	validator.EXPECT().Validate(gomock.Any(), t.signInRequest).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().SignIn(gomock.Any(), t.signInRequest, t.requestMeta).Return(signInResponse, nil)
+	context.EXPECT().JSON(http.StatusOK, signInResponse)
+
+	handler := auth.NewHandler(authSvc, userSvc, validator)
+
+	handler.SignIn(context)
+}
+
+func (t *AuthHandlerTest) TestSignInTwoFactorSuccess() {
+	request := &dto.SignInTwoFactorRequest{
+		ChallengeToken: faker.UUIDDigit(),
+		Code:           "123456",
+	}
+	credential := &dto.Credential{
 		AccessToken:  faker.Word(),
 		RefreshToken: faker.UUIDDigit(),
 		ExpiresIn:    3600,
@@ -168,14 +208,98 @@ func (t *AuthHandlerTest) TestSignInSuccess() {
 	validator := validatorMock.NewMockIDtoValidator(controller)
 	context := routerMock.NewMockIContext(controller)
 
-	context.EXPECT().Bind(t.signInRequest).Return(nil)
-	validator.EXPECT().Validate(t.signInRequest).Return(nil)
-	authSvc.EXPECT().SignIn(t.signInRequest).Return(signInResponse, nil)
+	context.EXPECT().Bind(&dto.SignInTwoFactorRequest{}).SetArg(0, *request).Return(nil)
+	validator.EXPECT().Validate(gomock.Any(), request).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().SignInTwoFactor(gomock.Any(), request, t.requestMeta).Return(credential, nil)
+	context.EXPECT().JSON(http.StatusOK, credential)
+
+	handler := auth.NewHandler(authSvc, userSvc, validator)
+
+	handler.SignInTwoFactor(context)
+}
+
+func (t *AuthHandlerTest) TestSignInOAuthSuccess() {
+	request := &dto.OAuthCallbackRequest{
+		State: faker.UUIDDigit(),
+		Code:  faker.Word(),
+	}
+	signInResponse := &dto.SignInResponse{
+		Credential: &dto.Credential{
+			AccessToken:  faker.Word(),
+			RefreshToken: faker.UUIDDigit(),
+			ExpiresIn:    3600,
+		},
+	}
+
+	controller := gomock.NewController(t.T())
+
+	authSvc := authMock.NewMockService(controller)
+	userSvc := userMock.NewMockService(controller)
+	validator := validatorMock.NewMockIDtoValidator(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Bind(&dto.OAuthCallbackRequest{}).SetArg(0, *request).Return(nil)
+	validator.EXPECT().Validate(gomock.Any(), request).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().SignInOAuth(gomock.Any(), request, t.requestMeta).Return(signInResponse, nil)
 	context.EXPECT().JSON(http.StatusOK, signInResponse)
 
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
-	handler.SignIn(context)
+	handler.SignInOAuth(context)
+}
+
+func (t *AuthHandlerTest) TestRequestMagicLinkSuccess() {
+	request := &dto.MagicLinkRequest{Email: faker.Email()}
+	response := &dto.MagicLinkResponse{IsSuccess: true}
+
+	controller := gomock.NewController(t.T())
+
+	authSvc := authMock.NewMockService(controller)
+	userSvc := userMock.NewMockService(controller)
+	validator := validatorMock.NewMockIDtoValidator(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Bind(&dto.MagicLinkRequest{}).SetArg(0, *request).Return(nil)
+	validator.EXPECT().Validate(gomock.Any(), request).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().RequestMagicLink(gomock.Any(), request, t.requestMeta).Return(response, nil)
+	context.EXPECT().JSON(http.StatusOK, response)
+
+	handler := auth.NewHandler(authSvc, userSvc, validator)
+
+	handler.RequestMagicLink(context)
+}
+
+func (t *AuthHandlerTest) TestSignInMagicLinkInvalidToken() {
+	request := &dto.SignInMagicLinkRequest{Token: faker.UUIDDigit()}
+	errResponse := dto.UnauthorizedError(constant.InvalidTokenErrorMessage)
+
+	controller := gomock.NewController(t.T())
+
+	authSvc := authMock.NewMockService(controller)
+	userSvc := userMock.NewMockService(controller)
+	validator := validatorMock.NewMockIDtoValidator(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Bind(&dto.SignInMagicLinkRequest{}).SetArg(0, *request).Return(nil)
+	validator.EXPECT().Validate(gomock.Any(), request).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().SignInMagicLink(gomock.Any(), request, t.requestMeta).Return(nil, errResponse)
+	context.EXPECT().JSON(http.StatusUnauthorized, errResponse)
+
+	handler := auth.NewHandler(authSvc, userSvc, validator)
+
+	handler.SignInMagicLink(context)
 }
 
 func (t *AuthHandlerTest) TestSignInBindFailed() {
@@ -215,7 +339,8 @@ func (t *AuthHandlerTest) TestSignInValidateFailed() {
 	context := routerMock.NewMockIContext(controller)
 
 	context.EXPECT().Bind(t.signInRequest).Return(nil)
-	validator.EXPECT().Validate(t.signInRequest).Return(t.validateErr)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	validator.EXPECT().Validate(gomock.Any(), t.signInRequest).Return(t.validateErr)
 	context.EXPECT().JSON(http.StatusBadRequest, errResponse)
 
 	handler := auth.NewHandler(authSvc, userSvc, validator)
@@ -238,8 +363,11 @@ func (t *AuthHandlerTest) TestSignInServiceError() {
 	context := routerMock.NewMockIContext(controller)
 
 	context.EXPECT().Bind(t.signInRequest).Return(nil)
-	validator.EXPECT().Validate(t.signInRequest).Return(nil)
-	authSvc.EXPECT().SignIn(t.signInRequest).Return(nil, signInErrResponse)
+	validator.EXPECT().Validate(gomock.Any(), t.signInRequest).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().SignIn(gomock.Any(), t.signInRequest, t.requestMeta).Return(nil, signInErrResponse)
 	context.EXPECT().JSON(http.StatusInternalServerError, signInErrResponse)
 
 	handler := auth.NewHandler(authSvc, userSvc, validator)
@@ -247,6 +375,30 @@ func (t *AuthHandlerTest) TestSignInServiceError() {
 	handler.SignIn(context)
 }
 
+func (t *AuthHandlerTest) TestSignInTooManyRequests() {
+	signInErrResponse := dto.TooManyRequestsError(constant.AccountLockedErrorMessage, 15*time.Minute)
+
+	controller := gomock.NewController(t.T())
+
+	authSvc := authMock.NewMockService(controller)
+	userSvc := userMock.NewMockService(controller)
+	validator := validatorMock.NewMockIDtoValidator(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Bind(t.signInRequest).Return(nil)
+	validator.EXPECT().Validate(gomock.Any(), t.signInRequest).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().SignIn(gomock.Any(), t.signInRequest, t.requestMeta).Return(nil, signInErrResponse)
+	context.EXPECT().SetHeader("Retry-After", "900")
+	context.EXPECT().JSON(http.StatusTooManyRequests, signInErrResponse)
+
+	handler := auth.NewHandler(authSvc, userSvc, validator)
+
+	handler.SignIn(context)
+}
+
 func (t *AuthHandlerTest) TestSignOutSuccess() {
 	token := faker.Word()
 	signOutResponse := &dto.SignOutResponse{
@@ -261,7 +413,10 @@ func (t *AuthHandlerTest) TestSignOutSuccess() {
 	context := routerMock.NewMockIContext(controller)
 
 	context.EXPECT().Token().Return(token)
-	authSvc.EXPECT().SignOut(token).Return(signOutResponse, nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().SignOut(gomock.Any(), token, t.requestMeta).Return(signOutResponse, nil)
 	context.EXPECT().JSON(http.StatusOK, signOutResponse)
 
 	handler := auth.NewHandler(authSvc, userSvc, validator)
@@ -287,7 +442,10 @@ func (t *AuthHandlerTest) TestSignOutServiceError() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Token().Return(token)
-	authSvc.EXPECT().SignOut(token).Return(nil, errResponse)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().SignOut(gomock.Any(), token, t.requestMeta).Return(nil, errResponse)
 	context.EXPECT().JSON(http.StatusInternalServerError, errResponse)
 
 	handler.SignOut(context)
@@ -310,8 +468,9 @@ func (t *AuthHandlerTest) TestRefreshTokenSuccess() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Bind(t.refreshTokenRequest).Return(nil)
-	validator.EXPECT().Validate(t.refreshTokenRequest).Return(nil)
-	authSvc.EXPECT().RefreshToken(t.refreshTokenRequest).Return(refreshTokenResponse, nil)
+	validator.EXPECT().Validate(gomock.Any(), t.refreshTokenRequest).Return(nil)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().RefreshToken(gomock.Any(), t.refreshTokenRequest).Return(refreshTokenResponse, nil)
 	context.EXPECT().JSON(http.StatusOK, refreshTokenResponse)
 
 	handler.RefreshToken(context)
@@ -356,7 +515,8 @@ func (t *AuthHandlerTest) TestRefreshTokenValidateFailed() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Bind(t.refreshTokenRequest).Return(nil)
-	validator.EXPECT().Validate(t.refreshTokenRequest).Return(t.validateErr)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	validator.EXPECT().Validate(gomock.Any(), t.refreshTokenRequest).Return(t.validateErr)
 	context.EXPECT().JSON(http.StatusBadRequest, errResponse)
 
 	handler.RefreshToken(context)
@@ -379,8 +539,9 @@ func (t *AuthHandlerTest) TestRefreshTokenServiceError() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Bind(t.refreshTokenRequest).Return(nil)
-	validator.EXPECT().Validate(t.refreshTokenRequest).Return(nil)
-	authSvc.EXPECT().RefreshToken(t.refreshTokenRequest).Return(nil, refreshTokenErr)
+	validator.EXPECT().Validate(gomock.Any(), t.refreshTokenRequest).Return(nil)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().RefreshToken(gomock.Any(), t.refreshTokenRequest).Return(nil, refreshTokenErr)
 	context.EXPECT().JSON(http.StatusInternalServerError, refreshTokenErr)
 
 	handler.RefreshToken(context)
@@ -401,8 +562,11 @@ func (t *AuthHandlerTest) TestForgotPasswordSuccess() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Bind(t.forgotPasswordRequest).Return(nil)
-	validator.EXPECT().Validate(t.forgotPasswordRequest).Return(nil)
-	authSvc.EXPECT().ForgotPassword(t.forgotPasswordRequest).Return(forgotPasswordResponse, nil)
+	validator.EXPECT().Validate(gomock.Any(), t.forgotPasswordRequest).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().ForgotPassword(gomock.Any(), t.forgotPasswordRequest, t.requestMeta).Return(forgotPasswordResponse, nil)
 	context.EXPECT().JSON(http.StatusOK, forgotPasswordResponse)
 
 	handler.ForgotPassword(context)
@@ -446,7 +610,8 @@ func (t *AuthHandlerTest) TestForgotPasswordValidateFailed() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Bind(t.forgotPasswordRequest).Return(nil)
-	validator.EXPECT().Validate(t.forgotPasswordRequest).Return(t.validateErr)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	validator.EXPECT().Validate(gomock.Any(), t.forgotPasswordRequest).Return(t.validateErr)
 	context.EXPECT().JSON(http.StatusBadRequest, errResponse)
 
 	handler.ForgotPassword(context)
@@ -469,8 +634,11 @@ func (t *AuthHandlerTest) TestForgotPasswordServiceError() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Bind(t.forgotPasswordRequest).Return(nil)
-	validator.EXPECT().Validate(t.forgotPasswordRequest).Return(nil)
-	authSvc.EXPECT().ForgotPassword(t.forgotPasswordRequest).Return(nil, forgotPasswordErr)
+	validator.EXPECT().Validate(gomock.Any(), t.forgotPasswordRequest).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().ForgotPassword(gomock.Any(), t.forgotPasswordRequest, t.requestMeta).Return(nil, forgotPasswordErr)
 	context.EXPECT().JSON(http.StatusInternalServerError, forgotPasswordErr)
 
 	handler.ForgotPassword(context)
@@ -491,8 +659,11 @@ func (t *AuthHandlerTest) TestResetPasswordSuccess() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Bind(t.resetPasswordRequest).Return(nil)
-	validator.EXPECT().Validate(t.resetPasswordRequest).Return(nil)
-	authSvc.EXPECT().ResetPassword(t.resetPasswordRequest).Return(resetPasswordResponse, nil)
+	validator.EXPECT().Validate(gomock.Any(), t.resetPasswordRequest).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().ResetPassword(gomock.Any(), t.resetPasswordRequest, t.requestMeta).Return(resetPasswordResponse, nil)
 	context.EXPECT().JSON(http.StatusOK, resetPasswordResponse)
 
 	handler.ResetPassword(context)
@@ -537,7 +708,8 @@ func (t *AuthHandlerTest) TestResetPasswordValidateFailed() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Bind(t.resetPasswordRequest).Return(nil)
-	validator.EXPECT().Validate(t.resetPasswordRequest).Return(t.validateErr)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	validator.EXPECT().Validate(gomock.Any(), t.resetPasswordRequest).Return(t.validateErr)
 	context.EXPECT().JSON(http.StatusBadRequest, errResponse)
 
 	handler.ResetPassword(context)
@@ -560,8 +732,11 @@ func (t *AuthHandlerTest) TestResetPasswordServiceError() {
 	handler := auth.NewHandler(authSvc, userSvc, validator)
 
 	context.EXPECT().Bind(t.resetPasswordRequest).Return(nil)
-	validator.EXPECT().Validate(t.resetPasswordRequest).Return(nil)
-	authSvc.EXPECT().ResetPassword(t.resetPasswordRequest).Return(nil, resetPasswordErr)
+	validator.EXPECT().Validate(gomock.Any(), t.resetPasswordRequest).Return(nil)
+	context.EXPECT().IP().Return(t.requestMeta.IP)
+	context.EXPECT().UserAgent().Return(t.requestMeta.UserAgent)
+	context.EXPECT().UserContext().Return(ctx.Background()).AnyTimes()
+	authSvc.EXPECT().ResetPassword(gomock.Any(), t.resetPasswordRequest, t.requestMeta).Return(nil, resetPasswordErr)
 	context.EXPECT().JSON(http.StatusInternalServerError, resetPasswordErr)
 
 	handler.ResetPassword(context)
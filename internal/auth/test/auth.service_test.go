@@ -0,0 +1,394 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-faker/faker/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/email"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/throttle"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/token"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/twofactor"
+	"github.com/isd-sgcu/johnjud-backend/internal/cache"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/utils"
+	mock_auth "github.com/isd-sgcu/johnjud-backend/mocks/repository/auth"
+	twoFactorRepoMock "github.com/isd-sgcu/johnjud-backend/mocks/repository/twofactor"
+	userRepoMock "github.com/isd-sgcu/johnjud-backend/mocks/repository/user"
+	emailMock "github.com/isd-sgcu/johnjud-backend/mocks/service/email"
+	jwtMock "github.com/isd-sgcu/johnjud-backend/mocks/service/jwt"
+	mock_oauth "github.com/isd-sgcu/johnjud-backend/mocks/service/oauth"
+	mock_securityevent "github.com/isd-sgcu/johnjud-backend/mocks/service/securityevent"
+	mock_throttle "github.com/isd-sgcu/johnjud-backend/mocks/service/throttle"
+	mock_token "github.com/isd-sgcu/johnjud-backend/mocks/service/token"
+	mock_twofactor "github.com/isd-sgcu/johnjud-backend/mocks/service/twofactor"
+	mockUtils "github.com/isd-sgcu/johnjud-backend/mocks/utils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type AuthServiceTest struct {
+	suite.Suite
+	user     *model.User
+	password string
+	meta     *dto.RequestMeta
+	config   config.Auth
+
+	authRepo     *mock_auth.MockRepository
+	userRepo     *userRepoMock.UserRepositoryMock
+	tokenService *mock_token.MockService
+	emailService *emailMock.MockService
+	throttle     *mock_throttle.MockService
+	twoFactor    *mock_twofactor.MockService
+	oauthService *mock_oauth.MockService
+	eventService *mock_securityevent.MockService
+	bcryptUtil   *mockUtils.BcryptUtilMock
+	service      auth.Service
+}
+
+func TestAuthService(t *testing.T) {
+	suite.Run(t, new(AuthServiceTest))
+}
+
+func (t *AuthServiceTest) SetupTest() {
+	t.user = &model.User{
+		Base:      model.Base{ID: uuid.New()},
+		Email:     faker.Email(),
+		Password:  faker.Password(),
+		Firstname: faker.FirstName(),
+		Lastname:  faker.LastName(),
+		Role:      constant.USER,
+	}
+	t.password = faker.Password()
+	t.meta = &dto.RequestMeta{IP: faker.IPv4(), UserAgent: faker.Word()}
+	t.config = config.Auth{ClientURL: "https://johnjud.example"}
+
+	controller := gomock.NewController(t.T())
+	t.authRepo = mock_auth.NewMockRepository(controller)
+	t.userRepo = &userRepoMock.UserRepositoryMock{}
+	t.tokenService = mock_token.NewMockService(controller)
+	t.emailService = emailMock.NewMockService(controller)
+	t.throttle = mock_throttle.NewMockService(controller)
+	t.twoFactor = mock_twofactor.NewMockService(controller)
+	t.oauthService = mock_oauth.NewMockService(controller)
+	t.eventService = mock_securityevent.NewMockService(controller)
+	t.bcryptUtil = &mockUtils.BcryptUtilMock{}
+	t.newService()
+}
+
+// newService builds the service again after a test changes the config
+func (t *AuthServiceTest) newService() {
+	t.service = auth.NewService(t.authRepo, t.userRepo, t.tokenService, t.emailService, t.throttle, t.twoFactor, t.oauthService, t.eventService, t.bcryptUtil, t.config)
+}
+
+// withTwoFactorRepository builds the service with the real 2fa service so the codes are checked for real
+func (t *AuthServiceTest) withTwoFactorRepository() *twoFactorRepoMock.MockRepository {
+	repo := twoFactorRepoMock.NewMockRepository(gomock.NewController(t.T()))
+	twoFactorService := twofactor.NewService(repo, t.userRepo, t.config)
+	t.service = auth.NewService(t.authRepo, t.userRepo, t.tokenService, t.emailService, t.throttle, twoFactorService, t.oauthService, t.eventService, t.bcryptUtil, t.config)
+
+	return repo
+}
+
+// expectPassword sets up a sign in of the user with the right password
+func (t *AuthServiceTest) expectPassword() {
+	t.throttle.EXPECT().Check(gomock.Any(), constant.SignInAction, t.user.Email, t.meta.IP).Return(time.Duration(0), nil)
+	t.userRepo.On("FindByEmail", testifyMock.Anything, t.user.Email, &model.User{}).Return(t.user, nil)
+	t.bcryptUtil.On("CompareHashedPassword", t.user.Password, t.password).Return(nil)
+	t.throttle.EXPECT().Reset(gomock.Any(), constant.SignInAction, t.user.Email).Return(nil)
+}
+
+// expectChallenge sets up the second step of the sign in of the user up to the check of the code
+func (t *AuthServiceTest) expectChallenge(challengeToken string) {
+	t.tokenService.EXPECT().FindTwoFactorChallenge(gomock.Any(), challengeToken).Return(&dto.TwoFactorChallengeCache{UserID: t.user.ID.String()}, nil)
+	t.userRepo.On("FindById", testifyMock.Anything, t.user.ID.String(), &model.User{}).Return(t.user, nil)
+	t.throttle.EXPECT().Check(gomock.Any(), constant.TwoFactorAction, t.user.Email, t.meta.IP).Return(time.Duration(0), nil)
+}
+
+// expectCredential sets up the credential issued to the user with the role
+func (t *AuthServiceTest) expectCredential(role constant.Role) *dto.Credential {
+	credential := &dto.Credential{AccessToken: faker.Word(), RefreshToken: faker.UUIDDigit(), ExpiresIn: 3600}
+	t.authRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	t.tokenService.EXPECT().CreateCredential(gomock.Any(), t.user.ID.String(), role, gomock.Any()).Return(credential, nil)
+
+	return credential
+}
+
+func (t *AuthServiceTest) currentCode() string {
+	code, _ := utils.TotpCode(t.user.TotpSecret, utils.TotpStep(time.Now()))
+	return code
+}
+
+func (t *AuthServiceTest) enableTwoFactor() {
+	t.user.TotpSecret, _ = utils.GenerateTotpSecret()
+	t.user.TotpEnabled = true
+}
+
+// expectWrongPassword sets up a sign in of the user with a wrong password
+func (t *AuthServiceTest) expectWrongPassword() {
+	t.throttle.EXPECT().Check(gomock.Any(), constant.SignInAction, t.user.Email, t.meta.IP).Return(time.Duration(0), nil)
+	t.userRepo.On("FindByEmail", testifyMock.Anything, t.user.Email, &model.User{}).Return(t.user, nil)
+	t.bcryptUtil.On("CompareHashedPassword", t.user.Password, t.password).Return(errors.New("Mismatched password"))
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInEvent, t.user.ID.String(), t.user.Email, t.meta, false)
+}
+
+func (t *AuthServiceTest) TestSignInIpLocked() {
+	t.expectWrongPassword()
+	t.throttle.EXPECT().Hit(gomock.Any(), constant.SignInAction, t.user.Email, t.meta.IP).
+		Return(&throttle.Result{IpLocked: true, RetryAfter: 15 * time.Minute}, nil)
+
+	actual, err := t.service.SignIn(context.Background(), &dto.SignInRequest{Email: t.user.Email, Password: t.password}, t.meta)
+
+	// the owner of the email is not told about a lock of the ip
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.TooManyRequestsError(constant.TooManyRequestsErrorMessage, 15*time.Minute), err)
+}
+
+func (t *AuthServiceTest) TestResetPasswordResetsForgotPasswordRequests() {
+	token := faker.UUIDDigit()
+	hashedPassword := faker.Password()
+
+	t.tokenService.EXPECT().FindResetPasswordToken(gomock.Any(), token).Return(&dto.ResetPasswordTokenCache{UserID: t.user.ID.String()}, nil)
+	t.userRepo.On("FindById", testifyMock.Anything, t.user.ID.String(), &model.User{}).Return(t.user, nil)
+	t.bcryptUtil.On("CompareHashedPassword", t.user.Password, t.password).Return(errors.New("Mismatched password"))
+	t.bcryptUtil.On("GenerateHashedPassword", t.password).Return(hashedPassword, nil)
+	t.userRepo.On("Update", testifyMock.Anything, t.user.ID.String(), testifyMock.Anything).Return(nil, nil)
+	t.tokenService.EXPECT().RemoveResetPasswordToken(gomock.Any(), token).Return(nil)
+	t.throttle.EXPECT().Reset(gomock.Any(), constant.ForgotPasswordAction, t.user.Email).Return(nil)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.ResetPasswordEvent, t.user.ID.String(), t.user.Email, t.meta, true)
+
+	actual, err := t.service.ResetPassword(context.Background(), &dto.ResetPasswordRequest{Token: token, Password: t.password}, t.meta)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.ResetPasswordResponse{IsSuccess: true}, actual)
+}
+
+func (t *AuthServiceTest) TestSignInTwoFactorChallenge() {
+	t.enableTwoFactor()
+	t.expectPassword()
+	t.tokenService.EXPECT().CreateTwoFactorChallenge(gomock.Any(), t.user.ID.String()).Return("challenge", nil)
+
+	actual, err := t.service.SignIn(context.Background(), &dto.SignInRequest{Email: t.user.Email, Password: t.password}, t.meta)
+
+	// no credential nor session is created before the code is checked
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.SignInResponse{TwoFactorRequired: true, ChallengeToken: "challenge"}, actual)
+}
+
+func (t *AuthServiceTest) TestSignInTwoFactorSuccess() {
+	t.enableTwoFactor()
+	t.user.Role = constant.ADMIN
+	t.config.RequireAdminTwoFactor = true
+	repo := t.withTwoFactorRepository()
+
+	t.expectChallenge("challenge")
+	repo.EXPECT().UpdateLastStep(gomock.Any(), t.user.ID.String(), utils.TotpStep(time.Now())).Return(nil)
+	t.throttle.EXPECT().Reset(gomock.Any(), constant.TwoFactorAction, t.user.Email).Return(nil)
+	t.tokenService.EXPECT().RemoveTwoFactorChallenge(gomock.Any(), "challenge").Return(nil)
+	credential := t.expectCredential(constant.ADMIN)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInTwoFactorEvent, t.user.ID.String(), t.user.Email, t.meta, true)
+
+	actual, err := t.service.SignInTwoFactor(context.Background(), &dto.SignInTwoFactorRequest{ChallengeToken: "challenge", Code: t.currentCode()}, t.meta)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), credential, actual)
+}
+
+func (t *AuthServiceTest) TestSignInTwoFactorReplayedCode() {
+	t.enableTwoFactor()
+	repo := t.withTwoFactorRepository()
+
+	t.expectChallenge("challenge")
+	// the step of the code was used by an earlier sign in so the repository does not move the last step
+	repo.EXPECT().UpdateLastStep(gomock.Any(), t.user.ID.String(), gomock.Any()).Return(gorm.ErrRecordNotFound)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInTwoFactorEvent, t.user.ID.String(), t.user.Email, t.meta, false)
+	t.throttle.EXPECT().Hit(gomock.Any(), constant.TwoFactorAction, t.user.Email, t.meta.IP).Return(&throttle.Result{}, nil)
+
+	actual, err := t.service.SignInTwoFactor(context.Background(), &dto.SignInTwoFactorRequest{ChallengeToken: "challenge", Code: t.currentCode()}, t.meta)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.UnauthorizedError(constant.InvalidTwoFactorCodeErrorMessage), err)
+}
+
+func (t *AuthServiceTest) TestSignInTwoFactorRecoveryCode() {
+	t.enableTwoFactor()
+	repo := t.withTwoFactorRepository()
+
+	t.expectChallenge("challenge")
+	repo.EXPECT().UseRecoveryCode(gomock.Any(), t.user.ID.String(), twofactor.HashRecoveryCode("abcde12345")).Return(nil)
+	t.throttle.EXPECT().Reset(gomock.Any(), constant.TwoFactorAction, t.user.Email).Return(nil)
+	t.tokenService.EXPECT().RemoveTwoFactorChallenge(gomock.Any(), "challenge").Return(nil)
+	credential := t.expectCredential(constant.USER)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInTwoFactorEvent, t.user.ID.String(), t.user.Email, t.meta, true)
+
+	actual, err := t.service.SignInTwoFactor(context.Background(), &dto.SignInTwoFactorRequest{ChallengeToken: "challenge", Code: "ABCDE-12345"}, t.meta)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), credential, actual)
+}
+
+func (t *AuthServiceTest) TestSignInTwoFactorUsedRecoveryCode() {
+	t.enableTwoFactor()
+	repo := t.withTwoFactorRepository()
+
+	t.expectChallenge("challenge")
+	repo.EXPECT().UseRecoveryCode(gomock.Any(), t.user.ID.String(), twofactor.HashRecoveryCode("abcde12345")).Return(gorm.ErrRecordNotFound)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInTwoFactorEvent, t.user.ID.String(), t.user.Email, t.meta, false)
+	t.throttle.EXPECT().Hit(gomock.Any(), constant.TwoFactorAction, t.user.Email, t.meta.IP).Return(&throttle.Result{}, nil)
+
+	actual, err := t.service.SignInTwoFactor(context.Background(), &dto.SignInTwoFactorRequest{ChallengeToken: "challenge", Code: "ABCDE-12345"}, t.meta)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.UnauthorizedError(constant.InvalidTwoFactorCodeErrorMessage), err)
+}
+
+func (t *AuthServiceTest) TestSignInTwoFactorLocked() {
+	t.enableTwoFactor()
+	code := t.currentCode()
+
+	t.expectChallenge("challenge")
+	t.twoFactor.EXPECT().Verify(gomock.Any(), gomock.Any(), code).Return(false, nil)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInTwoFactorEvent, t.user.ID.String(), t.user.Email, t.meta, false)
+	t.throttle.EXPECT().Hit(gomock.Any(), constant.TwoFactorAction, t.user.Email, t.meta.IP).
+		Return(&throttle.Result{Locked: true, RetryAfter: 15 * time.Minute}, nil)
+	// the password has to be entered again after the lockout
+	t.tokenService.EXPECT().RemoveTwoFactorChallenge(gomock.Any(), "challenge").Return(nil)
+
+	actual, err := t.service.SignInTwoFactor(context.Background(), &dto.SignInTwoFactorRequest{ChallengeToken: "challenge", Code: code}, t.meta)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.TooManyRequestsError(constant.TooManyRequestsErrorMessage, 15*time.Minute), err)
+}
+
+func (t *AuthServiceTest) TestSignInTwoFactorSuspended() {
+	t.enableTwoFactor()
+	t.user.IsSuspended = true
+	code := t.currentCode()
+
+	t.expectChallenge("challenge")
+	t.twoFactor.EXPECT().Verify(gomock.Any(), gomock.Any(), code).Return(true, nil)
+	t.throttle.EXPECT().Reset(gomock.Any(), constant.TwoFactorAction, t.user.Email).Return(nil)
+	t.tokenService.EXPECT().RemoveTwoFactorChallenge(gomock.Any(), "challenge").Return(nil)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInTwoFactorEvent, t.user.ID.String(), t.user.Email, t.meta, false)
+
+	actual, err := t.service.SignInTwoFactor(context.Background(), &dto.SignInTwoFactorRequest{ChallengeToken: "challenge", Code: code}, t.meta)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.ForbiddenError(constant.SuspendedUserErrorMessage), err)
+}
+
+func (t *AuthServiceTest) TestSignInSuccess() {
+	t.user.Role = constant.ADMIN
+	t.expectPassword()
+	credential := t.expectCredential(constant.ADMIN)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInEvent, t.user.ID.String(), t.user.Email, t.meta, true)
+
+	actual, err := t.service.SignIn(context.Background(), &dto.SignInRequest{Email: t.user.Email, Password: t.password}, t.meta)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.SignInResponse{Credential: credential}, actual)
+}
+
+func (t *AuthServiceTest) TestSignInSuspended() {
+	t.user.IsSuspended = true
+	t.expectPassword()
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInEvent, t.user.ID.String(), t.user.Email, t.meta, false)
+
+	actual, err := t.service.SignIn(context.Background(), &dto.SignInRequest{Email: t.user.Email, Password: t.password}, t.meta)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.ForbiddenError(constant.SuspendedUserErrorMessage), err)
+}
+
+func (t *AuthServiceTest) TestSignInAdminWithoutTwoFactor() {
+	t.user.Role = constant.ADMIN
+	t.config.RequireAdminTwoFactor = true
+	t.newService()
+
+	t.expectPassword()
+	credential := t.expectCredential(constant.USER)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInEvent, t.user.ID.String(), t.user.Email, t.meta, true)
+
+	actual, err := t.service.SignIn(context.Background(), &dto.SignInRequest{Email: t.user.Email, Password: t.password}, t.meta)
+
+	// the admin only gets the user role until 2fa is enrolled
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.SignInResponse{Credential: credential, TwoFactorSetupRequired: true}, actual)
+}
+
+func (t *AuthServiceTest) TestSignInLockout() {
+	t.expectWrongPassword()
+	t.throttle.EXPECT().Hit(gomock.Any(), constant.SignInAction, t.user.Email, t.meta.IP).
+		Return(&throttle.Result{Locked: true, RetryAfter: 15 * time.Minute}, nil)
+	recipient := &email.Recipient{Name: t.user.Firstname, Address: t.user.Email, Locale: t.user.Locale}
+	data := &email.AccountLockedData{Name: t.user.Firstname, Minutes: 15, URL: t.config.ClientURL + "/forgot-password"}
+	t.emailService.EXPECT().Send(gomock.Any(), constant.AccountLockedTemplate, recipient, data).Return(nil)
+
+	actual, err := t.service.SignIn(context.Background(), &dto.SignInRequest{Email: t.user.Email, Password: t.password}, t.meta)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.TooManyRequestsError(constant.AccountLockedErrorMessage, 15*time.Minute), err)
+}
+
+func (t *AuthServiceTest) TestSignInLockoutUnknownEmail() {
+	t.throttle.EXPECT().Check(gomock.Any(), constant.SignInAction, t.user.Email, t.meta.IP).Return(time.Duration(0), nil)
+	t.userRepo.On("FindByEmail", testifyMock.Anything, t.user.Email, &model.User{}).Return(nil, gorm.ErrRecordNotFound)
+	t.eventService.EXPECT().Record(gomock.Any(), constant.SignInEvent, "", t.user.Email, t.meta, false)
+	t.throttle.EXPECT().Hit(gomock.Any(), constant.SignInAction, t.user.Email, t.meta.IP).
+		Return(&throttle.Result{Locked: true, RetryAfter: 15 * time.Minute}, nil)
+
+	actual, err := t.service.SignIn(context.Background(), &dto.SignInRequest{Email: t.user.Email, Password: t.password}, t.meta)
+
+	// there is no one to email, the response is the same as for a known email
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.TooManyRequestsError(constant.AccountLockedErrorMessage, 15*time.Minute), err)
+}
+
+func (t *AuthServiceTest) TestSignInWhileLocked() {
+	t.throttle.EXPECT().Check(gomock.Any(), constant.SignInAction, t.user.Email, t.meta.IP).Return(10*time.Minute, nil)
+
+	actual, err := t.service.SignIn(context.Background(), &dto.SignInRequest{Email: t.user.Email, Password: t.password}, t.meta)
+
+	// the password is not checked at all during the lockout
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.TooManyRequestsError(constant.TooManyRequestsErrorMessage, 10*time.Minute), err)
+	t.bcryptUtil.AssertNotCalled(t.T(), "CompareHashedPassword", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (t *AuthServiceTest) TestSignInMagicLinkOnlyOnce() {
+	t.enableTwoFactor()
+
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t.T()).Addr()})
+	defer client.Close()
+	tokenCache := cache.NewRepository(client)
+	jwtService := &jwtMock.JwtServiceMock{}
+	jwtService.On("GetConfig").Return(&config.Jwt{MagicLinkTokenTTL: 900, TwoFactorChallengeTTL: 300})
+	tokenService := token.NewService(jwtService, tokenCache, tokenCache, tokenCache, tokenCache, tokenCache, tokenCache, utils.NewUuidUtil())
+	t.service = auth.NewService(t.authRepo, t.userRepo, tokenService, t.emailService, t.throttle, t.twoFactor, t.oauthService, t.eventService, t.bcryptUtil, t.config)
+
+	magicLinkToken, err := tokenService.CreateMagicLinkToken(context.Background(), t.user.ID.String())
+	assert.Nil(t.T(), err)
+
+	t.userRepo.On("FindById", testifyMock.Anything, t.user.ID.String(), &model.User{}).Return(t.user, nil)
+	t.throttle.EXPECT().Reset(gomock.Any(), constant.MagicLinkAction, t.user.Email).Return(nil)
+
+	actual, apperr := t.service.SignInMagicLink(context.Background(), &dto.SignInMagicLinkRequest{Token: magicLinkToken}, t.meta)
+	assert.Nil(t.T(), apperr)
+	assert.True(t.T(), actual.TwoFactorRequired)
+
+	actual, apperr = t.service.SignInMagicLink(context.Background(), &dto.SignInMagicLinkRequest{Token: magicLinkToken}, t.meta)
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), dto.UnauthorizedError(constant.InvalidTokenErrorMessage), apperr)
+}
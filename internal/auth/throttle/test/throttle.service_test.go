@@ -0,0 +1,160 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/throttle"
+	mock_cache "github.com/isd-sgcu/johnjud-backend/mocks/repository/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ThrottleServiceTest struct {
+	suite.Suite
+	config config.Throttle
+	email  string
+	ip     string
+}
+
+func TestThrottleService(t *testing.T) {
+	suite.Run(t, new(ThrottleServiceTest))
+}
+
+func (t *ThrottleServiceTest) SetupTest() {
+	t.config = config.Throttle{
+		MaxFailures:   10,
+		IpMaxFailures: 50,
+		Window:        900,
+		Lockout:       900,
+		DelayAfter:    3,
+		BaseDelay:     1,
+		MaxDelay:      30,
+	}
+	t.email = strings.ToLower(faker.Email())
+	t.ip = faker.IPv4()
+}
+
+func (t *ThrottleServiceTest) TestCheckAllowed() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().GetTTL(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(3)
+
+	service := throttle.NewService(t.config, cache)
+	wait, err := service.Check(context.Background(), constant.SignInAction, t.email, t.ip)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), time.Duration(0), wait)
+}
+
+func (t *ThrottleServiceTest) TestCheckLocked() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().GetTTL(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil)
+	cache.EXPECT().GetTTL(gomock.Any(), gomock.Any()).Return(10*time.Minute, nil)
+	cache.EXPECT().GetTTL(gomock.Any(), gomock.Any()).Return(2*time.Second, nil)
+
+	service := throttle.NewService(t.config, cache)
+	wait, err := service.Check(context.Background(), constant.SignInAction, t.email, t.ip)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), 10*time.Minute, wait)
+}
+
+func (t *ThrottleServiceTest) TestCheckCacheError() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().GetTTL(gomock.Any(), gomock.Any()).Return(time.Duration(0), errors.New("Connection lost"))
+
+	service := throttle.NewService(t.config, cache)
+	_, err := service.Check(context.Background(), constant.SignInAction, t.email, t.ip)
+
+	assert.NotNil(t.T(), err)
+}
+
+func (t *ThrottleServiceTest) TestHitBelowDelay() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().Increment(gomock.Any(), gomock.Any(), t.config.Window).Return(int64(1), nil).Times(2)
+
+	service := throttle.NewService(t.config, cache)
+	result, err := service.Hit(context.Background(), constant.SignInAction, t.email, t.ip)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &throttle.Result{}, result)
+}
+
+func (t *ThrottleServiceTest) TestHitDelay() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().Increment(gomock.Any(), gomock.Any(), t.config.Window).Return(int64(5), nil).Times(2)
+	cache.EXPECT().SetValue(gomock.Any(), "throttle:signin:delay:email:"+t.email, true, 4).Return(nil)
+
+	service := throttle.NewService(t.config, cache)
+	result, err := service.Hit(context.Background(), constant.SignInAction, t.email, t.ip)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &throttle.Result{RetryAfter: 4 * time.Second}, result)
+}
+
+func (t *ThrottleServiceTest) TestHitLockEmail() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().Increment(gomock.Any(), gomock.Any(), t.config.Window).Return(int64(10), nil).Times(2)
+	cache.EXPECT().SetValue(gomock.Any(), "throttle:signin:lock:email:"+t.email, true, t.config.Lockout).Return(nil)
+
+	service := throttle.NewService(t.config, cache)
+	result, err := service.Hit(context.Background(), constant.SignInAction, t.email, t.ip)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &throttle.Result{Locked: true, RetryAfter: 15 * time.Minute}, result)
+}
+
+func (t *ThrottleServiceTest) TestHitLockIp() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().Increment(gomock.Any(), "throttle:signin:failures:email:"+t.email, t.config.Window).Return(int64(1), nil)
+	cache.EXPECT().Increment(gomock.Any(), "throttle:signin:failures:ip:"+t.ip, t.config.Window).Return(int64(50), nil)
+	cache.EXPECT().SetValue(gomock.Any(), "throttle:signin:lock:ip:"+t.ip, true, t.config.Lockout).Return(nil)
+
+	service := throttle.NewService(t.config, cache)
+	result, err := service.Hit(context.Background(), constant.SignInAction, t.email, t.ip)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &throttle.Result{IpLocked: true, RetryAfter: 15 * time.Minute}, result)
+}
+
+func (t *ThrottleServiceTest) TestReset() {
+	controller := gomock.NewController(t.T())
+	cache := mock_cache.NewMockRepository(controller)
+
+	cache.EXPECT().DeleteValue(gomock.Any(), "throttle:signin:failures:email:"+t.email).Return(nil)
+	cache.EXPECT().DeleteValue(gomock.Any(), "throttle:signin:lock:email:"+t.email).Return(nil)
+	cache.EXPECT().DeleteValue(gomock.Any(), "throttle:signin:delay:email:"+t.email).Return(nil)
+
+	service := throttle.NewService(t.config, cache)
+	err := service.Reset(context.Background(), constant.SignInAction, t.email)
+
+	assert.Nil(t.T(), err)
+}
+
+func (t *ThrottleServiceTest) TestDelay() {
+	assert.Equal(t.T(), time.Duration(0), throttle.Delay(2, 3, 1, 30))
+	assert.Equal(t.T(), time.Second, throttle.Delay(3, 3, 1, 30))
+	assert.Equal(t.T(), 8*time.Second, throttle.Delay(6, 3, 1, 30))
+	assert.Equal(t.T(), 30*time.Second, throttle.Delay(9, 3, 1, 30))
+}
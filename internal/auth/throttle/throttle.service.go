@@ -0,0 +1,136 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/cache"
+)
+
+type Result struct {
+	// Locked is true when this hit locked the account of the email
+	Locked bool
+	// IpLocked is true when this hit locked the ip, the owner of the email is not notified since it may be anyone
+	IpLocked   bool
+	RetryAfter time.Duration
+}
+
+type Service interface {
+	Check(ctx context.Context, action constant.ThrottleAction, email string, ip string) (time.Duration, error)
+	Hit(ctx context.Context, action constant.ThrottleAction, email string, ip string) (*Result, error)
+	Reset(ctx context.Context, action constant.ThrottleAction, email string) error
+}
+
+type serviceImpl struct {
+	config config.Throttle
+	cache  cache.Repository
+}
+
+func NewService(config config.Throttle, cache cache.Repository) Service {
+	return &serviceImpl{config: config, cache: cache}
+}
+
+// Check returns how long the client has to wait, zero when the action is allowed
+func (s *serviceImpl) Check(ctx context.Context, action constant.ThrottleAction, email string, ip string) (time.Duration, error) {
+	keys := []string{
+		lockKey(action, "email", email),
+		lockKey(action, "ip", ip),
+		delayKey(action, email),
+	}
+
+	var wait time.Duration
+	for _, key := range keys {
+		ttl, err := s.cache.GetTTL(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		if ttl > wait {
+			wait = ttl
+		}
+	}
+
+	return wait, nil
+}
+
+// Hit counts a failed attempt for both the email and the ip within the window, the email is delayed progressively
+// after DelayAfter failures and locked after MaxFailures while the ip is only locked after IpMaxFailures
+func (s *serviceImpl) Hit(ctx context.Context, action constant.ThrottleAction, email string, ip string) (*Result, error) {
+	emailCount, err := s.cache.Increment(ctx, failureKey(action, "email", email), s.config.Window)
+	if err != nil {
+		return nil, err
+	}
+	ipCount, err := s.cache.Increment(ctx, failureKey(action, "ip", ip), s.config.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	lockout := time.Duration(s.config.Lockout) * time.Second
+
+	if int(emailCount) >= s.config.MaxFailures {
+		if err := s.cache.SetValue(ctx, lockKey(action, "email", email), true, s.config.Lockout); err != nil {
+			return nil, err
+		}
+		return &Result{Locked: true, RetryAfter: lockout}, nil
+	}
+
+	if int(ipCount) >= s.config.IpMaxFailures {
+		if err := s.cache.SetValue(ctx, lockKey(action, "ip", ip), true, s.config.Lockout); err != nil {
+			return nil, err
+		}
+		return &Result{IpLocked: true, RetryAfter: lockout}, nil
+	}
+
+	delay := Delay(int(emailCount), s.config.DelayAfter, s.config.BaseDelay, s.config.MaxDelay)
+	if delay > 0 {
+		if err := s.cache.SetValue(ctx, delayKey(action, email), true, int(delay.Seconds())); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Result{RetryAfter: delay}, nil
+}
+
+// Reset clears the failures, the delay and the lock of the email after a successful attempt, the ip counter is kept
+func (s *serviceImpl) Reset(ctx context.Context, action constant.ThrottleAction, email string) error {
+	if err := s.cache.DeleteValue(ctx, failureKey(action, "email", email)); err != nil {
+		return err
+	}
+	if err := s.cache.DeleteValue(ctx, lockKey(action, "email", email)); err != nil {
+		return err
+	}
+
+	return s.cache.DeleteValue(ctx, delayKey(action, email))
+}
+
+// Delay doubles the base delay (in seconds) for every failure from the DelayAfter-th one, capped at max
+func Delay(failures int, after int, base int, max int) time.Duration {
+	if failures < after {
+		return 0
+	}
+
+	delay := base
+	for i := after; i < failures && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	return time.Duration(delay) * time.Second
+}
+
+func failureKey(action constant.ThrottleAction, kind string, value string) string {
+	return fmt.Sprintf("throttle:%s:failures:%s:%s", action, kind, strings.ToLower(value))
+}
+
+func lockKey(action constant.ThrottleAction, kind string, value string) string {
+	return fmt.Sprintf("throttle:%s:lock:%s:%s", action, kind, strings.ToLower(value))
+}
+
+func delayKey(action constant.ThrottleAction, email string) string {
+	return fmt.Sprintf("throttle:%s:delay:email:%s", action, strings.ToLower(email))
+}
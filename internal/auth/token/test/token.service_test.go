@@ -1,9 +1,11 @@
 package token
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/go-faker/faker/v4"
 	_jwt "github.com/golang-jwt/jwt/v4"
 	"github.com/golang/mock/gomock"
@@ -11,6 +13,7 @@ import (
 	"github.com/isd-sgcu/johnjud-backend/config"
 	"github.com/isd-sgcu/johnjud-backend/constant"
 	"github.com/isd-sgcu/johnjud-backend/internal/auth/token"
+	"github.com/isd-sgcu/johnjud-backend/internal/cache"
 	"github.com/isd-sgcu/johnjud-backend/internal/dto"
 	mock_cache "github.com/isd-sgcu/johnjud-backend/mocks/repository/cache"
 	"github.com/isd-sgcu/johnjud-backend/mocks/service/jwt"
@@ -45,11 +48,14 @@ func (t *TokenServiceTest) SetupTest() {
 	accessToken := "testAccessToken"
 	refreshToken := uuid.New()
 	jwtConfig := &config.Jwt{
-		Secret:          "testSecret",
-		ExpiresIn:       3600,
-		RefreshTokenTTL: 604800,
-		Issuer:          "testIssuer",
-		ResetTokenTTL:   900,
+		Secret:                "testSecret",
+		ExpiresIn:             3600,
+		RefreshTokenTTL:       604800,
+		Issuer:                "testIssuer",
+		ResetTokenTTL:         900,
+		VerifyEmailTokenTTL:   86400,
+		TwoFactorChallengeTTL: 300,
+		MagicLinkTokenTTL:     900,
 	}
 	validateToken := ""
 
@@ -86,16 +92,19 @@ func (t *TokenServiceTest) TestCreateCredentialSuccess() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("SignAuth", t.userId, t.role, t.authSessionId).Return(t.accessToken, nil)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
 	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
-	accessTokenRepo.EXPECT().SetValue(t.authSessionId, accessTokenCache, t.jwtConfig.ExpiresIn).Return(nil)
-	refreshTokenRepo.EXPECT().SetValue(t.refreshToken.String(), refreshTokenCache, t.jwtConfig.RefreshTokenTTL).Return(nil)
+	accessTokenRepo.EXPECT().SetValue(gomock.Any(), t.authSessionId, accessTokenCache, t.jwtConfig.ExpiresIn).Return(nil)
+	refreshTokenRepo.EXPECT().SetValue(gomock.Any(), t.refreshToken.String(), refreshTokenCache, t.jwtConfig.RefreshTokenTTL).Return(nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.CreateCredential(t.userId, t.role, t.authSessionId)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.CreateCredential(context.Background(), t.userId, t.role, t.authSessionId)
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), expected.AccessToken, actual.AccessToken)
@@ -113,12 +122,15 @@ func (t *TokenServiceTest) TestCreateCredentialSignAuthFailed() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("SignAuth", t.userId, t.role, t.authSessionId).Return("", signAuthError)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.CreateCredential(t.userId, t.role, t.authSessionId)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.CreateCredential(context.Background(), t.userId, t.role, t.authSessionId)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected.Error(), err.Error())
@@ -139,15 +151,18 @@ func (t *TokenServiceTest) TestCreateCredentialSetAccessTokenFailed() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("SignAuth", t.userId, t.role, t.authSessionId).Return(t.accessToken, nil)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
 	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
-	accessTokenRepo.EXPECT().SetValue(t.authSessionId, accessTokenCache, t.jwtConfig.ExpiresIn).Return(setCacheErr)
+	accessTokenRepo.EXPECT().SetValue(gomock.Any(), t.authSessionId, accessTokenCache, t.jwtConfig.ExpiresIn).Return(setCacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.CreateCredential(t.userId, t.role, t.authSessionId)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.CreateCredential(context.Background(), t.userId, t.role, t.authSessionId)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected.Error(), err.Error())
@@ -173,16 +188,19 @@ func (t *TokenServiceTest) TestCreateCredentialSetRefreshTokenFailed() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("SignAuth", t.userId, t.role, t.authSessionId).Return(t.accessToken, nil)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
 	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
-	accessTokenRepo.EXPECT().SetValue(t.authSessionId, accessTokenCache, t.jwtConfig.ExpiresIn).Return(nil)
-	refreshTokenRepo.EXPECT().SetValue(t.refreshToken.String(), refreshTokenCache, t.jwtConfig.RefreshTokenTTL).Return(setCacheErr)
+	accessTokenRepo.EXPECT().SetValue(gomock.Any(), t.authSessionId, accessTokenCache, t.jwtConfig.ExpiresIn).Return(nil)
+	refreshTokenRepo.EXPECT().SetValue(gomock.Any(), t.refreshToken.String(), refreshTokenCache, t.jwtConfig.RefreshTokenTTL).Return(setCacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.CreateCredential(t.userId, t.role, t.authSessionId)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.CreateCredential(context.Background(), t.userId, t.role, t.authSessionId)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected.Error(), err.Error())
@@ -214,14 +232,17 @@ func (t *TokenServiceTest) TestValidateSuccess() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("VerifyAuth", t.validateToken).Return(jwtToken, nil)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
-	accessTokenRepo.EXPECT().GetValue(payloads["auth_session_id"].(string), accessTokenCache).Return(nil)
+	accessTokenRepo.EXPECT().GetValue(gomock.Any(), payloads["auth_session_id"].(string), accessTokenCache).Return(nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.Validate(t.validateToken)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.Validate(context.Background(), t.validateToken)
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), *expected, *actual)
@@ -249,13 +270,16 @@ func (t *TokenServiceTest) TestValidateInvalidIssuer() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("VerifyAuth", t.validateToken).Return(jwtToken, nil)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.Validate(t.validateToken)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.Validate(context.Background(), t.validateToken)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected.Error(), err.Error())
@@ -282,13 +306,16 @@ func (t *TokenServiceTest) TestValidateExpireToken() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("VerifyAuth", t.validateToken).Return(jwtToken, nil)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.Validate(t.validateToken)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.Validate(context.Background(), t.validateToken)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected.Error(), err.Error())
@@ -303,12 +330,15 @@ func (t *TokenServiceTest) TestValidateVerifyFailed() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("VerifyAuth", t.validateToken).Return(nil, expected)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.Validate(t.validateToken)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.Validate(context.Background(), t.validateToken)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected.Error(), err.Error())
@@ -336,14 +366,17 @@ func (t *TokenServiceTest) TestValidateGetCacheKeyNotFound() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("VerifyAuth", t.validateToken).Return(jwtToken, nil)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
-	accessTokenRepo.EXPECT().GetValue(payloads["auth_session_id"].(string), accessTokenCache).Return(redis.Nil)
+	accessTokenRepo.EXPECT().GetValue(gomock.Any(), payloads["auth_session_id"].(string), accessTokenCache).Return(redis.Nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.Validate(t.validateToken)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.Validate(context.Background(), t.validateToken)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected.Error(), err.Error())
@@ -372,14 +405,17 @@ func (t *TokenServiceTest) TestValidateGetCacheInternalFailed() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("VerifyAuth", t.validateToken).Return(jwtToken, nil)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
-	accessTokenRepo.EXPECT().GetValue(payloads["auth_session_id"].(string), accessTokenCache).Return(getCacheErr)
+	accessTokenRepo.EXPECT().GetValue(gomock.Any(), payloads["auth_session_id"].(string), accessTokenCache).Return(getCacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.Validate(t.validateToken)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.Validate(context.Background(), t.validateToken)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected.Error(), err.Error())
@@ -408,14 +444,17 @@ func (t *TokenServiceTest) TestValidateInvalidToken() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	jwtService.On("VerifyAuth", invalidToken).Return(jwtToken, nil)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
-	accessTokenRepo.EXPECT().GetValue(payloads["auth_session_id"].(string), accessTokenCache).Return(nil)
+	accessTokenRepo.EXPECT().GetValue(gomock.Any(), payloads["auth_session_id"].(string), accessTokenCache).Return(nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.Validate(invalidToken)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.Validate(context.Background(), invalidToken)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected.Error(), err.Error())
@@ -430,11 +469,14 @@ func (t *TokenServiceTest) TestCreateRefreshTokenSuccess() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
 	actual := tokenSvc.CreateRefreshToken()
 
 	assert.Equal(t.T(), expected, actual)
@@ -447,12 +489,15 @@ func (t *TokenServiceTest) TestRemoveAccessTokenCacheSuccess() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	accessTokenRepo.EXPECT().DeleteValue(t.authSessionId).Return(nil)
+	accessTokenRepo.EXPECT().DeleteValue(gomock.Any(), t.authSessionId).Return(nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	err := tokenSvc.RemoveAccessTokenCache(t.authSessionId)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	err := tokenSvc.RemoveAccessTokenCache(context.Background(), t.authSessionId)
 
 	assert.Nil(t.T(), err)
 }
@@ -468,16 +513,70 @@ func (t *TokenServiceTest) TestRemoveAccessTokenCacheDeleteInternalFailed() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	accessTokenRepo.EXPECT().DeleteValue(t.authSessionId).Return(deleteAccessTokenCacheErr)
+	accessTokenRepo.EXPECT().DeleteValue(gomock.Any(), t.authSessionId).Return(deleteAccessTokenCacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	err := tokenSvc.RemoveAccessTokenCache(t.authSessionId)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	err := tokenSvc.RemoveAccessTokenCache(context.Background(), t.authSessionId)
 
 	assert.Equal(t.T(), expected, err)
 }
 
+func (t *TokenServiceTest) TestRemoveCredentialSuccess() {
+	accessTokenCache := &dto.AccessTokenCache{}
+	cached := &dto.AccessTokenCache{
+		Token:        t.accessToken,
+		Role:         t.role,
+		RefreshToken: t.refreshToken.String(),
+	}
+
+	controller := gomock.NewController(t.T())
+
+	jwtService := jwt.JwtServiceMock{}
+	accessTokenRepo := mock_cache.NewMockRepository(controller)
+	refreshTokenRepo := mock_cache.NewMockRepository(controller)
+	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
+	uuidUtil := utils.UuidUtilMock{}
+
+	accessTokenRepo.EXPECT().GetValue(gomock.Any(), t.authSessionId, accessTokenCache).SetArg(2, *cached).Return(nil)
+	refreshTokenRepo.EXPECT().DeleteValue(gomock.Any(), t.refreshToken.String()).Return(nil)
+	accessTokenRepo.EXPECT().DeleteValue(gomock.Any(), t.authSessionId).Return(nil)
+
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	err := tokenSvc.RemoveCredential(context.Background(), t.authSessionId)
+
+	assert.Nil(t.T(), err)
+}
+
+func (t *TokenServiceTest) TestRemoveCredentialExpired() {
+	accessTokenCache := &dto.AccessTokenCache{}
+
+	controller := gomock.NewController(t.T())
+
+	jwtService := jwt.JwtServiceMock{}
+	accessTokenRepo := mock_cache.NewMockRepository(controller)
+	refreshTokenRepo := mock_cache.NewMockRepository(controller)
+	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
+	uuidUtil := utils.UuidUtilMock{}
+
+	accessTokenRepo.EXPECT().GetValue(gomock.Any(), t.authSessionId, accessTokenCache).Return(redis.Nil)
+
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	err := tokenSvc.RemoveCredential(context.Background(), t.authSessionId)
+
+	assert.Nil(t.T(), err)
+}
+
 func (t *TokenServiceTest) TestFindRefreshTokenCacheSuccess() {
 	expected := &dto.RefreshTokenCache{}
 
@@ -487,12 +586,15 @@ func (t *TokenServiceTest) TestFindRefreshTokenCacheSuccess() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	refreshTokenRepo.EXPECT().GetValue(t.refreshToken.String(), &dto.RefreshTokenCache{}).Return(nil)
+	refreshTokenRepo.EXPECT().GetValue(gomock.Any(), t.refreshToken.String(), &dto.RefreshTokenCache{}).Return(nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.FindRefreshTokenCache(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.FindRefreshTokenCache(context.Background(), t.refreshToken.String())
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), expected, actual)
@@ -509,12 +611,15 @@ func (t *TokenServiceTest) TestFindRefreshTokenCacheInvalid() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	refreshTokenRepo.EXPECT().GetValue(t.refreshToken.String(), &dto.RefreshTokenCache{}).Return(getCacheErr)
+	refreshTokenRepo.EXPECT().GetValue(gomock.Any(), t.refreshToken.String(), &dto.RefreshTokenCache{}).Return(getCacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.FindRefreshTokenCache(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.FindRefreshTokenCache(context.Background(), t.refreshToken.String())
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected, err)
@@ -531,12 +636,15 @@ func (t *TokenServiceTest) TestFindRefreshTokenCacheInternalError() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	refreshTokenRepo.EXPECT().GetValue(t.refreshToken.String(), &dto.RefreshTokenCache{}).Return(getCacheErr)
+	refreshTokenRepo.EXPECT().GetValue(gomock.Any(), t.refreshToken.String(), &dto.RefreshTokenCache{}).Return(getCacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.FindRefreshTokenCache(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.FindRefreshTokenCache(context.Background(), t.refreshToken.String())
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected, err)
@@ -549,12 +657,15 @@ func (t *TokenServiceTest) TestRemoveRefreshTokenCacheSuccess() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	refreshTokenRepo.EXPECT().DeleteValue(t.refreshToken.String()).Return(nil)
+	refreshTokenRepo.EXPECT().DeleteValue(gomock.Any(), t.refreshToken.String()).Return(nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	err := tokenSvc.RemoveRefreshTokenCache(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	err := tokenSvc.RemoveRefreshTokenCache(context.Background(), t.refreshToken.String())
 
 	assert.Nil(t.T(), err)
 }
@@ -570,12 +681,15 @@ func (t *TokenServiceTest) TestRemoveRefreshTokenCacheDeleteInternalFailed() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	refreshTokenRepo.EXPECT().DeleteValue(t.refreshToken.String()).Return(deleteRefreshTokenCacheErr)
+	refreshTokenRepo.EXPECT().DeleteValue(gomock.Any(), t.refreshToken.String()).Return(deleteRefreshTokenCacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	err := tokenSvc.RemoveRefreshTokenCache(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	err := tokenSvc.RemoveRefreshTokenCache(context.Background(), t.refreshToken.String())
 
 	assert.Equal(t.T(), expected, err)
 }
@@ -591,14 +705,17 @@ func (t *TokenServiceTest) TestCreateResetPasswordTokenSuccess() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
-	resetPasswordTokenRepo.EXPECT().SetValue(t.refreshToken.String(), tokenCache, t.jwtConfig.ResetTokenTTL).Return(nil)
+	resetPasswordTokenRepo.EXPECT().SetValue(gomock.Any(), "reset:"+t.refreshToken.String(), tokenCache, t.jwtConfig.ResetTokenTTL).Return(nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.CreateResetPasswordToken(t.userId)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.CreateResetPasswordToken(context.Background(), t.userId)
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), t.refreshToken.String(), actual)
@@ -618,14 +735,17 @@ func (t *TokenServiceTest) TestCreateResetPasswordTokenFailed() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
 	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
 	jwtService.On("GetConfig").Return(t.jwtConfig)
-	resetPasswordTokenRepo.EXPECT().SetValue(t.refreshToken.String(), tokenCache, t.jwtConfig.ResetTokenTTL).Return(cacheErr)
+	resetPasswordTokenRepo.EXPECT().SetValue(gomock.Any(), "reset:"+t.refreshToken.String(), tokenCache, t.jwtConfig.ResetTokenTTL).Return(cacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.CreateResetPasswordToken(t.userId)
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.CreateResetPasswordToken(context.Background(), t.userId)
 
 	assert.Equal(t.T(), "", actual)
 	assert.Equal(t.T(), expected, err)
@@ -642,12 +762,15 @@ func (t *TokenServiceTest) TestFindResetPasswordTokenSuccess() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	resetPasswordTokenRepo.EXPECT().GetValue(t.refreshToken.String(), tokenCache).Return(nil)
+	resetPasswordTokenRepo.EXPECT().GetValue(gomock.Any(), "reset:"+t.refreshToken.String(), tokenCache).Return(nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.FindResetPasswordToken(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.FindResetPasswordToken(context.Background(), t.refreshToken.String())
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), expected, actual)
@@ -665,12 +788,17 @@ func (t *TokenServiceTest) TestFindResetPasswordTokenNotFound() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	resetPasswordTokenRepo.EXPECT().GetValue(t.refreshToken.String(), tokenCache).Return(cacheErr)
+	jwtService.On("GetConfig").Return(t.jwtConfig)
+	resetPasswordTokenRepo.EXPECT().GetValue(gomock.Any(), "reset:"+t.refreshToken.String(), tokenCache).Return(cacheErr)
+	resetPasswordTokenRepo.EXPECT().GetValue(gomock.Any(), t.refreshToken.String(), gomock.Any()).Return(cacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.FindResetPasswordToken(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.FindResetPasswordToken(context.Background(), t.refreshToken.String())
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected, err)
@@ -688,12 +816,15 @@ func (t *TokenServiceTest) TestFindResetPasswordTokenInternalError() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	resetPasswordTokenRepo.EXPECT().GetValue(t.refreshToken.String(), tokenCache).Return(cacheErr)
+	resetPasswordTokenRepo.EXPECT().GetValue(gomock.Any(), "reset:"+t.refreshToken.String(), tokenCache).Return(cacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	actual, err := tokenSvc.FindResetPasswordToken(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.FindResetPasswordToken(context.Background(), t.refreshToken.String())
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), expected, err)
@@ -706,12 +837,17 @@ func (t *TokenServiceTest) TestRemoveResetPasswordTokenSuccess() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	resetPasswordTokenRepo.EXPECT().DeleteValue(t.refreshToken.String()).Return(nil)
+	jwtService.On("GetConfig").Return(t.jwtConfig)
+	resetPasswordTokenRepo.EXPECT().DeleteValue(gomock.Any(), "reset:"+t.refreshToken.String()).Return(nil)
+	resetPasswordTokenRepo.EXPECT().DeleteValue(gomock.Any(), t.refreshToken.String()).Return(nil)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	err := tokenSvc.RemoveResetPasswordToken(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	err := tokenSvc.RemoveResetPasswordToken(context.Background(), t.refreshToken.String())
 
 	assert.Nil(t.T(), err)
 }
@@ -727,12 +863,291 @@ func (t *TokenServiceTest) TestRemoveResetPasswordTokenFailed() {
 	accessTokenRepo := mock_cache.NewMockRepository(controller)
 	refreshTokenRepo := mock_cache.NewMockRepository(controller)
 	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
 	uuidUtil := utils.UuidUtilMock{}
 
-	resetPasswordTokenRepo.EXPECT().DeleteValue(t.refreshToken.String()).Return(cacheErr)
+	resetPasswordTokenRepo.EXPECT().DeleteValue(gomock.Any(), "reset:"+t.refreshToken.String()).Return(cacheErr)
 
-	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, &uuidUtil)
-	err := tokenSvc.RemoveResetPasswordToken(t.refreshToken.String())
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	err := tokenSvc.RemoveResetPasswordToken(context.Background(), t.refreshToken.String())
 
 	assert.Equal(t.T(), expected, err)
 }
+
+func (t *TokenServiceTest) TestCreateVerifyEmailTokenSuccess() {
+	email := faker.Email()
+	tokenCache := &dto.VerifyEmailTokenCache{
+		UserID: t.userId,
+		Email:  email,
+	}
+
+	controller := gomock.NewController(t.T())
+
+	jwtService := jwt.JwtServiceMock{}
+	accessTokenRepo := mock_cache.NewMockRepository(controller)
+	refreshTokenRepo := mock_cache.NewMockRepository(controller)
+	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
+	uuidUtil := utils.UuidUtilMock{}
+
+	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
+	jwtService.On("GetConfig").Return(t.jwtConfig)
+	verifyEmailTokenRepo.EXPECT().SetValue(gomock.Any(), "verify:"+t.refreshToken.String(), tokenCache, t.jwtConfig.VerifyEmailTokenTTL).Return(nil)
+
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.CreateVerifyEmailToken(context.Background(), t.userId, email)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), t.refreshToken.String(), actual)
+}
+
+func (t *TokenServiceTest) TestFindVerifyEmailTokenNotFound() {
+	expected := status.Error(codes.InvalidArgument, redis.Nil.Error())
+
+	controller := gomock.NewController(t.T())
+
+	jwtService := jwt.JwtServiceMock{}
+	accessTokenRepo := mock_cache.NewMockRepository(controller)
+	refreshTokenRepo := mock_cache.NewMockRepository(controller)
+	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
+	uuidUtil := utils.UuidUtilMock{}
+
+	jwtService.On("GetConfig").Return(t.jwtConfig)
+	verifyEmailTokenRepo.EXPECT().GetValue(gomock.Any(), "verify:"+t.refreshToken.String(), &dto.VerifyEmailTokenCache{}).Return(redis.Nil)
+	verifyEmailTokenRepo.EXPECT().GetValue(gomock.Any(), t.refreshToken.String(), gomock.Any()).Return(redis.Nil)
+
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.FindVerifyEmailToken(context.Background(), t.refreshToken.String())
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), expected, err)
+}
+
+func (t *TokenServiceTest) TestCreateTwoFactorChallengeSuccess() {
+	tokenCache := &dto.TwoFactorChallengeCache{
+		UserID: t.userId,
+	}
+
+	controller := gomock.NewController(t.T())
+
+	jwtService := jwt.JwtServiceMock{}
+	accessTokenRepo := mock_cache.NewMockRepository(controller)
+	refreshTokenRepo := mock_cache.NewMockRepository(controller)
+	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
+	uuidUtil := utils.UuidUtilMock{}
+
+	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
+	jwtService.On("GetConfig").Return(t.jwtConfig)
+	twoFactorChallengeRepo.EXPECT().SetValue(gomock.Any(), "2fa:"+t.refreshToken.String(), tokenCache, t.jwtConfig.TwoFactorChallengeTTL).Return(nil)
+
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.CreateTwoFactorChallenge(context.Background(), t.userId)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), t.refreshToken.String(), actual)
+}
+
+func (t *TokenServiceTest) TestFindTwoFactorChallengeNotFound() {
+	expected := status.Error(codes.InvalidArgument, redis.Nil.Error())
+
+	controller := gomock.NewController(t.T())
+
+	jwtService := jwt.JwtServiceMock{}
+	accessTokenRepo := mock_cache.NewMockRepository(controller)
+	refreshTokenRepo := mock_cache.NewMockRepository(controller)
+	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
+	uuidUtil := utils.UuidUtilMock{}
+
+	twoFactorChallengeRepo.EXPECT().GetValue(gomock.Any(), "2fa:"+t.refreshToken.String(), &dto.TwoFactorChallengeCache{}).Return(redis.Nil)
+
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.FindTwoFactorChallenge(context.Background(), t.refreshToken.String())
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), expected, err)
+}
+
+func (t *TokenServiceTest) TestCreateMagicLinkTokenSuccess() {
+	tokenCache := &dto.MagicLinkTokenCache{
+		UserID: t.userId,
+	}
+
+	controller := gomock.NewController(t.T())
+
+	jwtService := jwt.JwtServiceMock{}
+	accessTokenRepo := mock_cache.NewMockRepository(controller)
+	refreshTokenRepo := mock_cache.NewMockRepository(controller)
+	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
+	uuidUtil := utils.UuidUtilMock{}
+
+	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
+	jwtService.On("GetConfig").Return(t.jwtConfig)
+	magicLinkTokenRepo.EXPECT().SetValue(gomock.Any(), "magic:"+t.refreshToken.String(), tokenCache, t.jwtConfig.MagicLinkTokenTTL).Return(nil)
+
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.CreateMagicLinkToken(context.Background(), t.userId)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), t.refreshToken.String(), actual)
+}
+
+func (t *TokenServiceTest) TestConsumeMagicLinkTokenSuccess() {
+	tokenCache := &dto.MagicLinkTokenCache{
+		UserID: t.userId,
+	}
+
+	controller := gomock.NewController(t.T())
+
+	jwtService := jwt.JwtServiceMock{}
+	accessTokenRepo := mock_cache.NewMockRepository(controller)
+	refreshTokenRepo := mock_cache.NewMockRepository(controller)
+	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
+	uuidUtil := utils.UuidUtilMock{}
+
+	magicLinkTokenRepo.EXPECT().PopValue(gomock.Any(), "magic:"+t.refreshToken.String(), &dto.MagicLinkTokenCache{}).SetArg(2, *tokenCache).Return(nil)
+
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.ConsumeMagicLinkToken(context.Background(), t.refreshToken.String())
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), tokenCache, actual)
+}
+
+func (t *TokenServiceTest) TestConsumeMagicLinkTokenUsed() {
+	expected := status.Error(codes.InvalidArgument, redis.Nil.Error())
+
+	controller := gomock.NewController(t.T())
+
+	jwtService := jwt.JwtServiceMock{}
+	accessTokenRepo := mock_cache.NewMockRepository(controller)
+	refreshTokenRepo := mock_cache.NewMockRepository(controller)
+	resetPasswordTokenRepo := mock_cache.NewMockRepository(controller)
+	verifyEmailTokenRepo := mock_cache.NewMockRepository(controller)
+	twoFactorChallengeRepo := mock_cache.NewMockRepository(controller)
+	magicLinkTokenRepo := mock_cache.NewMockRepository(controller)
+	uuidUtil := utils.UuidUtilMock{}
+
+	magicLinkTokenRepo.EXPECT().PopValue(gomock.Any(), "magic:"+t.refreshToken.String(), &dto.MagicLinkTokenCache{}).Return(redis.Nil)
+
+	tokenSvc := token.NewService(&jwtService, accessTokenRepo, refreshTokenRepo, resetPasswordTokenRepo, verifyEmailTokenRepo, twoFactorChallengeRepo, magicLinkTokenRepo, &uuidUtil)
+	actual, err := tokenSvc.ConsumeMagicLinkToken(context.Background(), t.refreshToken.String())
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), expected, err)
+}
+
+func (t *TokenServiceTest) TestTokenOfOneKindRejectedByTheOthers() {
+	server := miniredis.RunT(t.T())
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	jwtService := jwt.JwtServiceMock{}
+	uuidUtil := utils.UuidUtilMock{}
+	jwtService.On("GetConfig").Return(t.jwtConfig)
+	uuidUtil.On("GetNewUUID").Return(t.refreshToken)
+
+	// every cache shares the redis client like the app does
+	tokenSvc := token.NewService(&jwtService, cache.NewRepository(client), cache.NewRepository(client), cache.NewRepository(client), cache.NewRepository(client), cache.NewRepository(client), cache.NewRepository(client), &uuidUtil)
+	ctx := context.Background()
+
+	find := map[string]func(string) error{
+		"refresh": func(tok string) error {
+			_, err := tokenSvc.FindRefreshTokenCache(ctx, tok)
+			return err
+		},
+		"reset": func(tok string) error {
+			_, err := tokenSvc.FindResetPasswordToken(ctx, tok)
+			return err
+		},
+		"verify": func(tok string) error {
+			_, err := tokenSvc.FindVerifyEmailToken(ctx, tok)
+			return err
+		},
+		"2fa": func(tok string) error {
+			_, err := tokenSvc.FindTwoFactorChallenge(ctx, tok)
+			return err
+		},
+		"magic": func(tok string) error {
+			_, err := tokenSvc.ConsumeMagicLinkToken(ctx, tok)
+			return err
+		},
+	}
+	create := map[string]func() (string, error){
+		"reset":  func() (string, error) { return tokenSvc.CreateResetPasswordToken(ctx, t.userId) },
+		"verify": func() (string, error) { return tokenSvc.CreateVerifyEmailToken(ctx, t.userId, faker.Email()) },
+		"2fa":    func() (string, error) { return tokenSvc.CreateTwoFactorChallenge(ctx, t.userId) },
+		"magic":  func() (string, error) { return tokenSvc.CreateMagicLinkToken(ctx, t.userId) },
+	}
+
+	for kind, createToken := range create {
+		server.FlushAll()
+		tok, err := createToken()
+		assert.Nil(t.T(), err)
+
+		for other, findToken := range find {
+			if other == kind {
+				continue
+			}
+			err := findToken(tok)
+			assert.Equal(t.T(), codes.InvalidArgument, status.Code(err), "%s token used as %s", kind, other)
+		}
+		assert.Nil(t.T(), find[kind](tok), "%s token", kind)
+	}
+}
+
+func (t *TokenServiceTest) TestLegacyTokenKeys() {
+	server := miniredis.RunT(t.T())
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	jwtService := jwt.JwtServiceMock{}
+	jwtService.On("GetConfig").Return(t.jwtConfig)
+	tokenSvc := token.NewService(&jwtService, cache.NewRepository(client), cache.NewRepository(client), cache.NewRepository(client), cache.NewRepository(client), cache.NewRepository(client), cache.NewRepository(client), &utils.UuidUtilMock{})
+	ctx := context.Background()
+
+	// tokens saved without a prefix before the upgrade, including a refresh token which must not pass as either kind
+	email := faker.Email()
+	server.Set("legacy-reset", `{"user_id":"`+t.userId+`"}`)
+	server.Set("legacy-verify", `{"user_id":"`+t.userId+`","email":"`+email+`"}`)
+	server.Set("refresh", `{"auth_session_id":"`+t.authSessionId+`","user_id":"`+t.userId+`","role":"admin"}`)
+
+	reset, err := tokenSvc.FindResetPasswordToken(ctx, "legacy-reset")
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.ResetPasswordTokenCache{UserID: t.userId}, reset)
+
+	verify, err := tokenSvc.FindVerifyEmailToken(ctx, "legacy-verify")
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.VerifyEmailTokenCache{UserID: t.userId, Email: email}, verify)
+
+	for _, tok := range []string{"legacy-verify", "refresh"} {
+		_, err := tokenSvc.FindResetPasswordToken(ctx, tok)
+		assert.Equal(t.T(), codes.InvalidArgument, status.Code(err), "%s used as reset", tok)
+	}
+	for _, tok := range []string{"legacy-reset", "refresh"} {
+		_, err := tokenSvc.FindVerifyEmailToken(ctx, tok)
+		assert.Equal(t.T(), codes.InvalidArgument, status.Code(err), "%s used as verify", tok)
+	}
+
+	assert.Nil(t.T(), tokenSvc.RemoveResetPasswordToken(ctx, "legacy-reset"))
+	assert.False(t.T(), server.Exists("legacy-reset"))
+	assert.True(t.T(), server.Exists("refresh"))
+}
@@ -1,6 +1,9 @@
 package token
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	_jwt "github.com/golang-jwt/jwt/v4"
@@ -17,15 +20,24 @@ import (
 )
 
 type Service interface {
-	CreateCredential(userId string, role constant.Role, authSessionId string) (*dto.Credential, error)
-	Validate(token string) (*dto.UserCredential, error)
+	CreateCredential(ctx context.Context, userId string, role constant.Role, authSessionId string) (*dto.Credential, error)
+	Validate(ctx context.Context, token string) (*dto.UserCredential, error)
 	CreateRefreshToken() string
-	RemoveAccessTokenCache(authSessionId string) error
-	FindRefreshTokenCache(refreshToken string) (*dto.RefreshTokenCache, error)
-	RemoveRefreshTokenCache(refreshToken string) error
-	CreateResetPasswordToken(userId string) (string, error)
-	FindResetPasswordToken(token string) (*dto.ResetPasswordTokenCache, error)
-	RemoveResetPasswordToken(token string) error
+	RemoveAccessTokenCache(ctx context.Context, authSessionId string) error
+	RemoveCredential(ctx context.Context, authSessionId string) error
+	FindRefreshTokenCache(ctx context.Context, refreshToken string) (*dto.RefreshTokenCache, error)
+	RemoveRefreshTokenCache(ctx context.Context, refreshToken string) error
+	CreateResetPasswordToken(ctx context.Context, userId string) (string, error)
+	FindResetPasswordToken(ctx context.Context, token string) (*dto.ResetPasswordTokenCache, error)
+	RemoveResetPasswordToken(ctx context.Context, token string) error
+	CreateVerifyEmailToken(ctx context.Context, userId string, email string) (string, error)
+	FindVerifyEmailToken(ctx context.Context, token string) (*dto.VerifyEmailTokenCache, error)
+	RemoveVerifyEmailToken(ctx context.Context, token string) error
+	CreateTwoFactorChallenge(ctx context.Context, userId string) (string, error)
+	FindTwoFactorChallenge(ctx context.Context, token string) (*dto.TwoFactorChallengeCache, error)
+	RemoveTwoFactorChallenge(ctx context.Context, token string) error
+	CreateMagicLinkToken(ctx context.Context, userId string) (string, error)
+	ConsumeMagicLinkToken(ctx context.Context, token string) (*dto.MagicLinkTokenCache, error)
 }
 
 type serviceImpl struct {
@@ -33,23 +45,32 @@ type serviceImpl struct {
 	accessTokenCache        cache.Repository
 	refreshTokenCache       cache.Repository
 	resetPasswordTokenCache cache.Repository
+	verifyEmailTokenCache   cache.Repository
+	twoFactorChallengeCache cache.Repository
+	magicLinkTokenCache     cache.Repository
 	uuidUtil                utils.IUuidUtil
+	startedAt               time.Time
 }
 
-func NewService(jwtService jwt.Service, accessTokenCache cache.Repository, refreshTokenCache cache.Repository, resetPasswordTokenCache cache.Repository, uuidUtil utils.IUuidUtil) Service {
+func NewService(jwtService jwt.Service, accessTokenCache cache.Repository, refreshTokenCache cache.Repository, resetPasswordTokenCache cache.Repository, verifyEmailTokenCache cache.Repository, twoFactorChallengeCache cache.Repository, magicLinkTokenCache cache.Repository, uuidUtil utils.IUuidUtil) Service {
 	return &serviceImpl{
 		jwtService:              jwtService,
 		accessTokenCache:        accessTokenCache,
 		refreshTokenCache:       refreshTokenCache,
 		resetPasswordTokenCache: resetPasswordTokenCache,
+		verifyEmailTokenCache:   verifyEmailTokenCache,
+		twoFactorChallengeCache: twoFactorChallengeCache,
+		magicLinkTokenCache:     magicLinkTokenCache,
 		uuidUtil:                uuidUtil,
+		startedAt:               time.Now(),
 	}
 }
 
-func (s *serviceImpl) CreateCredential(userId string, role constant.Role, authSessionId string) (*dto.Credential, error) {
+func (s *serviceImpl) CreateCredential(ctx context.Context, userId string, role constant.Role, authSessionId string) (*dto.Credential, error) {
 	accessToken, err := s.jwtService.SignAuth(userId, role, authSessionId)
 	if err != nil {
 		log.Error().
+			Ctx(ctx).
 			Err(err).
 			Str("service", "token").
 			Str("module", "CreateCredential").
@@ -65,9 +86,10 @@ func (s *serviceImpl) CreateCredential(userId string, role constant.Role, authSe
 		Role:         role,
 		RefreshToken: refreshToken,
 	}
-	err = s.accessTokenCache.SetValue(authSessionId, accessTokenCache, jwtConf.ExpiresIn)
+	err = s.accessTokenCache.SetValue(ctx, authSessionId, accessTokenCache, jwtConf.ExpiresIn)
 	if err != nil {
 		log.Error().
+			Ctx(ctx).
 			Err(err).
 			Str("service", "token").
 			Str("module", "CreateCredential").
@@ -80,9 +102,10 @@ func (s *serviceImpl) CreateCredential(userId string, role constant.Role, authSe
 		UserID:        userId,
 		Role:          role,
 	}
-	err = s.refreshTokenCache.SetValue(refreshToken, refreshTokenCache, jwtConf.RefreshTokenTTL)
+	err = s.refreshTokenCache.SetValue(ctx, refreshToken, refreshTokenCache, jwtConf.RefreshTokenTTL)
 	if err != nil {
 		log.Error().
+			Ctx(ctx).
 			Err(err).
 			Str("service", "token").
 			Str("module", "CreateCredential").
@@ -99,7 +122,7 @@ func (s *serviceImpl) CreateCredential(userId string, role constant.Role, authSe
 	return credential, nil
 }
 
-func (s *serviceImpl) Validate(token string) (*dto.UserCredential, error) {
+func (s *serviceImpl) Validate(ctx context.Context, token string) (*dto.UserCredential, error) {
 	jwtToken, err := s.jwtService.VerifyAuth(token)
 	if err != nil {
 		return nil, err
@@ -115,7 +138,7 @@ func (s *serviceImpl) Validate(token string) (*dto.UserCredential, error) {
 	}
 
 	accessTokenCache := &dto.AccessTokenCache{}
-	err = s.accessTokenCache.GetValue(payloads["auth_session_id"].(string), accessTokenCache)
+	err = s.accessTokenCache.GetValue(ctx, payloads["auth_session_id"].(string), accessTokenCache)
 	if err != nil {
 		if err != redis.Nil {
 			return nil, err
@@ -140,8 +163,8 @@ func (s *serviceImpl) CreateRefreshToken() string {
 	return s.uuidUtil.GetNewUUID().String()
 }
 
-func (s *serviceImpl) RemoveAccessTokenCache(authSessionId string) error {
-	err := s.accessTokenCache.DeleteValue(authSessionId)
+func (s *serviceImpl) RemoveAccessTokenCache(ctx context.Context, authSessionId string) error {
+	err := s.accessTokenCache.DeleteValue(ctx, authSessionId)
 	if err != nil {
 		if err != redis.Nil {
 			return err
@@ -151,11 +174,30 @@ func (s *serviceImpl) RemoveAccessTokenCache(authSessionId string) error {
 	return nil
 }
 
-func (s *serviceImpl) FindRefreshTokenCache(refreshToken string) (*dto.RefreshTokenCache, error) {
+// RemoveCredential removes both the access token and the refresh token issued to the auth session
+func (s *serviceImpl) RemoveCredential(ctx context.Context, authSessionId string) error {
+	accessTokenCache := &dto.AccessTokenCache{}
+	err := s.accessTokenCache.GetValue(ctx, authSessionId, accessTokenCache)
+	if err != nil {
+		if err != redis.Nil {
+			return err
+		}
+		return nil
+	}
+
+	if err := s.RemoveRefreshTokenCache(ctx, accessTokenCache.RefreshToken); err != nil {
+		return err
+	}
+
+	return s.RemoveAccessTokenCache(ctx, authSessionId)
+}
+
+func (s *serviceImpl) FindRefreshTokenCache(ctx context.Context, refreshToken string) (*dto.RefreshTokenCache, error) {
 	refreshTokenCache := &dto.RefreshTokenCache{}
-	err := s.refreshTokenCache.GetValue(refreshToken, refreshTokenCache)
+	err := s.refreshTokenCache.GetValue(ctx, refreshToken, refreshTokenCache)
 	if err != nil {
 		log.Error().
+			Ctx(ctx).
 			Err(err).
 			Str("service", "token").
 			Str("module", "FindRefreshTokenCache").
@@ -169,8 +211,8 @@ func (s *serviceImpl) FindRefreshTokenCache(refreshToken string) (*dto.RefreshTo
 	return refreshTokenCache, nil
 }
 
-func (s *serviceImpl) RemoveRefreshTokenCache(refreshToken string) error {
-	err := s.refreshTokenCache.DeleteValue(refreshToken)
+func (s *serviceImpl) RemoveRefreshTokenCache(ctx context.Context, refreshToken string) error {
+	err := s.refreshTokenCache.DeleteValue(ctx, refreshToken)
 	if err != nil {
 		if err != redis.Nil {
 			return err
@@ -180,21 +222,24 @@ func (s *serviceImpl) RemoveRefreshTokenCache(refreshToken string) error {
 	return nil
 }
 
-func (s *serviceImpl) CreateResetPasswordToken(userId string) (string, error) {
+func (s *serviceImpl) CreateResetPasswordToken(ctx context.Context, userId string) (string, error) {
 	resetPasswordToken := s.CreateRefreshToken()
 	tokenCache := &dto.ResetPasswordTokenCache{
 		UserID: userId,
 	}
-	err := s.resetPasswordTokenCache.SetValue(resetPasswordToken, tokenCache, s.jwtService.GetConfig().ResetTokenTTL)
+	err := s.resetPasswordTokenCache.SetValue(ctx, resetPasswordKey(resetPasswordToken), tokenCache, s.jwtService.GetConfig().ResetTokenTTL)
 	if err != nil {
 		return "", err
 	}
 	return resetPasswordToken, nil
 }
 
-func (s *serviceImpl) FindResetPasswordToken(token string) (*dto.ResetPasswordTokenCache, error) {
+func (s *serviceImpl) FindResetPasswordToken(ctx context.Context, token string) (*dto.ResetPasswordTokenCache, error) {
 	tokenCache := &dto.ResetPasswordTokenCache{}
-	err := s.resetPasswordTokenCache.GetValue(token, tokenCache)
+	err := s.resetPasswordTokenCache.GetValue(ctx, resetPasswordKey(token), tokenCache)
+	if err == redis.Nil {
+		err = s.findLegacyToken(ctx, s.resetPasswordTokenCache, token, s.jwtService.GetConfig().ResetTokenTTL, tokenCache, "user_id")
+	}
 	if err != nil {
 		if err != redis.Nil {
 			return nil, status.Error(codes.Internal, err.Error())
@@ -205,8 +250,11 @@ func (s *serviceImpl) FindResetPasswordToken(token string) (*dto.ResetPasswordTo
 	return tokenCache, nil
 }
 
-func (s *serviceImpl) RemoveResetPasswordToken(token string) error {
-	err := s.resetPasswordTokenCache.DeleteValue(token)
+func (s *serviceImpl) RemoveResetPasswordToken(ctx context.Context, token string) error {
+	err := s.resetPasswordTokenCache.DeleteValue(ctx, resetPasswordKey(token))
+	if err == nil {
+		err = s.removeLegacyToken(ctx, s.resetPasswordTokenCache, token, s.jwtService.GetConfig().ResetTokenTTL)
+	}
 	if err != nil {
 		if err != redis.Nil {
 			return err
@@ -215,3 +263,166 @@ func (s *serviceImpl) RemoveResetPasswordToken(token string) error {
 
 	return nil
 }
+
+func (s *serviceImpl) CreateVerifyEmailToken(ctx context.Context, userId string, email string) (string, error) {
+	verifyEmailToken := s.CreateRefreshToken()
+	tokenCache := &dto.VerifyEmailTokenCache{
+		UserID: userId,
+		Email:  email,
+	}
+	err := s.verifyEmailTokenCache.SetValue(ctx, verifyEmailKey(verifyEmailToken), tokenCache, s.jwtService.GetConfig().VerifyEmailTokenTTL)
+	if err != nil {
+		return "", err
+	}
+	return verifyEmailToken, nil
+}
+
+func (s *serviceImpl) FindVerifyEmailToken(ctx context.Context, token string) (*dto.VerifyEmailTokenCache, error) {
+	tokenCache := &dto.VerifyEmailTokenCache{}
+	err := s.verifyEmailTokenCache.GetValue(ctx, verifyEmailKey(token), tokenCache)
+	if err == redis.Nil {
+		err = s.findLegacyToken(ctx, s.verifyEmailTokenCache, token, s.jwtService.GetConfig().VerifyEmailTokenTTL, tokenCache, "user_id", "email")
+	}
+	if err != nil {
+		if err != redis.Nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return tokenCache, nil
+}
+
+func (s *serviceImpl) RemoveVerifyEmailToken(ctx context.Context, token string) error {
+	err := s.verifyEmailTokenCache.DeleteValue(ctx, verifyEmailKey(token))
+	if err == nil {
+		err = s.removeLegacyToken(ctx, s.verifyEmailTokenCache, token, s.jwtService.GetConfig().VerifyEmailTokenTTL)
+	}
+	if err != nil {
+		if err != redis.Nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) CreateTwoFactorChallenge(ctx context.Context, userId string) (string, error) {
+	challengeToken := s.CreateRefreshToken()
+	tokenCache := &dto.TwoFactorChallengeCache{
+		UserID: userId,
+	}
+	err := s.twoFactorChallengeCache.SetValue(ctx, twoFactorChallengeKey(challengeToken), tokenCache, s.jwtService.GetConfig().TwoFactorChallengeTTL)
+	if err != nil {
+		return "", err
+	}
+	return challengeToken, nil
+}
+
+func (s *serviceImpl) FindTwoFactorChallenge(ctx context.Context, token string) (*dto.TwoFactorChallengeCache, error) {
+	tokenCache := &dto.TwoFactorChallengeCache{}
+	err := s.twoFactorChallengeCache.GetValue(ctx, twoFactorChallengeKey(token), tokenCache)
+	if err != nil {
+		if err != redis.Nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return tokenCache, nil
+}
+
+func (s *serviceImpl) RemoveTwoFactorChallenge(ctx context.Context, token string) error {
+	err := s.twoFactorChallengeCache.DeleteValue(ctx, twoFactorChallengeKey(token))
+	if err != nil {
+		if err != redis.Nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) CreateMagicLinkToken(ctx context.Context, userId string) (string, error) {
+	magicLinkToken := s.CreateRefreshToken()
+	tokenCache := &dto.MagicLinkTokenCache{
+		UserID: userId,
+	}
+	err := s.magicLinkTokenCache.SetValue(ctx, magicLinkKey(magicLinkToken), tokenCache, s.jwtService.GetConfig().MagicLinkTokenTTL)
+	if err != nil {
+		return "", err
+	}
+	return magicLinkToken, nil
+}
+
+// ConsumeMagicLinkToken removes the token as it is read so the link can only be used once
+func (s *serviceImpl) ConsumeMagicLinkToken(ctx context.Context, token string) (*dto.MagicLinkTokenCache, error) {
+	tokenCache := &dto.MagicLinkTokenCache{}
+	err := s.magicLinkTokenCache.PopValue(ctx, magicLinkKey(token), tokenCache)
+	if err != nil {
+		if err != redis.Nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return tokenCache, nil
+}
+
+// findLegacyToken reads a reset or verification token saved before the keys had a prefix, so the links sent
+// before the upgrade keep working. They expire within a token lifetime of the start, after that the old key is
+// not read. The refresh tokens and the other kinds share the unprefixed keys, so the value must have exactly the
+// fields of the kind. 2fa challenges and magic links are not read from the old keys, they only last minutes
+func (s *serviceImpl) findLegacyToken(ctx context.Context, tokenCache cache.Repository, token string, ttl int, value interface{}, fields ...string) error {
+	if time.Since(s.startedAt) > time.Duration(ttl)*time.Second {
+		return redis.Nil
+	}
+
+	raw := map[string]json.RawMessage{}
+	if err := tokenCache.GetValue(ctx, token, &raw); err != nil {
+		return err
+	}
+	if len(raw) != len(fields) {
+		return redis.Nil
+	}
+	for _, field := range fields {
+		if _, ok := raw[field]; !ok {
+			return redis.Nil
+		}
+	}
+
+	v, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(v, value)
+}
+
+// removeLegacyToken removes the unprefixed key of a token found by findLegacyToken
+func (s *serviceImpl) removeLegacyToken(ctx context.Context, tokenCache cache.Repository, token string, ttl int) error {
+	if time.Since(s.startedAt) > time.Duration(ttl)*time.Second {
+		return nil
+	}
+
+	return tokenCache.DeleteValue(ctx, token)
+}
+
+// the single use tokens are all uuids kept in the same redis, so each kind has its own prefix
+// and a token of one kind is never found when it is presented as another
+
+func resetPasswordKey(token string) string {
+	return fmt.Sprintf("reset:%s", token)
+}
+
+func verifyEmailKey(token string) string {
+	return fmt.Sprintf("verify:%s", token)
+}
+
+func twoFactorChallengeKey(token string) string {
+	return fmt.Sprintf("2fa:%s", token)
+}
+
+func magicLinkKey(token string) string {
+	return fmt.Sprintf("magic:%s", token)
+}
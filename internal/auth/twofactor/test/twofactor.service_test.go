@@ -0,0 +1,202 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/twofactor"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/utils"
+	mock_twofactor "github.com/isd-sgcu/johnjud-backend/mocks/repository/twofactor"
+	user_mock "github.com/isd-sgcu/johnjud-backend/mocks/repository/user"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type TwoFactorServiceTest struct {
+	suite.Suite
+	config config.Auth
+	user   *model.User
+	secret string
+}
+
+func TestTwoFactorService(t *testing.T) {
+	suite.Run(t, new(TwoFactorServiceTest))
+}
+
+func (t *TwoFactorServiceTest) SetupTest() {
+	t.config = config.Auth{TotpIssuer: "Johnjud", RequireAdminTwoFactor: true}
+	t.secret, _ = utils.GenerateTotpSecret()
+	t.user = &model.User{
+		Base:       model.Base{ID: uuid.New()},
+		Email:      faker.Email(),
+		Role:       constant.USER,
+		TotpSecret: t.secret,
+	}
+}
+
+func (t *TwoFactorServiceTest) currentCode() string {
+	code, _ := utils.TotpCode(t.secret, utils.TotpStep(time.Now()))
+	return code
+}
+
+func (t *TwoFactorServiceTest) TestTotpCodeRfcVector() {
+	// RFC 6238 appendix B, SHA1 at T = 59
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	code, err := utils.TotpCode(secret, utils.TotpStep(time.Unix(59, 0)))
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), "287082", code)
+
+	step, ok := utils.ValidateTotp(secret, "287082", time.Unix(59, 0))
+	assert.True(t.T(), ok)
+	assert.Equal(t.T(), int64(1), step)
+
+	_, ok = utils.ValidateTotp(secret, "287082", time.Unix(59+5*30, 0))
+	assert.False(t.T(), ok)
+}
+
+func (t *TwoFactorServiceTest) TestEnrollSuccess() {
+	controller := gomock.NewController(t.T())
+	repo := mock_twofactor.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	userRepo.On("FindById", testifyMock.Anything, t.user.ID.String(), &model.User{}).Return(t.user, nil)
+	repo.EXPECT().UpdateSecret(gomock.Any(), t.user.ID.String(), gomock.Any()).Return(nil)
+
+	service := twofactor.NewService(repo, userRepo, t.config)
+	actual, err := service.Enroll(context.Background(), t.user.ID.String())
+
+	assert.Nil(t.T(), err)
+	assert.NotEmpty(t.T(), actual.Secret)
+	assert.Contains(t.T(), actual.URI, "otpauth://totp/Johnjud:")
+}
+
+func (t *TwoFactorServiceTest) TestEnrollAlreadyEnabled() {
+	t.user.TotpEnabled = true
+
+	controller := gomock.NewController(t.T())
+	repo := mock_twofactor.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	userRepo.On("FindById", testifyMock.Anything, t.user.ID.String(), &model.User{}).Return(t.user, nil)
+
+	service := twofactor.NewService(repo, userRepo, t.config)
+	actual, err := service.Enroll(context.Background(), t.user.ID.String())
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusConflict, err.StatusCode)
+}
+
+func (t *TwoFactorServiceTest) TestEnableSuccess() {
+	controller := gomock.NewController(t.T())
+	repo := mock_twofactor.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	userRepo.On("FindById", testifyMock.Anything, t.user.ID.String(), &model.User{}).Return(t.user, nil)
+	repo.EXPECT().Enable(gomock.Any(), t.user.ID.String(), gomock.Any(), gomock.Len(10)).Return(nil)
+
+	service := twofactor.NewService(repo, userRepo, t.config)
+	actual, err := service.Enable(context.Background(), t.user.ID.String(), &dto.EnableTwoFactorRequest{Code: t.currentCode()})
+
+	assert.Nil(t.T(), err)
+	assert.Len(t.T(), actual.RecoveryCodes, 10)
+}
+
+func (t *TwoFactorServiceTest) TestEnableInvalidCode() {
+	controller := gomock.NewController(t.T())
+	repo := mock_twofactor.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	userRepo.On("FindById", testifyMock.Anything, t.user.ID.String(), &model.User{}).Return(t.user, nil)
+
+	service := twofactor.NewService(repo, userRepo, t.config)
+	actual, err := service.Enable(context.Background(), t.user.ID.String(), &dto.EnableTwoFactorRequest{Code: "abcdef"})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+	assert.Equal(t.T(), constant.InvalidTwoFactorCodeErrorMessage, err.Message)
+}
+
+func (t *TwoFactorServiceTest) TestDisableRequiredForAdmin() {
+	controller := gomock.NewController(t.T())
+	repo := mock_twofactor.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	service := twofactor.NewService(repo, userRepo, t.config)
+	actual, err := service.Disable(context.Background(), t.user.ID.String(), constant.ADMIN, &dto.DisableTwoFactorRequest{Code: t.currentCode()})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusForbidden, err.StatusCode)
+}
+
+func (t *TwoFactorServiceTest) TestDisableSuccess() {
+	t.user.TotpEnabled = true
+
+	controller := gomock.NewController(t.T())
+	repo := mock_twofactor.NewMockRepository(controller)
+	userRepo := &user_mock.UserRepositoryMock{}
+
+	userRepo.On("FindById", testifyMock.Anything, t.user.ID.String(), &model.User{}).Return(t.user, nil)
+	repo.EXPECT().UpdateLastStep(gomock.Any(), t.user.ID.String(), gomock.Any()).Return(nil)
+	repo.EXPECT().Disable(gomock.Any(), t.user.ID.String()).Return(nil)
+
+	service := twofactor.NewService(repo, userRepo, t.config)
+	actual, err := service.Disable(context.Background(), t.user.ID.String(), constant.USER, &dto.DisableTwoFactorRequest{Code: t.currentCode()})
+
+	assert.Nil(t.T(), err)
+	assert.True(t.T(), actual.IsSuccess)
+}
+
+func (t *TwoFactorServiceTest) TestVerifyReplayedCode() {
+	t.user.TotpEnabled = true
+
+	controller := gomock.NewController(t.T())
+	repo := mock_twofactor.NewMockRepository(controller)
+
+	// the step was already used so the repository does not move it
+	repo.EXPECT().UpdateLastStep(gomock.Any(), t.user.ID.String(), gomock.Any()).Return(gorm.ErrRecordNotFound)
+
+	service := twofactor.NewService(repo, nil, t.config)
+	ok, err := service.Verify(context.Background(), t.user, t.currentCode())
+
+	assert.Nil(t.T(), err)
+	assert.False(t.T(), ok)
+}
+
+func (t *TwoFactorServiceTest) TestVerifyRecoveryCode() {
+	t.user.TotpEnabled = true
+	code := "ABCDE-12345"
+
+	controller := gomock.NewController(t.T())
+	repo := mock_twofactor.NewMockRepository(controller)
+
+	repo.EXPECT().UseRecoveryCode(gomock.Any(), t.user.ID.String(), twofactor.HashRecoveryCode("abcde12345")).Return(nil)
+
+	service := twofactor.NewService(repo, nil, t.config)
+	ok, err := service.Verify(context.Background(), t.user, code)
+
+	assert.Nil(t.T(), err)
+	assert.True(t.T(), ok)
+}
+
+func (t *TwoFactorServiceTest) TestVerifyNotEnabled() {
+	controller := gomock.NewController(t.T())
+	repo := mock_twofactor.NewMockRepository(controller)
+
+	service := twofactor.NewService(repo, nil, t.config)
+	ok, err := service.Verify(context.Background(), t.user, t.currentCode())
+
+	assert.Nil(t.T(), err)
+	assert.False(t.T(), ok)
+}
@@ -0,0 +1,122 @@
+package twofactor
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/router"
+	"github.com/isd-sgcu/johnjud-backend/internal/validator"
+)
+
+type Handler struct {
+	service  Service
+	validate validator.IDtoValidator
+}
+
+func NewHandler(service Service, validate validator.IDtoValidator) *Handler {
+	return &Handler{service, validate}
+}
+
+// Enroll is a function that creates a new totp secret for the signed in user
+// @Summary enrolls two-factor authentication
+// @Description Returns the secret and the otpauth uri, confirm a code with /auth/2fa/enable to turn it on
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.EnrollTwoFactorResponse
+// @Failure 409 {object} dto.ResponseConflictErr "Two-factor authentication is already enabled"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/2fa/enroll [post]
+func (h *Handler) Enroll(c router.IContext) {
+	response, respErr := h.service.Enroll(c.UserContext(), c.UserID())
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Enable is a function that turns on two-factor authentication with a code from the enrolled secret
+// @Summary enables two-factor authentication
+// @Description Returns the recovery codes, they are only shown once
+// @Param request body dto.EnableTwoFactorRequest true "enable two factor dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.EnableTwoFactorResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid code or not enrolled"
+// @Failure 409 {object} dto.ResponseConflictErr "Two-factor authentication is already enabled"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/2fa/enable [post]
+func (h *Handler) Enable(c router.IContext) {
+	request := &dto.EnableTwoFactorRequest{}
+	if !h.bindAndValidate(c, request) {
+		return
+	}
+
+	response, respErr := h.service.Enable(c.UserContext(), c.UserID(), request)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Disable is a function that turns off two-factor authentication
+// @Summary disables two-factor authentication
+// @Description Returns isSuccess, admins cannot disable it when it is required
+// @Param request body dto.DisableTwoFactorRequest true "disable two factor dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.DisableTwoFactorResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid code or not enabled"
+// @Failure 403 {object} dto.ResponseForbiddenErr "Two-factor authentication is required for admins"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/auth/2fa/disable [post]
+func (h *Handler) Disable(c router.IContext) {
+	request := &dto.DisableTwoFactorRequest{}
+	if !h.bindAndValidate(c, request) {
+		return
+	}
+
+	response, respErr := h.service.Disable(c.UserContext(), c.UserID(), constant.Role(c.Role()), request)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) bindAndValidate(c router.IContext, request interface{}) bool {
+	if err := c.Bind(request); err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.BindingRequestErrorMessage + err.Error(),
+			Data:       nil,
+		})
+		return false
+	}
+
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
+		var errorMessage []string
+		for _, reqErr := range err {
+			errorMessage = append(errorMessage, reqErr.Message)
+		}
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.InvalidRequestBodyMessage + strings.Join(errorMessage, ", "),
+			Data:       nil,
+		})
+		return false
+	}
+
+	return true
+}
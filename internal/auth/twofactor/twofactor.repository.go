@@ -0,0 +1,97 @@
+package twofactor
+
+import (
+	"context"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	UpdateSecret(ctx context.Context, userId string, secret string) error
+	Enable(ctx context.Context, userId string, step int64, codes []*model.RecoveryCode) error
+	Disable(ctx context.Context, userId string) error
+	UpdateLastStep(ctx context.Context, userId string, step int64) error
+	UseRecoveryCode(ctx context.Context, userId string, codeHash string) error
+}
+
+type repositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repositoryImpl{Db: db}
+}
+
+func (r *repositoryImpl) UpdateSecret(ctx context.Context, userId string, secret string) error {
+	return r.Db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+		"totp_secret":    secret,
+		"totp_enabled":   false,
+		"totp_last_step": 0,
+	}).Error
+}
+
+// Enable replaces the recovery codes of the user in the same transaction
+func (r *repositoryImpl) Enable(ctx context.Context, userId string, step int64, codes []*model.RecoveryCode) error {
+	return r.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+			"totp_enabled":   true,
+			"totp_last_step": step,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Delete(&model.RecoveryCode{}, "user_id = ?", userId).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(codes).Error
+	})
+}
+
+func (r *repositoryImpl) Disable(ctx context.Context, userId string) error {
+	return r.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+			"totp_secret":    "",
+			"totp_enabled":   false,
+			"totp_last_step": 0,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&model.RecoveryCode{}, "user_id = ?", userId).Error
+	})
+}
+
+// UpdateLastStep only moves the step forward so a code cannot be used twice, it returns ErrRecordNotFound otherwise
+func (r *repositoryImpl) UpdateLastStep(ctx context.Context, userId string, step int64) error {
+	result := r.Db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", userId, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// UseRecoveryCode returns ErrRecordNotFound when the code does not exist or was already used
+func (r *repositoryImpl) UseRecoveryCode(ctx context.Context, userId string, codeHash string) error {
+	result := r.Db.WithContext(ctx).Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/user"
+	"github.com/isd-sgcu/johnjud-backend/internal/utils"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const recoveryCodeCount = 10
+
+type Service interface {
+	Enroll(ctx context.Context, userId string) (*dto.EnrollTwoFactorResponse, *dto.ResponseErr)
+	Enable(ctx context.Context, userId string, request *dto.EnableTwoFactorRequest) (*dto.EnableTwoFactorResponse, *dto.ResponseErr)
+	Disable(ctx context.Context, userId string, role constant.Role, request *dto.DisableTwoFactorRequest) (*dto.DisableTwoFactorResponse, *dto.ResponseErr)
+	Verify(ctx context.Context, user *model.User, code string) (bool, error)
+}
+
+type serviceImpl struct {
+	repository Repository
+	userRepo   user.Repository
+	config     config.Auth
+}
+
+func NewService(repository Repository, userRepo user.Repository, config config.Auth) Service {
+	return &serviceImpl{repository: repository, userRepo: userRepo, config: config}
+}
+
+// Enroll creates a new secret, 2fa stays off until a code from it is confirmed with Enable
+func (s *serviceImpl) Enroll(ctx context.Context, userId string) (*dto.EnrollTwoFactorResponse, *dto.ResponseErr) {
+	user, apperr := s.findUser(ctx, userId)
+	if apperr != nil {
+		return nil, apperr
+	}
+	if user.TotpEnabled {
+		return nil, dto.ConflictError(constant.TwoFactorAlreadyEnabledErrorMessage)
+	}
+
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	if err := s.repository.UpdateSecret(ctx, userId, secret); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "two factor").
+			Str("module", "enroll").
+			Str("user_id", userId).
+			Msg("Error saving totp secret")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return &dto.EnrollTwoFactorResponse{
+		Secret: secret,
+		URI:    utils.TotpURI(s.config.TotpIssuer, user.Email, secret),
+	}, nil
+}
+
+func (s *serviceImpl) Enable(ctx context.Context, userId string, request *dto.EnableTwoFactorRequest) (*dto.EnableTwoFactorResponse, *dto.ResponseErr) {
+	user, apperr := s.findUser(ctx, userId)
+	if apperr != nil {
+		return nil, apperr
+	}
+	if user.TotpEnabled {
+		return nil, dto.ConflictError(constant.TwoFactorAlreadyEnabledErrorMessage)
+	}
+	if user.TotpSecret == "" {
+		return nil, dto.BadRequestError(constant.TwoFactorNotEnrolledErrorMessage)
+	}
+
+	step, ok := utils.ValidateTotp(user.TotpSecret, request.Code, time.Now())
+	if !ok {
+		return nil, dto.BadRequestError(constant.InvalidTwoFactorCodeErrorMessage)
+	}
+
+	codes, err := GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	var recoveryCodes []*model.RecoveryCode
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, &model.RecoveryCode{UserID: user.ID, CodeHash: HashRecoveryCode(code)})
+	}
+
+	if err := s.repository.Enable(ctx, userId, step, recoveryCodes); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "two factor").
+			Str("module", "enable").
+			Str("user_id", userId).
+			Msg("Error enabling two factor")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return &dto.EnableTwoFactorResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *serviceImpl) Disable(ctx context.Context, userId string, role constant.Role, request *dto.DisableTwoFactorRequest) (*dto.DisableTwoFactorResponse, *dto.ResponseErr) {
+	if role == constant.ADMIN && s.config.RequireAdminTwoFactor {
+		return nil, dto.ForbiddenError(constant.TwoFactorRequiredErrorMessage)
+	}
+
+	user, apperr := s.findUser(ctx, userId)
+	if apperr != nil {
+		return nil, apperr
+	}
+	if !user.TotpEnabled {
+		return nil, dto.BadRequestError(constant.TwoFactorNotEnabledErrorMessage)
+	}
+
+	ok, err := s.Verify(ctx, user, request.Code)
+	if err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+	if !ok {
+		return nil, dto.BadRequestError(constant.InvalidTwoFactorCodeErrorMessage)
+	}
+
+	if err := s.repository.Disable(ctx, userId); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "two factor").
+			Str("module", "disable").
+			Str("user_id", userId).
+			Msg("Error disabling two factor")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return &dto.DisableTwoFactorResponse{IsSuccess: true}, nil
+}
+
+// Verify accepts an unused totp code or an unused recovery code, both are used up on success
+func (s *serviceImpl) Verify(ctx context.Context, user *model.User, code string) (bool, error) {
+	if !user.TotpEnabled {
+		return false, nil
+	}
+
+	if step, ok := utils.ValidateTotp(user.TotpSecret, code, time.Now()); ok {
+		err := s.repository.UpdateLastStep(ctx, user.ID.String(), step)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			log.Error().Ctx(ctx).Err(err).
+				Str("service", "two factor").
+				Str("module", "verify").
+				Str("user_id", user.ID.String()).
+				Msg("Error updating totp step")
+			return false, err
+		}
+		return true, nil
+	}
+
+	err := s.repository.UseRecoveryCode(ctx, user.ID.String(), HashRecoveryCode(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "two factor").
+			Str("module", "verify").
+			Str("user_id", user.ID.String()).
+			Msg("Error using recovery code")
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *serviceImpl) findUser(ctx context.Context, userId string) (*model.User, *dto.ResponseErr) {
+	user := &model.User{}
+	err := s.userRepo.FindById(ctx, userId, user)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.NotFoundError(constant.UserNotFoundErrorMessage)
+		}
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,34 @@
+package twofactor
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateRecoveryCodes returns codes like "abcde-fghij"
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	var codes []string
+	for i := 0; i < count; i++ {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+
+	return codes, nil
+}
+
+// HashRecoveryCode ignores case, spaces and dashes so the code can be typed loosely
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
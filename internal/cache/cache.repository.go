@@ -5,13 +5,17 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/isd-sgcu/johnjud-backend/internal/metrics"
 	"github.com/redis/go-redis/v9"
 )
 
 type Repository interface {
-	SetValue(key string, value interface{}, ttl int) error
-	GetValue(key string, value interface{}) error
-	DeleteValue(key string) error
+	SetValue(ctx context.Context, key string, value interface{}, ttl int) error
+	GetValue(ctx context.Context, key string, value interface{}) error
+	DeleteValue(ctx context.Context, key string) error
+	PopValue(ctx context.Context, key string, value interface{}) error
+	Increment(ctx context.Context, key string, ttl int) (int64, error)
+	GetTTL(ctx context.Context, key string) (time.Duration, error)
 }
 
 type repositoryImpl struct {
@@ -22,8 +26,10 @@ func NewRepository(client *redis.Client) Repository {
 	return &repositoryImpl{client: client}
 }
 
-func (r *repositoryImpl) SetValue(key string, value interface{}, ttl int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *repositoryImpl) SetValue(ctx context.Context, key string, value interface{}, ttl int) (err error) {
+	defer func(start time.Time) { metrics.ObserveRedis("set", start, err) }(time.Now())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	v, err := json.Marshal(value)
@@ -34,8 +40,10 @@ func (r *repositoryImpl) SetValue(key string, value interface{}, ttl int) error
 	return r.client.Set(ctx, key, v, time.Duration(ttl)*time.Second).Err()
 }
 
-func (r *repositoryImpl) GetValue(key string, value interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *repositoryImpl) GetValue(ctx context.Context, key string, value interface{}) (err error) {
+	defer func(start time.Time) { metrics.ObserveRedis("get", start, err) }(time.Now())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	v, err := r.client.Get(ctx, key).Result()
@@ -46,9 +54,64 @@ func (r *repositoryImpl) GetValue(key string, value interface{}) error {
 	return json.Unmarshal([]byte(v), value)
 }
 
-func (r *repositoryImpl) DeleteValue(key string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *repositoryImpl) DeleteValue(ctx context.Context, key string) (err error) {
+	defer func(start time.Time) { metrics.ObserveRedis("del", start, err) }(time.Now())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	return r.client.Del(ctx, key).Err()
 }
+
+// PopValue gets and deletes the value atomically so only one caller can read it
+func (r *repositoryImpl) PopValue(ctx context.Context, key string, value interface{}) (err error) {
+	defer func(start time.Time) { metrics.ObserveRedis("getdel", start, err) }(time.Now())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	v, err := r.client.GetDel(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(v), value)
+}
+
+// incrementScript sets the ttl in the same step as the increment, so a counter never outlives a failed expire.
+// A counter left without a ttl is given one on its next hit
+var incrementScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('EXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// Increment starts the ttl only when the key is created so the counter expires a fixed time after the first hit
+func (r *repositoryImpl) Increment(ctx context.Context, key string, ttl int) (count int64, err error) {
+	defer func(start time.Time) { metrics.ObserveRedis("incr", start, err) }(time.Now())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return incrementScript.Run(ctx, r.client, []string{key}, ttl).Int64()
+}
+
+// GetTTL returns zero when the key does not exist or has no expiry
+func (r *repositoryImpl) GetTTL(ctx context.Context, key string) (ttl time.Duration, err error) {
+	defer func(start time.Time) { metrics.ObserveRedis("ttl", start, err) }(time.Now())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ttl, err = r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/isd-sgcu/johnjud-backend/internal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheRepositoryTest struct {
+	suite.Suite
+	server     *miniredis.Miniredis
+	repository cache.Repository
+}
+
+func TestCacheRepository(t *testing.T) {
+	suite.Run(t, new(CacheRepositoryTest))
+}
+
+func (t *CacheRepositoryTest) SetupTest() {
+	t.server = miniredis.RunT(t.T())
+	client := redis.NewClient(&redis.Options{Addr: t.server.Addr()})
+	t.T().Cleanup(func() { client.Close() })
+	t.repository = cache.NewRepository(client)
+}
+
+func (t *CacheRepositoryTest) TestIncrementSetsTtlOnFirstHit() {
+	count, err := t.repository.Increment(context.Background(), "counter", 60)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), int64(1), count)
+	assert.Equal(t.T(), 60*time.Second, t.server.TTL("counter"))
+}
+
+func (t *CacheRepositoryTest) TestIncrementKeepsTtlOfWindow() {
+	_, err := t.repository.Increment(context.Background(), "counter", 60)
+	assert.Nil(t.T(), err)
+	t.server.FastForward(20 * time.Second)
+
+	count, err := t.repository.Increment(context.Background(), "counter", 60)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), int64(2), count)
+	assert.Equal(t.T(), 40*time.Second, t.server.TTL("counter"))
+}
+
+func (t *CacheRepositoryTest) TestIncrementExpires() {
+	_, err := t.repository.Increment(context.Background(), "counter", 60)
+	assert.Nil(t.T(), err)
+	t.server.FastForward(60 * time.Second)
+
+	count, err := t.repository.Increment(context.Background(), "counter", 60)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), int64(1), count)
+}
+
+func (t *CacheRepositoryTest) TestIncrementRepairsCounterWithoutTtl() {
+	assert.Nil(t.T(), t.server.Set("counter", "5"))
+
+	count, err := t.repository.Increment(context.Background(), "counter", 60)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), int64(6), count)
+	assert.Equal(t.T(), 60*time.Second, t.server.TTL("counter"))
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+)
+
+type ApiKey struct {
+	Id         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Prefix     string                 `json:"prefix"`
+	Scopes     []constant.ApiKeyScope `json:"scopes"`
+	RateLimit  int                    `json:"rate_limit"`
+	CreatedBy  string                 `json:"created_by"`
+	ExpiresAt  *time.Time             `json:"expires_at"`
+	LastUsedAt *time.Time             `json:"last_used_at"`
+	RevokedAt  *time.Time             `json:"revoked_at"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+type CreateApiKeyRequest struct {
+	Name   string                 `json:"name" validate:"required"`
+	Scopes []constant.ApiKeyScope `json:"scopes" validate:"required,min=1,dive,oneof=pets:read pets:write"`
+	// RateLimit is the number of requests per minute, the default is used when it is not set
+	RateLimit int        `json:"rate_limit" validate:"omitempty,gte=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type CreateApiKeyResponse struct {
+	*ApiKey
+	// Key is only shown once
+	Key string `json:"key" example:"jj_..."`
+}
+
+type RevokeApiKeyResponse struct {
+	Success bool `json:"success"`
+}
+
+// ApiKeyPayload is what the guard needs to authorize a request made with an api key
+type ApiKeyPayload struct {
+	Id        string
+	CreatedBy string
+	Scopes    []constant.ApiKeyScope
+	RateLimit int
+}
@@ -1,15 +1,19 @@
 package dto
 
+import "github.com/isd-sgcu/johnjud-backend/constant"
+
 type TokenPayloadAuth struct {
-	UserId string `json:"user_id"`
-	Role   string `json:"role"`
+	UserId        string `json:"user_id"`
+	Role          string `json:"role"`
+	AuthSessionId string `json:"auth_session_id"`
 }
 
 type SignupRequest struct {
-	Email     string `json:"email" validate:"required,email"`
-	Password  string `json:"password" validate:"required,gte=6,lte=30"`
-	Firstname string `json:"firstname" validate:"required"`
-	Lastname  string `json:"lastname" validate:"required"`
+	Email     string          `json:"email" validate:"required,email"`
+	Password  string          `json:"password" validate:"required,password"`
+	Firstname string          `json:"firstname" validate:"required"`
+	Lastname  string          `json:"lastname" validate:"required"`
+	Locale    constant.Locale `json:"locale" validate:"omitempty,oneof=th en"`
 }
 
 type SignupResponse struct {
@@ -20,8 +24,36 @@ type SignupResponse struct {
 }
 
 type SignInRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,gte=6,lte=30"`
+	Email string `json:"email" validate:"required,email"`
+	// the policy is not checked here so passwords set under an older policy still work
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+// SignInResponse holds the credential, or the challenge token when the user has 2fa
+type SignInResponse struct {
+	*Credential
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+	// TwoFactorSetupRequired means the admin was signed in as a user until 2fa is enabled
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+}
+
+type SignInTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	// Code is either a totp code or a recovery code
+	Code string `json:"code" validate:"required"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type MagicLinkResponse struct {
+	IsSuccess bool `json:"is_success"`
+}
+
+type SignInMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
 }
 
 type SignOutResponse struct {
@@ -42,7 +74,7 @@ type ForgotPasswordResponse struct {
 
 type ResetPasswordRequest struct {
 	Token    string `json:"token" validate:"required"`
-	Password string `json:"password" validate:"required,gte=6,lte=30"`
+	Password string `json:"password" validate:"required,password"`
 }
 
 type ResetPasswordResponse struct {
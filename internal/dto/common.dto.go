@@ -1,6 +1,10 @@
 package dto
 
-import "net/http"
+import (
+	"math"
+	"net/http"
+	"time"
+)
 
 type ResponseErr struct {
 	StatusCode int         `json:"status_code"`
@@ -52,6 +56,12 @@ type ResponseConflictErr struct {
 	Data       interface{} `json:"data"`
 }
 
+type ResponseTooManyRequestsErr struct {
+	StatusCode int            `json:"status_code" example:"429"`
+	Message    string         `json:"message" example:"Too many attempts, please try again later"`
+	Data       RetryAfterData `json:"data"`
+}
+
 type ResponseInternalErr struct {
 	StatusCode int         `json:"status_code" example:"500"`
 	Message    string      `json:"message" example:"Internal service error"`
@@ -90,6 +100,21 @@ func ConflictError(message string) *ResponseErr {
 	return &ResponseErr{http.StatusConflict, message, nil}
 }
 
+// RetryAfterData is the data of a 429 response, the handler copies it to the Retry-After header
+type RetryAfterData struct {
+	RetryAfter int `json:"retry_after" example:"30"`
+}
+
+// RequestMeta describes the client of a request for the services that throttle or audit it
+type RequestMeta struct {
+	IP        string
+	UserAgent string
+}
+
+func TooManyRequestsError(message string, retryAfter time.Duration) *ResponseErr {
+	return &ResponseErr{http.StatusTooManyRequests, message, &RetryAfterData{int(math.Ceil(retryAfter.Seconds()))}}
+}
+
 func InternalServerError(message string) *ResponseErr {
 	return &ResponseErr{http.StatusInternalServerError, message, nil}
 }
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+)
+
+type EmailPreviewResponse struct {
+	Template string `json:"template"`
+	Locale   string `json:"locale"`
+	Subject  string `json:"subject"`
+	Text     string `json:"text"`
+	Html     string `json:"html"`
+}
+
+type Email struct {
+	Id            string               `json:"id"`
+	Template      string               `json:"template"`
+	ToName        string               `json:"to_name"`
+	ToAddress     string               `json:"to_address"`
+	Subject       string               `json:"subject"`
+	Status        constant.EmailStatus `json:"status"`
+	Attempts      int                  `json:"attempts"`
+	LastError     string               `json:"last_error"`
+	NextAttemptAt time.Time            `json:"next_attempt_at"`
+	SentAt        *time.Time           `json:"sent_at"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+type FindAllEmailRequest struct {
+	Status   constant.EmailStatus `json:"status"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+}
+
+type FindAllEmailResponse struct {
+	Emails   []*Email         `json:"emails"`
+	Metadata *FindAllMetadata `json:"metadata"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "github.com/isd-sgcu/johnjud-backend/constant"
+
+type LivenessResponse struct {
+	Status constant.HealthStatus `json:"status"`
+}
+
+type ReadinessResponse struct {
+	Status       constant.HealthStatus        `json:"status"`
+	Dependencies map[string]*DependencyHealth `json:"dependencies"`
+}
+
+type DependencyHealth struct {
+	Status    constant.HealthStatus `json:"status"`
+	Required  bool                  `json:"required"`
+	LatencyMs int64                 `json:"latency_ms"`
+}
@@ -22,6 +22,10 @@ type DeleteImageResponse struct {
 	Success bool `json:"success"`
 }
 
+type PurgeOrphanImagesResponse struct {
+	Count int `json:"count"`
+}
+
 type AssignPetRequest struct {
 	Ids   []string `json:"ids" validate:"required"`
 	PetId string   `json:"pet_id" validate:"required"`
@@ -0,0 +1,17 @@
+package dto
+
+type OAuthAuthorizeResponse struct {
+	URL string `json:"url" example:"https://accounts.google.com/o/oauth2/v2/auth?client_id=..."`
+}
+
+type OAuthCallbackRequest struct {
+	State string `json:"state" validate:"required"`
+	Code  string `json:"code" validate:"required"`
+}
+
+// OAuthStateCache is kept until the callback so the state can only be used once
+type OAuthStateCache struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+}
@@ -112,3 +112,35 @@ type DeleteRequest struct {
 type DeleteResponse struct {
 	Success bool `json:"success"`
 }
+
+type ImportPetRequest struct {
+	Filename string
+	Data     []byte
+	DryRun   bool
+}
+
+type ImportPetRowError struct {
+	Row      int      `json:"row"`
+	Messages []string `json:"messages"`
+}
+
+type ImportPetResponse struct {
+	DryRun  bool                 `json:"dry_run"`
+	Total   int                  `json:"total"`
+	Valid   int                  `json:"valid"`
+	Invalid int                  `json:"invalid"`
+	Created int                  `json:"created"`
+	Pets    []*PetResponse       `json:"pets"`
+	Errors  []*ImportPetRowError `json:"errors"`
+}
+
+type ExportPetRequest struct {
+	Format constant.ExportFormat
+	Query  *FindAllPetRequest
+}
+
+type ExportPetResponse struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
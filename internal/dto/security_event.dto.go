@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+)
+
+type SecurityEvent struct {
+	Id        string                     `json:"id"`
+	UserId    string                     `json:"user_id"`
+	Email     string                     `json:"email"`
+	Type      constant.SecurityEventType `json:"type"`
+	IP        string                     `json:"ip"`
+	UserAgent string                     `json:"user_agent"`
+	Success   bool                       `json:"success"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+type FindAllSecurityEventRequest struct {
+	UserId   string                     `json:"user_id"`
+	Type     constant.SecurityEventType `json:"type"`
+	Success  *bool                      `json:"success"`
+	Page     int                        `json:"page"`
+	PageSize int                        `json:"page_size"`
+}
+
+type FindAllSecurityEventResponse struct {
+	Events   []*SecurityEvent `json:"events"`
+	Metadata *FindAllMetadata `json:"metadata"`
+}
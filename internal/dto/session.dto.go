@@ -0,0 +1,8 @@
+package dto
+
+import "time"
+
+type Session struct {
+	Id        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
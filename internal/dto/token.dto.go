@@ -33,3 +33,16 @@ type RefreshTokenCache struct {
 type ResetPasswordTokenCache struct {
 	UserID string `json:"user_id"`
 }
+
+type VerifyEmailTokenCache struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+type TwoFactorChallengeCache struct {
+	UserID string `json:"user_id"`
+}
+
+type MagicLinkTokenCache struct {
+	UserID string `json:"user_id"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+type EnrollTwoFactorResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri" example:"otpauth://totp/Johnjud:user@example.com?secret=..."`
+}
+
+type EnableTwoFactorRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type EnableTwoFactorResponse struct {
+	// RecoveryCodes are only shown once
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type DisableTwoFactorRequest struct {
+	// Code is either a totp code or a recovery code
+	Code string `json:"code" validate:"required"`
+}
+
+type DisableTwoFactorResponse struct {
+	IsSuccess bool `json:"is_success"`
+}
@@ -1,19 +1,87 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+)
+
 type User struct {
-	Id        string `json:"id"`
-	Email     string `json:"email"`
-	Firstname string `json:"firstname"`
-	Lastname  string `json:"lastname"`
+	Id        string          `json:"id"`
+	Email     string          `json:"email"`
+	Firstname string          `json:"firstname"`
+	Lastname  string          `json:"lastname"`
+	Locale    constant.Locale `json:"locale"`
 }
 
 type UpdateUserRequest struct {
-	Email     string `json:"email" validate:"required,email"`
-	Password  string `json:"password" validate:"required,gte=6,lte=30"`
-	Firstname string `json:"firstname" validate:"required"`
-	Lastname  string `json:"lastname" validate:"required"`
+	Email     *string          `json:"email" validate:"omitnil,email"`
+	Firstname *string          `json:"firstname" validate:"omitnil,min=1"`
+	Lastname  *string          `json:"lastname" validate:"omitnil,min=1"`
+	Locale    *constant.Locale `json:"locale" validate:"omitnil,oneof=th en"`
+}
+
+type UpdateUserResponse struct {
+	User
+	PendingEmail string `json:"pending_email,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,password"`
+}
+
+type ChangePasswordResponse struct {
+	IsSuccess bool `json:"is_success"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
 }
 
 type DeleteUserResponse struct {
 	Success bool `json:"success"`
 }
+
+type AdminUser struct {
+	Id          string        `json:"id"`
+	Email       string        `json:"email"`
+	Firstname   string        `json:"firstname"`
+	Lastname    string        `json:"lastname"`
+	Role        constant.Role `json:"role"`
+	IsSuspended bool          `json:"is_suspended"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type FindAllUserRequest struct {
+	Search   string `json:"search"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+type FindAllUserResponse struct {
+	Users    []*AdminUser     `json:"users"`
+	Metadata *FindAllMetadata `json:"metadata"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role constant.Role `json:"role" validate:"required,oneof=user admin"`
+}
+
+type SuspendUserRequest struct {
+	IsSuspended *bool `json:"is_suspended" validate:"required"`
+}
+
+// DeleteAccountRequest needs the password unless the user signed in within the reauth ttl, users of oauth and magic links have no password
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type UserDataExport struct {
+	ExportedAt     time.Time        `json:"exported_at"`
+	Profile        *AdminUser       `json:"profile"`
+	Sessions       []*Session       `json:"sessions"`
+	Likes          []*LikeResponse  `json:"likes"`
+	Adoptions      []*PetResponse   `json:"adoptions"`
+	SecurityEvents []*SecurityEvent `json:"security_events"`
+}
@@ -0,0 +1,50 @@
+package healthcheck
+
+import (
+	"context"
+
+	"github.com/isd-sgcu/johnjud-backend/client/bucket"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Dependency is a service the backend talks to, the server is not ready when a required one is down
+type Dependency struct {
+	Name     string
+	Required bool
+	Ping     func(ctx context.Context) error
+}
+
+func NewDatabaseDependency(db *gorm.DB) Dependency {
+	return Dependency{
+		Name:     constant.DatabaseDependency,
+		Required: true,
+		Ping: func(ctx context.Context) error {
+			sqlDb, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDb.PingContext(ctx)
+		},
+	}
+}
+
+func NewRedisDependency(client *redis.Client) Dependency {
+	return Dependency{
+		Name:     constant.RedisDependency,
+		Required: true,
+		Ping: func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		},
+	}
+}
+
+// NewBucketDependency is not required, only uploads and deletes of images fail while the bucket is down
+func NewBucketDependency(client bucket.Client) Dependency {
+	return Dependency{
+		Name:     constant.BucketDependency,
+		Required: false,
+		Ping:     client.Ping,
+	}
+}
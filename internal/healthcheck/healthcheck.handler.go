@@ -3,19 +3,51 @@ package healthcheck
 import (
 	"net/http"
 
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
 	"github.com/isd-sgcu/johnjud-backend/internal/router"
 )
 
 type Handler struct {
+	service Service
 }
 
-func NewHandler() *Handler {
-	return &Handler{}
+func NewHandler(service Service) *Handler {
+	return &Handler{service}
 }
 
-func (h *Handler) HealthCheck(c *router.FiberCtx) {
+func (h *Handler) HealthCheck(c router.IContext) {
 	c.JSON(http.StatusOK, map[string]interface{}{
 		"Health": "ok",
 	})
 	return
 }
+
+// Liveness is a function that reports that the process is up, it does not check the dependencies
+// @Summary liveness check
+// @Description Always returns 200 while the server is able to handle requests
+// @Tags healthcheck
+// @Produce json
+// @Success 200 {object} dto.LivenessResponse
+// @Router /v1/healthz [get]
+func (h *Handler) Liveness(c router.IContext) {
+	c.JSON(http.StatusOK, &dto.LivenessResponse{Status: constant.HealthStatusUp})
+}
+
+// Readiness is a function that checks the database, redis and the bucket
+// @Summary readiness check
+// @Description Pings the dependencies in parallel and returns the status and latency of each one
+// @Tags healthcheck
+// @Produce json
+// @Success 200 {object} dto.ReadinessResponse
+// @Failure 503 {object} dto.ReadinessResponse "A required dependency is down"
+// @Router /v1/readyz [get]
+func (h *Handler) Readiness(c router.IContext) {
+	res, ready := h.service.Readiness(c.UserContext())
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, res)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
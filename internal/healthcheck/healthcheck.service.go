@@ -0,0 +1,104 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/rs/zerolog/log"
+)
+
+type Service interface {
+	Readiness(ctx context.Context) (*dto.ReadinessResponse, bool)
+}
+
+type serviceImpl struct {
+	conf         config.HealthCheck
+	dependencies []Dependency
+}
+
+func NewService(conf config.HealthCheck, dependencies ...Dependency) Service {
+	return &serviceImpl{conf: conf, dependencies: dependencies}
+}
+
+// Readiness pings every dependency in parallel, each with its own timeout. It reports false when a required
+// dependency is down, the errors are only logged so that hosts and credentials are not sent to the caller
+func (s *serviceImpl) Readiness(ctx context.Context) (*dto.ReadinessResponse, bool) {
+	timeout := time.Duration(s.conf.Timeout) * time.Millisecond
+	results := make([]*dto.DependencyHealth, len(s.dependencies))
+
+	var wg sync.WaitGroup
+	for i, dependency := range s.dependencies {
+		wg.Add(1)
+		go func(i int, dependency Dependency) {
+			defer wg.Done()
+			results[i] = check(ctx, dependency, timeout)
+		}(i, dependency)
+	}
+	wg.Wait()
+
+	ready := true
+	res := &dto.ReadinessResponse{
+		Status:       constant.HealthStatusUp,
+		Dependencies: make(map[string]*dto.DependencyHealth, len(results)),
+	}
+	for i, result := range results {
+		res.Dependencies[s.dependencies[i].Name] = result
+		if result.Required && result.Status != constant.HealthStatusUp {
+			ready = false
+			res.Status = constant.HealthStatusDown
+		}
+	}
+
+	return res, ready
+}
+
+func check(ctx context.Context, dependency Dependency, timeout time.Duration) *dto.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ping(ctx, dependency)
+	result := &dto.DependencyHealth{
+		Status:    constant.HealthStatusUp,
+		Required:  dependency.Required,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err == nil {
+		return result
+	}
+
+	result.Status = constant.HealthStatusDown
+	if errors.Is(err, context.DeadlineExceeded) {
+		result.Status = constant.HealthStatusTimeout
+	}
+
+	log.Error().
+		Ctx(ctx).
+		Err(err).
+		Str("service", "healthcheck").
+		Str("module", "readiness").
+		Str("dependency", dependency.Name).
+		Msg("Dependency is not healthy")
+
+	return result
+}
+
+// ping returns when the timeout is reached even if the client does not watch the context
+func ping(ctx context.Context, dependency Dependency) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- dependency.Ping(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	ctx "context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/healthcheck"
+	routerMock "github.com/isd-sgcu/johnjud-backend/mocks/router"
+	healthCheckMock "github.com/isd-sgcu/johnjud-backend/mocks/service/healthcheck"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthCheckHandlerTest struct {
+	suite.Suite
+	readiness *dto.ReadinessResponse
+}
+
+func TestHealthCheckHandler(t *testing.T) {
+	suite.Run(t, new(HealthCheckHandlerTest))
+}
+
+func (t *HealthCheckHandlerTest) SetupTest() {
+	t.readiness = &dto.ReadinessResponse{
+		Status: constant.HealthStatusUp,
+		Dependencies: map[string]*dto.DependencyHealth{
+			constant.DatabaseDependency: {Status: constant.HealthStatusUp, Required: true, LatencyMs: 1},
+		},
+	}
+}
+
+func (t *HealthCheckHandlerTest) TestLiveness() {
+	controller := gomock.NewController(t.T())
+	service := healthCheckMock.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().JSON(http.StatusOK, &dto.LivenessResponse{Status: constant.HealthStatusUp})
+
+	handler := healthcheck.NewHandler(service)
+	handler.Liveness(context)
+}
+
+func (t *HealthCheckHandlerTest) TestReadinessReady() {
+	controller := gomock.NewController(t.T())
+	service := healthCheckMock.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().UserContext().Return(ctx.Background())
+	service.EXPECT().Readiness(gomock.Any()).Return(t.readiness, true)
+	context.EXPECT().JSON(http.StatusOK, t.readiness)
+
+	handler := healthcheck.NewHandler(service)
+	handler.Readiness(context)
+}
+
+func (t *HealthCheckHandlerTest) TestReadinessNotReady() {
+	controller := gomock.NewController(t.T())
+	service := healthCheckMock.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	t.readiness.Status = constant.HealthStatusDown
+	t.readiness.Dependencies[constant.DatabaseDependency].Status = constant.HealthStatusTimeout
+	context.EXPECT().UserContext().Return(ctx.Background())
+	service.EXPECT().Readiness(gomock.Any()).Return(t.readiness, false)
+	context.EXPECT().JSON(http.StatusServiceUnavailable, t.readiness)
+
+	handler := healthcheck.NewHandler(service)
+	handler.Readiness(context)
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthCheckServiceTest struct {
+	suite.Suite
+	config config.HealthCheck
+}
+
+func TestHealthCheckService(t *testing.T) {
+	suite.Run(t, new(HealthCheckServiceTest))
+}
+
+func (t *HealthCheckServiceTest) SetupTest() {
+	t.config = config.HealthCheck{Timeout: 50}
+}
+
+func up(name string, required bool) healthcheck.Dependency {
+	return healthcheck.Dependency{Name: name, Required: required, Ping: func(ctx context.Context) error {
+		return nil
+	}}
+}
+
+func down(name string, required bool) healthcheck.Dependency {
+	return healthcheck.Dependency{Name: name, Required: required, Ping: func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}}
+}
+
+// hanging ignores the context like a client without timeouts would
+func hanging(name string, required bool) healthcheck.Dependency {
+	return healthcheck.Dependency{Name: name, Required: required, Ping: func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}}
+}
+
+func (t *HealthCheckServiceTest) TestReadinessAllUp() {
+	service := healthcheck.NewService(t.config,
+		up(constant.DatabaseDependency, true),
+		up(constant.RedisDependency, true),
+		up(constant.BucketDependency, false),
+	)
+
+	actual, ready := service.Readiness(context.Background())
+
+	assert.True(t.T(), ready)
+	assert.Equal(t.T(), constant.HealthStatusUp, actual.Status)
+	assert.Len(t.T(), actual.Dependencies, 3)
+	for _, dependency := range actual.Dependencies {
+		assert.Equal(t.T(), constant.HealthStatusUp, dependency.Status)
+	}
+	assert.True(t.T(), actual.Dependencies[constant.DatabaseDependency].Required)
+	assert.False(t.T(), actual.Dependencies[constant.BucketDependency].Required)
+}
+
+func (t *HealthCheckServiceTest) TestReadinessRequiredDown() {
+	service := healthcheck.NewService(t.config,
+		down(constant.DatabaseDependency, true),
+		up(constant.RedisDependency, true),
+	)
+
+	actual, ready := service.Readiness(context.Background())
+
+	assert.False(t.T(), ready)
+	assert.Equal(t.T(), constant.HealthStatusDown, actual.Status)
+	assert.Equal(t.T(), constant.HealthStatusDown, actual.Dependencies[constant.DatabaseDependency].Status)
+	assert.Equal(t.T(), constant.HealthStatusUp, actual.Dependencies[constant.RedisDependency].Status)
+}
+
+func (t *HealthCheckServiceTest) TestReadinessOptionalDown() {
+	service := healthcheck.NewService(t.config,
+		up(constant.DatabaseDependency, true),
+		down(constant.BucketDependency, false),
+	)
+
+	actual, ready := service.Readiness(context.Background())
+
+	assert.True(t.T(), ready)
+	assert.Equal(t.T(), constant.HealthStatusUp, actual.Status)
+	assert.Equal(t.T(), constant.HealthStatusDown, actual.Dependencies[constant.BucketDependency].Status)
+}
+
+func (t *HealthCheckServiceTest) TestReadinessTimeoutInParallel() {
+	service := healthcheck.NewService(t.config,
+		hanging(constant.DatabaseDependency, true),
+		hanging(constant.RedisDependency, true),
+	)
+
+	start := time.Now()
+	actual, ready := service.Readiness(context.Background())
+
+	assert.False(t.T(), ready)
+	assert.Less(t.T(), time.Since(start), 500*time.Millisecond)
+	assert.Equal(t.T(), constant.HealthStatusTimeout, actual.Dependencies[constant.DatabaseDependency].Status)
+	assert.Equal(t.T(), constant.HealthStatusTimeout, actual.Dependencies[constant.RedisDependency].Status)
+}
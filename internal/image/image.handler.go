@@ -41,6 +41,7 @@ func (h *handlerImpl) Upload(c *router.FiberCtx) {
 	file, err := c.File("file", constant.AllowContentType, h.maxFileSize)
 	if err != nil {
 		log.Error().
+			Ctx(c.UserContext()).
 			Err(err).
 			Str("service", "image").
 			Str("module", "upload").
@@ -59,7 +60,7 @@ func (h *handlerImpl) Upload(c *router.FiberCtx) {
 		PetId:    petId,
 	}
 
-	response, respErr := h.service.Upload(request)
+	response, respErr := h.service.Upload(c.UserContext(), request)
 	if respErr != nil {
 		c.JSON(respErr.StatusCode, respErr)
 		return
@@ -90,7 +91,7 @@ func (h *handlerImpl) Delete(c *router.FiberCtx) {
 		return
 	}
 
-	res, errRes := h.service.Delete(id)
+	res, errRes := h.service.Delete(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -1,18 +1,22 @@
 package image
 
 import (
+	"context"
+	"time"
+
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
 	"gorm.io/gorm"
 )
 
 type Repository interface {
-	FindAll(*[]*model.Image) error
-	FindOne(string, *model.Image) error
-	FindByPetId(string, *[]*model.Image) error
-	Create(*model.Image) error
-	Update(string, *model.Image) error
-	Delete(string) error
-	DeleteMany([]string) error
+	FindAll(context.Context, *[]*model.Image) error
+	FindOne(context.Context, string, *model.Image) error
+	FindByPetId(context.Context, string, *[]*model.Image) error
+	Create(context.Context, *model.Image) error
+	Update(context.Context, string, *model.Image) error
+	Delete(context.Context, string) error
+	DeleteMany(context.Context, []string) error
+	FindOrphans(context.Context, time.Time, *[]*model.Image) error
 }
 
 type repositoryImpl struct {
@@ -23,32 +27,41 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repositoryImpl{db: db}
 }
 
-func (r *repositoryImpl) FindAll(result *[]*model.Image) error {
-	return r.db.Model(&model.Image{}).Find(result).Error
+func (r *repositoryImpl) FindAll(ctx context.Context, result *[]*model.Image) error {
+	return r.db.WithContext(ctx).Model(&model.Image{}).Find(result).Error
 }
 
-func (r *repositoryImpl) FindOne(id string, result *model.Image) error {
-	return r.db.Model(&model.Image{}).First(result, "id = ?", id).Error
+func (r *repositoryImpl) FindOne(ctx context.Context, id string, result *model.Image) error {
+	return r.db.WithContext(ctx).Model(&model.Image{}).First(result, "id = ?", id).Error
 }
 
-func (r *repositoryImpl) FindByPetId(id string, result *[]*model.Image) error {
-	return r.db.Model(&model.Image{}).Find(&result, "pet_id = ?", id).Error
+func (r *repositoryImpl) FindByPetId(ctx context.Context, id string, result *[]*model.Image) error {
+	return r.db.WithContext(ctx).Model(&model.Image{}).Find(&result, "pet_id = ?", id).Error
 }
 
-func (r *repositoryImpl) Create(in *model.Image) error {
-	return r.db.Create(&in).Error
+func (r *repositoryImpl) Create(ctx context.Context, in *model.Image) error {
+	return r.db.WithContext(ctx).Create(&in).Error
 }
 
-func (r *repositoryImpl) Update(id string, in *model.Image) error {
-	return r.db.Where(id, "id = ?", id).Updates(&in).First(&in, "id = ?", id).Error
+func (r *repositoryImpl) Update(ctx context.Context, id string, in *model.Image) error {
+	return r.db.WithContext(ctx).Where(id, "id = ?", id).Updates(&in).First(&in, "id = ?", id).Error
 }
 
-func (r *repositoryImpl) Delete(id string) error {
-	return r.db.Where("id = ?", id).Delete(&model.Image{}).Error
+func (r *repositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Image{}).Error
 }
-func (r *repositoryImpl) DeleteMany(ids []string) error {
+func (r *repositoryImpl) DeleteMany(ctx context.Context, ids []string) error {
 	if len(ids) == 0 {
 		return nil
 	}
-	return r.db.Delete(&model.Image{}, ids).Error
+	return r.db.WithContext(ctx).Delete(&model.Image{}, ids).Error
+}
+
+// FindOrphans finds the images created before the time that are not assigned to a pet or whose pet was deleted
+func (r *repositoryImpl) FindOrphans(ctx context.Context, before time.Time, result *[]*model.Image) error {
+	return r.db.WithContext(ctx).Model(&model.Image{}).
+		Joins("LEFT JOIN pets ON pets.id = images.pet_id").
+		Where("images.created_at < ?", before).
+		Where("images.pet_id IS NULL OR pets.id IS NULL OR pets.deleted_at IS NOT NULL").
+		Find(result).Error
 }
@@ -1,6 +1,7 @@
 package image
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -9,18 +10,21 @@ import (
 	"github.com/isd-sgcu/johnjud-backend/constant"
 	"github.com/isd-sgcu/johnjud-backend/internal/dto"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/tracing"
 	"github.com/isd-sgcu/johnjud-backend/internal/utils"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
 type Service interface {
-	FindAll() ([]*dto.ImageResponse, *dto.ResponseErr)
-	FindByPetId(petID string) ([]*dto.ImageResponse, *dto.ResponseErr)
-	Upload(request *dto.UploadImageRequest) (*dto.ImageResponse, *dto.ResponseErr)
-	Delete(id string) (*dto.DeleteImageResponse, *dto.ResponseErr)
-	DeleteByPetId(petID string) (*dto.DeleteImageResponse, *dto.ResponseErr)
-	AssignPet(request *dto.AssignPetRequest) (*dto.AssignPetResponse, *dto.ResponseErr)
+	FindAll(ctx context.Context) ([]*dto.ImageResponse, *dto.ResponseErr)
+	FindByPetId(ctx context.Context, petID string) ([]*dto.ImageResponse, *dto.ResponseErr)
+	Upload(ctx context.Context, request *dto.UploadImageRequest) (*dto.ImageResponse, *dto.ResponseErr)
+	Delete(ctx context.Context, id string) (*dto.DeleteImageResponse, *dto.ResponseErr)
+	DeleteByPetId(ctx context.Context, petID string) (*dto.DeleteImageResponse, *dto.ResponseErr)
+	AssignPet(ctx context.Context, request *dto.AssignPetRequest) (*dto.AssignPetResponse, *dto.ResponseErr)
+	Download(ctx context.Context, objectKey string) ([]byte, *dto.ResponseErr)
+	PurgeOrphans(ctx context.Context, before time.Time) (*dto.PurgeOrphanImagesResponse, *dto.ResponseErr)
 }
 
 type serviceImpl struct {
@@ -37,12 +41,15 @@ func NewService(client bucket.Client, repository Repository, random utils.Random
 	}
 }
 
-func (s *serviceImpl) FindAll() ([]*dto.ImageResponse, *dto.ResponseErr) {
+func (s *serviceImpl) FindAll(ctx context.Context) ([]*dto.ImageResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "image.FindAll")
+	defer span.End()
+
 	var images []*model.Image
 
-	err := s.repository.FindAll(&images)
+	err := s.repository.FindAll(ctx, &images)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "find all").
 			Msg("Error finding all images")
@@ -53,12 +60,15 @@ func (s *serviceImpl) FindAll() ([]*dto.ImageResponse, *dto.ResponseErr) {
 	return RawToDtoList(&images), nil
 }
 
-func (s *serviceImpl) FindByPetId(petID string) ([]*dto.ImageResponse, *dto.ResponseErr) {
+func (s *serviceImpl) FindByPetId(ctx context.Context, petID string) ([]*dto.ImageResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "image.FindByPetId")
+	defer span.End()
+
 	var images []*model.Image
 
-	err := s.repository.FindByPetId(petID, &images)
+	err := s.repository.FindByPetId(ctx, petID, &images)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "find by petId").
 			Str("petId", petID).
@@ -73,11 +83,14 @@ func (s *serviceImpl) FindByPetId(petID string) ([]*dto.ImageResponse, *dto.Resp
 	return RawToDtoList(&images), nil
 }
 
-func (s *serviceImpl) Upload(req *dto.UploadImageRequest) (*dto.ImageResponse, *dto.ResponseErr) {
+func (s *serviceImpl) Upload(ctx context.Context, req *dto.UploadImageRequest) (*dto.ImageResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "image.Upload")
+	defer span.End()
+
 	if req.PetId != "" {
 		_, err := uuid.Parse(req.PetId)
 		if err != nil {
-			log.Error().Err(err).
+			log.Error().Ctx(ctx).Err(err).
 				Str("service", "image").
 				Str("module", "upload").
 				Str("petId", req.PetId).
@@ -89,7 +102,7 @@ func (s *serviceImpl) Upload(req *dto.UploadImageRequest) (*dto.ImageResponse, *
 
 	randomString, err := s.random.GenerateRandomString(10)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "upload").
 			Str("petId", req.PetId).
@@ -97,9 +110,9 @@ func (s *serviceImpl) Upload(req *dto.UploadImageRequest) (*dto.ImageResponse, *
 		return nil, dto.InternalServerError("Error while generating random string")
 	}
 
-	imageUrl, objectKey, err := s.client.Upload(req.File, randomString+"_"+req.Filename)
+	imageUrl, objectKey, err := s.client.Upload(ctx, req.File, randomString+"_"+req.Filename)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "upload").
 			Str("petId", req.PetId).
@@ -115,9 +128,9 @@ func (s *serviceImpl) Upload(req *dto.UploadImageRequest) (*dto.ImageResponse, *
 		ObjectKey: objectKey,
 	})
 
-	err = s.repository.Create(raw)
+	err = s.repository.Create(ctx, raw)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "upload").
 			Str("petId", req.PetId).
@@ -129,10 +142,13 @@ func (s *serviceImpl) Upload(req *dto.UploadImageRequest) (*dto.ImageResponse, *
 	return RawToDto(raw), nil
 }
 
-func (s *serviceImpl) AssignPet(req *dto.AssignPetRequest) (*dto.AssignPetResponse, *dto.ResponseErr) {
+func (s *serviceImpl) AssignPet(ctx context.Context, req *dto.AssignPetRequest) (*dto.AssignPetResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "image.AssignPet")
+	defer span.End()
+
 	petId, err := uuid.Parse(req.PetId)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "assign pet").
 			Str("petId", req.PetId).
@@ -142,14 +158,14 @@ func (s *serviceImpl) AssignPet(req *dto.AssignPetRequest) (*dto.AssignPetRespon
 	}
 
 	for _, id := range req.Ids {
-		err = s.repository.Update(id, &model.Image{
+		err = s.repository.Update(ctx, id, &model.Image{
 			PetID: &petId,
 		})
 		if err == nil {
 			continue
 		}
 
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "assign pet").
 			Str("petId", req.PetId).
@@ -169,12 +185,15 @@ func (s *serviceImpl) AssignPet(req *dto.AssignPetRequest) (*dto.AssignPetRespon
 	return &dto.AssignPetResponse{Success: true}, nil
 }
 
-func (s *serviceImpl) Delete(id string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
+func (s *serviceImpl) Delete(ctx context.Context, id string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "image.Delete")
+	defer span.End()
+
 	var image model.Image
 
-	err := s.repository.FindOne(id, &image)
+	err := s.repository.FindOne(ctx, id, &image)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "delete").
 			Str("id", id).
@@ -186,9 +205,9 @@ func (s *serviceImpl) Delete(id string) (*dto.DeleteImageResponse, *dto.Response
 		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
-	err = s.client.Delete(image.ObjectKey)
+	err = s.client.Delete(ctx, image.ObjectKey)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "delete").
 			Str("id", id).
@@ -197,9 +216,9 @@ func (s *serviceImpl) Delete(id string) (*dto.DeleteImageResponse, *dto.Response
 		return nil, dto.InternalServerError(constant.DeleteFromBucketErrorMessage)
 	}
 
-	err = s.repository.Delete(id)
+	err = s.repository.Delete(ctx, id)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "delete").
 			Str("id", id).
@@ -211,12 +230,15 @@ func (s *serviceImpl) Delete(id string) (*dto.DeleteImageResponse, *dto.Response
 	return &dto.DeleteImageResponse{Success: true}, nil
 }
 
-func (s *serviceImpl) DeleteByPetId(petID string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
+func (s *serviceImpl) DeleteByPetId(ctx context.Context, petID string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "image.DeleteByPetId")
+	defer span.End()
+
 	var images []*model.Image
 
-	err := s.repository.FindByPetId(petID, &images)
+	err := s.repository.FindByPetId(ctx, petID, &images)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "delete by pet id").
 			Str("pet id", petID).
@@ -229,9 +251,9 @@ func (s *serviceImpl) DeleteByPetId(petID string) (*dto.DeleteImageResponse, *dt
 	}
 
 	imageObjectKeys := ExtractImageObjectKeys(images)
-	err = s.client.DeleteMany(imageObjectKeys)
+	err = s.client.DeleteMany(ctx, imageObjectKeys)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "delete by pet id").
 			Interface("image object keys", imageObjectKeys).
@@ -241,9 +263,9 @@ func (s *serviceImpl) DeleteByPetId(petID string) (*dto.DeleteImageResponse, *dt
 	}
 
 	imageIds := ExtractImageIds(images)
-	err = s.repository.DeleteMany(imageIds)
+	err = s.repository.DeleteMany(ctx, imageIds)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "image").
 			Str("module", "delete by pet id").
 			Interface("image ids", imageIds).
@@ -255,6 +277,24 @@ func (s *serviceImpl) DeleteByPetId(petID string) (*dto.DeleteImageResponse, *dt
 	return &dto.DeleteImageResponse{Success: true}, nil
 }
 
+func (s *serviceImpl) Download(ctx context.Context, objectKey string) ([]byte, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "image.Download")
+	defer span.End()
+
+	data, err := s.client.Download(ctx, objectKey)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "image").
+			Str("module", "download").
+			Str("objectKey", objectKey).
+			Msg(constant.DownloadFromBucketErrorMessage)
+
+		return nil, dto.InternalServerError(constant.DownloadFromBucketErrorMessage)
+	}
+
+	return data, nil
+}
+
 func DtoToRaw(in *dto.ImageResponse) (result *model.Image, err error) {
 	var id uuid.UUID
 	if in.Id != "" {
@@ -336,3 +376,51 @@ func ExtractImageObjectKeys(in []*model.Image) []string {
 
 	return imageObjectKeys
 }
+
+// PurgeOrphans deletes the images uploaded before the time that never got a pet or whose pet was deleted,
+// the uploads of a pet that is still being created are kept by choosing a time far enough in the past
+func (s *serviceImpl) PurgeOrphans(ctx context.Context, before time.Time) (*dto.PurgeOrphanImagesResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "image.PurgeOrphans")
+	defer span.End()
+
+	var images []*model.Image
+
+	err := s.repository.FindOrphans(ctx, before, &images)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "image").
+			Str("module", "purge orphans").
+			Msg("Error finding orphan images from repo")
+
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+	if len(images) == 0 {
+		return &dto.PurgeOrphanImagesResponse{Count: 0}, nil
+	}
+
+	imageObjectKeys := ExtractImageObjectKeys(images)
+	err = s.client.DeleteMany(ctx, imageObjectKeys)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "image").
+			Str("module", "purge orphans").
+			Interface("image object keys", imageObjectKeys).
+			Msg(constant.DeleteFromBucketErrorMessage)
+
+		return nil, dto.InternalServerError(constant.DeleteFromBucketErrorMessage)
+	}
+
+	imageIds := ExtractImageIds(images)
+	err = s.repository.DeleteMany(ctx, imageIds)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "image").
+			Str("module", "purge orphans").
+			Interface("image ids", imageIds).
+			Msg(constant.DeleteImageErrorMessage)
+
+		return nil, dto.InternalServerError(constant.DeleteImageErrorMessage)
+	}
+
+	return &dto.PurgeOrphanImagesResponse{Count: len(images)}, nil
+}
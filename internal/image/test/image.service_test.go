@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/image"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	mock_bucket "github.com/isd-sgcu/johnjud-backend/mocks/client/bucket"
+	mock "github.com/isd-sgcu/johnjud-backend/mocks/repository/image"
+	"github.com/isd-sgcu/johnjud-backend/mocks/utils"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImageServiceTest struct {
+	suite.Suite
+	before  time.Time
+	orphans []*model.Image
+}
+
+func TestImageService(t *testing.T) {
+	suite.Run(t, new(ImageServiceTest))
+}
+
+func (t *ImageServiceTest) SetupTest() {
+	t.before = time.Now().Add(-24 * time.Hour)
+
+	// the repository returns the images created before the time without a pet, or whose pet was deleted
+	t.orphans = []*model.Image{
+		{
+			Base:      model.Base{ID: uuid.New(), CreatedAt: t.before.Add(-time.Hour)},
+			ObjectKey: "orphan-1.jpg",
+		},
+		{
+			Base:      model.Base{ID: uuid.New(), CreatedAt: t.before.Add(-48 * time.Hour)},
+			ObjectKey: "orphan-2.jpg",
+		},
+	}
+}
+
+func (t *ImageServiceTest) TestPurgeOrphansSuccess() {
+	controller := gomock.NewController(t.T())
+	client := mock_bucket.NewMockClient(controller)
+	repo := &mock.ImageRepositoryMock{}
+
+	repo.On("FindOrphans", testifyMock.Anything, t.before, testifyMock.Anything).Return(&t.orphans, nil)
+	client.EXPECT().DeleteMany(gomock.Any(), []string{"orphan-1.jpg", "orphan-2.jpg"}).Return(nil)
+	repo.On("DeleteMany", testifyMock.Anything, []string{t.orphans[0].ID.String(), t.orphans[1].ID.String()}).Return(nil)
+
+	srv := image.NewService(client, repo, &utils.RandomUtilMock{})
+	actual, err := srv.PurgeOrphans(context.Background(), t.before)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.PurgeOrphanImagesResponse{Count: 2}, actual)
+	repo.AssertExpectations(t.T())
+}
+
+func (t *ImageServiceTest) TestPurgeOrphansNone() {
+	controller := gomock.NewController(t.T())
+	client := mock_bucket.NewMockClient(controller)
+	repo := &mock.ImageRepositoryMock{}
+
+	repo.On("FindOrphans", testifyMock.Anything, t.before, testifyMock.Anything).Return(&[]*model.Image{}, nil)
+
+	srv := image.NewService(client, repo, &utils.RandomUtilMock{})
+	actual, err := srv.PurgeOrphans(context.Background(), t.before)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.PurgeOrphanImagesResponse{Count: 0}, actual)
+	repo.AssertNotCalled(t.T(), "DeleteMany", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (t *ImageServiceTest) TestPurgeOrphansFindFailed() {
+	controller := gomock.NewController(t.T())
+	client := mock_bucket.NewMockClient(controller)
+	repo := &mock.ImageRepositoryMock{}
+
+	repo.On("FindOrphans", testifyMock.Anything, t.before, testifyMock.Anything).Return(nil, errors.New("something wrong"))
+
+	srv := image.NewService(client, repo, &utils.RandomUtilMock{})
+	actual, err := srv.PurgeOrphans(context.Background(), t.before)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
+}
+
+// the rows are kept when the objects could not be deleted so the next purge tries them again
+func (t *ImageServiceTest) TestPurgeOrphansBucketFailedKeepsRows() {
+	controller := gomock.NewController(t.T())
+	client := mock_bucket.NewMockClient(controller)
+	repo := &mock.ImageRepositoryMock{}
+
+	repo.On("FindOrphans", testifyMock.Anything, t.before, testifyMock.Anything).Return(&t.orphans, nil)
+	client.EXPECT().DeleteMany(gomock.Any(), []string{"orphan-1.jpg", "orphan-2.jpg"}).Return(errors.New("something wrong"))
+
+	srv := image.NewService(client, repo, &utils.RandomUtilMock{})
+	actual, err := srv.PurgeOrphans(context.Background(), t.before)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
+	repo.AssertNotCalled(t.T(), "DeleteMany", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (t *ImageServiceTest) TestPurgeOrphansDeleteRowsFailed() {
+	controller := gomock.NewController(t.T())
+	client := mock_bucket.NewMockClient(controller)
+	repo := &mock.ImageRepositoryMock{}
+
+	repo.On("FindOrphans", testifyMock.Anything, t.before, testifyMock.Anything).Return(&t.orphans, nil)
+	client.EXPECT().DeleteMany(gomock.Any(), []string{"orphan-1.jpg", "orphan-2.jpg"}).Return(nil)
+	repo.On("DeleteMany", testifyMock.Anything, testifyMock.Anything).Return(errors.New("something wrong"))
+
+	srv := image.NewService(client, repo, &utils.RandomUtilMock{})
+	actual, err := srv.PurgeOrphans(context.Background(), t.before)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
+}
@@ -0,0 +1,143 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Hook is a component of the process such as the http server, a worker or a connection pool. Start and Stop can
+// be nil. Start should not block, long running work is started in a goroutine and reported with Manager.Fail
+type Hook struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Manager starts the hooks in the order they were appended and stops them in the reverse order, so a component
+// is stopped before the ones it was started after, e.g. the server drains its requests before the database closes
+type Manager struct {
+	timeout time.Duration
+	hooks   []Hook
+	started []Hook
+	failed  chan error
+}
+
+// NewManager returns a manager that gives the hooks timeout in total to stop
+func NewManager(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout, failed: make(chan error, 1)}
+}
+
+func (m *Manager) Append(hook Hook) {
+	m.hooks = append(m.hooks, hook)
+}
+
+// Start runs the start hooks in order. When one fails the hooks that were already started are stopped
+func (m *Manager) Start(ctx context.Context) error {
+	for _, hook := range m.hooks {
+		if hook.Start != nil {
+			if err := hook.Start(ctx); err != nil {
+				err = fmt.Errorf("start %s: %w", hook.Name, err)
+				return errors.Join(err, m.Stop(context.Background()))
+			}
+		}
+
+		log.Info().
+			Str("service", "lifecycle").
+			Str("component", hook.Name).
+			Msg("Started")
+		m.started = append(m.started, hook)
+	}
+
+	return nil
+}
+
+// Stop runs the stop hooks of the started components in reverse order within the timeout of the manager. Every
+// stop hook is called even when one before it fails or runs out of time, the ones called after the timeout get a
+// done context and are not waited for. The errors are returned together
+func (m *Manager) Stop(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(m.started) - 1; i >= 0; i-- {
+		hook := m.started[i]
+		if hook.Stop == nil {
+			continue
+		}
+
+		if err := stop(ctx, hook); err != nil {
+			log.Error().
+				Err(err).
+				Str("service", "lifecycle").
+				Str("component", hook.Name).
+				Msg("Failed to stop")
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, err))
+			continue
+		}
+
+		log.Info().
+			Str("service", "lifecycle").
+			Str("component", hook.Name).
+			Msg("Stopped")
+	}
+	m.started = nil
+
+	return errors.Join(errs...)
+}
+
+// Fail reports that a component failed after it was started, e.g. the server could not listen. Run then stops
+// the process
+func (m *Manager) Fail(name string, err error) {
+	select {
+	case m.failed <- fmt.Errorf("%s: %w", name, err):
+	default:
+	}
+}
+
+// Run starts the hooks, waits for SIGINT or SIGTERM, the context to be done or a component to fail, and stops
+// them. The error is not nil when a component failed to start, failed while running or failed to stop
+func (m *Manager) Run(ctx context.Context) error {
+	if err := m.Start(ctx); err != nil {
+		return err
+	}
+
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	defer stopSignals()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		log.Info().
+			Str("service", "lifecycle").
+			Msg("Shutting down")
+	case runErr = <-m.failed:
+		log.Error().
+			Err(runErr).
+			Str("service", "lifecycle").
+			Msg("Component failed, shutting down")
+	}
+
+	return errors.Join(runErr, m.Stop(context.Background()))
+}
+
+// stop gives up on a hook that does not return once the context is done so the others still get to stop
+func stop(ctx context.Context, hook Hook) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- hook.Stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
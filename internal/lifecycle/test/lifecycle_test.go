@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LifecycleTest struct {
+	suite.Suite
+	calls []string
+}
+
+func TestLifecycle(t *testing.T) {
+	suite.Run(t, new(LifecycleTest))
+}
+
+func (t *LifecycleTest) SetupTest() {
+	t.calls = nil
+}
+
+// hook records its start and stop calls and returns the errors
+func (t *LifecycleTest) hook(name string, startErr error, stopErr error) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		Start: func(context.Context) error {
+			t.calls = append(t.calls, "start "+name)
+			return startErr
+		},
+		Stop: func(context.Context) error {
+			t.calls = append(t.calls, "stop "+name)
+			return stopErr
+		},
+	}
+}
+
+func (t *LifecycleTest) TestStartStopOrder() {
+	lc := lifecycle.NewManager(time.Second)
+	lc.Append(t.hook("database", nil, nil))
+	lc.Append(t.hook("worker", nil, nil))
+	lc.Append(t.hook("server", nil, nil))
+
+	assert.Nil(t.T(), lc.Start(context.Background()))
+	assert.Nil(t.T(), lc.Stop(context.Background()))
+
+	assert.Equal(t.T(), []string{
+		"start database", "start worker", "start server",
+		"stop server", "stop worker", "stop database",
+	}, t.calls)
+}
+
+func (t *LifecycleTest) TestStartFailedStopsStarted() {
+	startErr := errors.New("connection refused")
+	lc := lifecycle.NewManager(time.Second)
+	lc.Append(t.hook("database", nil, nil))
+	lc.Append(t.hook("worker", startErr, nil))
+	lc.Append(t.hook("server", nil, nil))
+
+	err := lc.Start(context.Background())
+
+	assert.ErrorIs(t.T(), err, startErr)
+	assert.Equal(t.T(), []string{"start database", "start worker", "stop database"}, t.calls)
+}
+
+func (t *LifecycleTest) TestStopFailedStopsOthers() {
+	redisErr := errors.New("redis: client is closed")
+	databaseErr := errors.New("sql: database is closed")
+	lc := lifecycle.NewManager(time.Second)
+	lc.Append(t.hook("database", nil, databaseErr))
+	lc.Append(t.hook("redis", nil, redisErr))
+	lc.Append(t.hook("server", nil, nil))
+
+	assert.Nil(t.T(), lc.Start(context.Background()))
+	err := lc.Stop(context.Background())
+
+	assert.ErrorIs(t.T(), err, redisErr)
+	assert.ErrorIs(t.T(), err, databaseErr)
+	assert.Equal(t.T(), []string{"stop server", "stop redis", "stop database"}, t.calls[3:])
+}
+
+func (t *LifecycleTest) TestStopTimeout() {
+	databaseStopped := make(chan struct{})
+	lc := lifecycle.NewManager(20 * time.Millisecond)
+	lc.Append(lifecycle.Hook{
+		Name: "database",
+		Stop: func(context.Context) error {
+			close(databaseStopped)
+			return nil
+		},
+	})
+	lc.Append(lifecycle.Hook{
+		Name: "server",
+		Stop: func(context.Context) error {
+			// ignores the context like a hung connection
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+
+	assert.Nil(t.T(), lc.Start(context.Background()))
+	err := lc.Stop(context.Background())
+
+	assert.ErrorIs(t.T(), err, context.DeadlineExceeded)
+	select {
+	case <-databaseStopped:
+	case <-time.After(time.Second):
+		t.T().Fatal("database was not stopped after the server timed out")
+	}
+}
+
+func (t *LifecycleTest) TestRunStopsOnFail() {
+	listenErr := errors.New("address already in use")
+	lc := lifecycle.NewManager(time.Second)
+	lc.Append(t.hook("database", nil, nil))
+	lc.Append(lifecycle.Hook{
+		Name: "server",
+		Start: func(context.Context) error {
+			go lc.Fail("server", listenErr)
+			return nil
+		},
+	})
+
+	err := lc.Run(context.Background())
+
+	assert.ErrorIs(t.T(), err, listenErr)
+	assert.Equal(t.T(), []string{"start database", "stop database"}, t.calls)
+}
+
+func (t *LifecycleTest) TestRunStopsOnContextDone() {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc := lifecycle.NewManager(time.Second)
+	lc.Append(t.hook("database", nil, nil))
+	lc.Append(lifecycle.Hook{
+		Name: "server",
+		Start: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+
+	err := lc.Run(ctx)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), []string{"start database", "stop database"}, t.calls)
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+)
+
+// Namespace prefixes every metric of the backend
+const Namespace = "johnjud"
+
+const (
+	ResultOk    = "ok"
+	ResultMiss  = "miss"
+	ResultError = "error"
+)
+
+// Registry holds every collector of the backend, it is used instead of the global registry so tests can gather it
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of handled requests by route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of handled requests by route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	dbQueryDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of gorm queries by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "result"})
+
+	redisCommandDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Duration of cache repository commands.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5},
+	}, []string{"command", "result"})
+
+	bucketOperations = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "bucket",
+		Name:      "operations_total",
+		Help:      "Number of bucket uploads and deletes.",
+	}, []string{"operation", "result"})
+
+	bucketBytes = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "bucket",
+		Name:      "bytes_total",
+		Help:      "Number of bytes uploaded to the bucket.",
+	}, []string{"operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the metrics in the prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// NewServer serves the metrics at /metrics on their own port, so they are not reachable through the api
+func NewServer(port int) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%v", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
+
+func ObserveRequest(method string, route string, status string, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, status).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func ObserveQuery(operation string, table string, err error, duration time.Duration) {
+	dbQueryDuration.WithLabelValues(operation, table, result(err)).Observe(duration.Seconds())
+}
+
+// ObserveRedis counts a missing key as a miss instead of an error
+func ObserveRedis(command string, start time.Time, err error) {
+	res := result(err)
+	if errors.Is(err, redis.Nil) {
+		res = ResultMiss
+	}
+	redisCommandDuration.WithLabelValues(command, res).Observe(time.Since(start).Seconds())
+}
+
+// ObserveBucket counts the operation, the bytes are only added when it succeeded
+func ObserveBucket(operation string, bytes int, err error) {
+	bucketOperations.WithLabelValues(operation, result(err)).Inc()
+	if err == nil && bytes > 0 {
+		bucketBytes.WithLabelValues(operation).Add(float64(bytes))
+	}
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultOk
+}
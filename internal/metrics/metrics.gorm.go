@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+// GormPlugin times every query through the gorm callbacks
+type GormPlugin struct{}
+
+func NewGormPlugin() gorm.Plugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", db.Callback().Create().Before("gorm:create").Register, db.Callback().Create().After("gorm:create").Register},
+		{"query", db.Callback().Query().Before("gorm:query").Register, db.Callback().Query().After("gorm:query").Register},
+		{"update", db.Callback().Update().Before("gorm:update").Register, db.Callback().Update().After("gorm:update").Register},
+		{"delete", db.Callback().Delete().Before("gorm:delete").Register, db.Callback().Delete().After("gorm:delete").Register},
+		{"row", db.Callback().Row().Before("gorm:row").Register, db.Callback().Row().After("gorm:row").Register},
+		{"raw", db.Callback().Raw().Before("gorm:raw").Register, db.Callback().Raw().After("gorm:raw").Register},
+	}
+
+	for _, callback := range callbacks {
+		if err := callback.before("metrics:before_"+callback.operation, start); err != nil {
+			return err
+		}
+		if err := callback.after("metrics:after_"+callback.operation, observe(callback.operation)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func start(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		startedAt, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		// a missing record is an expected answer, not a failed query
+		err := db.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+
+		ObserveQuery(operation, table, err, time.Since(startedAt))
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/isd-sgcu/johnjud-backend/config"
 	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/apikey"
 	"github.com/isd-sgcu/johnjud-backend/internal/auth"
 	"github.com/isd-sgcu/johnjud-backend/internal/dto"
 	"github.com/isd-sgcu/johnjud-backend/internal/router"
@@ -13,15 +14,17 @@ import (
 
 type Guard struct {
 	service     auth.Service
+	apiKey      apikey.Service
 	excludes    map[string]struct{}
 	adminpath   map[string]struct{}
 	conf        config.App
 	versionList map[string]struct{}
 }
 
-func NewAuthGuard(s auth.Service, e map[string]struct{}, a map[string]struct{}, conf config.App, versionList map[string]struct{}) Guard {
+func NewAuthGuard(s auth.Service, k apikey.Service, e map[string]struct{}, a map[string]struct{}, conf config.App, versionList map[string]struct{}) Guard {
 	return Guard{
 		service:     s,
+		apiKey:      k,
 		excludes:    e,
 		adminpath:   a,
 		conf:        conf,
@@ -30,12 +33,12 @@ func NewAuthGuard(s auth.Service, e map[string]struct{}, a map[string]struct{},
 }
 
 func (m *Guard) Use(ctx router.IContext) error {
-	method := ctx.Method()
-	path := ctx.Path()
+	path := auth.FormatRoute(ctx.Method(), ctx.Path(), m.versionList)
+
+	if key := ctx.Header(constant.ApiKeyHeader); key != "" {
+		return m.useApiKey(ctx, key, path)
+	}
 
-	path = utils.TrimInList(path, "/", m.versionList)
-	ids := auth.FindIDFromPath(path)
-	path = auth.FormatPath(method, path, ids)
 	if utils.IsExisted(m.excludes, path) {
 		return ctx.Next()
 	}
@@ -49,7 +52,7 @@ func (m *Guard) Use(ctx router.IContext) error {
 		return nil
 	}
 
-	payload, err := m.service.Validate(token)
+	payload, err := m.service.Validate(ctx.UserContext(), token)
 	if err != nil {
 		ctx.JSON(err.StatusCode, err)
 		return nil
@@ -57,6 +60,7 @@ func (m *Guard) Use(ctx router.IContext) error {
 
 	ctx.StoreValue("UserId", payload.UserId)
 	ctx.StoreValue("Role", payload.Role)
+	ctx.StoreValue("AuthSessionId", payload.AuthSessionId)
 
 	if utils.IsExisted(m.adminpath, path) && payload.Role != string(constant.ADMIN) {
 		ctx.JSON(http.StatusUnauthorized, dto.ResponseErr{
@@ -69,3 +73,36 @@ func (m *Guard) Use(ctx router.IContext) error {
 
 	return ctx.Next()
 }
+
+// useApiKey authorizes the request by the scopes of the key instead of a role, keys only reach the paths in ApiKeyPath
+func (m *Guard) useApiKey(ctx router.IContext, key string, path string) error {
+	payload, respErr := m.apiKey.Validate(ctx.UserContext(), key)
+	if respErr != nil {
+		ctx.JSON(respErr.StatusCode, respErr)
+		return nil
+	}
+
+	scope, ok := constant.ApiKeyPath[path]
+	if !ok || !apikey.HasScope(payload, scope) {
+		ctx.JSON(http.StatusForbidden, dto.ForbiddenError(constant.ApiKeyScopeErrorMessage))
+		return nil
+	}
+
+	retryAfter, err := m.apiKey.Allow(ctx.UserContext(), payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.InternalServerError(constant.InternalServerErrorMessage))
+		return nil
+	}
+	if retryAfter > 0 {
+		router.JSONError(ctx, dto.TooManyRequestsError(constant.ApiKeyRateLimitErrorMessage, retryAfter))
+		return nil
+	}
+
+	// handlers reading the user get the admin who created the key
+	ctx.StoreValue("UserId", payload.CreatedBy)
+	ctx.StoreValue("Role", "")
+	ctx.StoreValue("AuthSessionId", "")
+	ctx.StoreValue("ApiKeyId", payload.Id)
+
+	return ctx.Next()
+}
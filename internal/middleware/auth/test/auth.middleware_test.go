@@ -0,0 +1,138 @@
+package test
+
+import (
+	ctx "context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	guard "github.com/isd-sgcu/johnjud-backend/internal/middleware/auth"
+	routerMock "github.com/isd-sgcu/johnjud-backend/mocks/router"
+	mock_apikey "github.com/isd-sgcu/johnjud-backend/mocks/service/apikey"
+	mock_auth "github.com/isd-sgcu/johnjud-backend/mocks/service/auth"
+	"github.com/stretchr/testify/suite"
+)
+
+type GuardTest struct {
+	suite.Suite
+	key     string
+	payload *dto.ApiKeyPayload
+}
+
+func TestGuard(t *testing.T) {
+	suite.Run(t, new(GuardTest))
+}
+
+func (t *GuardTest) SetupTest() {
+	t.key = "jj_key"
+	t.payload = &dto.ApiKeyPayload{
+		Id:        uuid.NewString(),
+		CreatedBy: uuid.NewString(),
+		Scopes:    []constant.ApiKeyScope{constant.PetsReadScope},
+		RateLimit: 60,
+	}
+}
+
+func (t *GuardTest) newGuard(controller *gomock.Controller, apiKeySvc *mock_apikey.MockService) guard.Guard {
+	return guard.NewAuthGuard(mock_auth.NewMockService(controller), apiKeySvc, constant.ExcludePath, constant.AdminPath, config.App{}, constant.VersionList)
+}
+
+func (t *GuardTest) TestApiKeyAllowed() {
+	controller := gomock.NewController(t.T())
+	apiKeySvc := mock_apikey.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodGet)
+	context.EXPECT().Path().Return("/v1/pets/admin")
+	context.EXPECT().Header(constant.ApiKeyHeader).Return(t.key)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	apiKeySvc.EXPECT().Validate(gomock.Any(), t.key).Return(t.payload, nil)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	apiKeySvc.EXPECT().Allow(gomock.Any(), t.payload).Return(time.Duration(0), nil)
+	context.EXPECT().StoreValue("UserId", t.payload.CreatedBy)
+	context.EXPECT().StoreValue("Role", "")
+	context.EXPECT().StoreValue("AuthSessionId", "")
+	context.EXPECT().StoreValue("ApiKeyId", t.payload.Id)
+	context.EXPECT().Next().Return(nil)
+
+	g := t.newGuard(controller, apiKeySvc)
+	g.Use(context)
+}
+
+func (t *GuardTest) TestApiKeyMissingScope() {
+	id := uuid.NewString()
+
+	controller := gomock.NewController(t.T())
+	apiKeySvc := mock_apikey.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodDelete)
+	context.EXPECT().Path().Return("/v1/pets/" + id)
+	context.EXPECT().Header(constant.ApiKeyHeader).Return(t.key)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	apiKeySvc.EXPECT().Validate(gomock.Any(), t.key).Return(t.payload, nil)
+	context.EXPECT().JSON(http.StatusForbidden, dto.ForbiddenError(constant.ApiKeyScopeErrorMessage))
+
+	g := t.newGuard(controller, apiKeySvc)
+	g.Use(context)
+}
+
+func (t *GuardTest) TestApiKeyPathNotAllowed() {
+	controller := gomock.NewController(t.T())
+	apiKeySvc := mock_apikey.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodGet)
+	context.EXPECT().Path().Return("/v1/user/admin")
+	context.EXPECT().Header(constant.ApiKeyHeader).Return(t.key)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	apiKeySvc.EXPECT().Validate(gomock.Any(), t.key).Return(t.payload, nil)
+	context.EXPECT().JSON(http.StatusForbidden, dto.ForbiddenError(constant.ApiKeyScopeErrorMessage))
+
+	g := t.newGuard(controller, apiKeySvc)
+	g.Use(context)
+}
+
+func (t *GuardTest) TestApiKeyRateLimited() {
+	controller := gomock.NewController(t.T())
+	apiKeySvc := mock_apikey.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	errResponse := dto.TooManyRequestsError(constant.ApiKeyRateLimitErrorMessage, 12*time.Second)
+
+	context.EXPECT().Method().Return(http.MethodGet)
+	context.EXPECT().Path().Return("/v1/pets")
+	context.EXPECT().Header(constant.ApiKeyHeader).Return(t.key)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	apiKeySvc.EXPECT().Validate(gomock.Any(), t.key).Return(t.payload, nil)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	apiKeySvc.EXPECT().Allow(gomock.Any(), t.payload).Return(12*time.Second, nil)
+	context.EXPECT().SetHeader("Retry-After", "12")
+	context.EXPECT().JSON(http.StatusTooManyRequests, errResponse)
+
+	g := t.newGuard(controller, apiKeySvc)
+	g.Use(context)
+}
+
+func (t *GuardTest) TestApiKeyInvalid() {
+	controller := gomock.NewController(t.T())
+	apiKeySvc := mock_apikey.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	errResponse := dto.UnauthorizedError(constant.InvalidApiKeyErrorMessage)
+
+	context.EXPECT().Method().Return(http.MethodGet)
+	context.EXPECT().Path().Return("/v1/pets")
+	context.EXPECT().Header(constant.ApiKeyHeader).Return(t.key)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	apiKeySvc.EXPECT().Validate(gomock.Any(), t.key).Return(nil, errResponse)
+	context.EXPECT().JSON(http.StatusUnauthorized, errResponse)
+
+	g := t.newGuard(controller, apiKeySvc)
+	g.Use(context)
+}
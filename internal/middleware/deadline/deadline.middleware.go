@@ -0,0 +1,25 @@
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// New gives the user context of every request a deadline so the queries, Redis commands and bucket calls of a
+// request that runs too long are cancelled. Fasthttp does not report when the client goes away, so the timeout
+// is what bounds the work of abandoned requests. The requests still running when base is cancelled, which the
+// server does once shutdown has waited long enough for them, are cancelled too
+func New(base context.Context, timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+
+		stop := context.AfterFunc(base, cancel)
+		defer stop()
+
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
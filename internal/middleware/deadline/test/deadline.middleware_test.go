@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	deadlineMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/deadline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DeadlineMiddlewareTest struct {
+	suite.Suite
+	started chan struct{}
+}
+
+func TestDeadlineMiddleware(t *testing.T) {
+	suite.Run(t, new(DeadlineMiddlewareTest))
+}
+
+func (t *DeadlineMiddlewareTest) SetupTest() {
+	t.started = make(chan struct{}, 1)
+}
+
+// newApp has a handler that waits for the context of the request to be done and returns why it was
+func (t *DeadlineMiddlewareTest) newApp(base context.Context, timeout time.Duration) *fiber.App {
+	app := fiber.New()
+	app.Use(deadlineMiddleware.New(base, timeout))
+	app.Get("/", func(c *fiber.Ctx) error {
+		t.started <- struct{}{}
+		select {
+		case <-c.UserContext().Done():
+			return c.SendString(c.UserContext().Err().Error())
+		case <-time.After(5 * time.Second):
+			return c.SendString("not cancelled")
+		}
+	})
+	return app
+}
+
+func (t *DeadlineMiddlewareTest) TestTimeout() {
+	app := t.newApp(context.Background(), 20*time.Millisecond)
+
+	body := t.send(app)
+
+	assert.Equal(t.T(), context.DeadlineExceeded.Error(), body)
+}
+
+func (t *DeadlineMiddlewareTest) TestBaseCancelled() {
+	base, cancel := context.WithCancel(context.Background())
+	app := t.newApp(base, time.Minute)
+
+	go func() {
+		<-t.started
+		cancel()
+	}()
+	body := t.send(app)
+
+	assert.Equal(t.T(), context.Canceled.Error(), body)
+}
+
+func (t *DeadlineMiddlewareTest) send(app *fiber.App) string {
+	res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), -1)
+	assert.Nil(t.T(), err)
+
+	body, err := io.ReadAll(res.Body)
+	assert.Nil(t.T(), err)
+
+	return string(body)
+}
@@ -0,0 +1,95 @@
+package forwarded
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// New rewrites the proxy header to the client ip alone. A proxy appends the address it got the request from to
+// X-Forwarded-For, so the entries left of it were written by the client, yet fiber reads the leftmost valid one.
+// The rightmost entry that is not a trusted proxy is kept instead, before anything reads the ip. The header of
+// a caller that is not a trusted proxy is left to fiber, which ignores it
+func New(header string, trustedProxies []string) fiber.Handler {
+	if header == "" {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	trusted := parseTrusted(trustedProxies)
+
+	return func(c *fiber.Ctx) error {
+		if !trusted.contains(c.Context().RemoteIP()) {
+			return c.Next()
+		}
+
+		var hops []string
+		for _, value := range c.Request().Header.PeekAll(header) {
+			hops = append(hops, strings.Split(string(value), ",")...)
+		}
+		if len(hops) == 0 {
+			return c.Next()
+		}
+
+		client := ""
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				break
+			}
+			client = ip.String()
+			if !trusted.contains(ip) {
+				break
+			}
+		}
+
+		c.Request().Header.Del(header)
+		if client != "" {
+			c.Request().Header.Set(header, client)
+		}
+
+		return c.Next()
+	}
+}
+
+type proxySet struct {
+	ips    map[string]struct{}
+	ranges []*net.IPNet
+}
+
+// parseTrusted reads the proxies the same way fiber does, a value with a slash is a cidr range and
+// anything that does not parse is skipped
+func parseTrusted(proxies []string) *proxySet {
+	trusted := &proxySet{ips: map[string]struct{}{}}
+	for _, proxy := range proxies {
+		if strings.Contains(proxy, "/") {
+			if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+				trusted.ranges = append(trusted.ranges, ipNet)
+			}
+			continue
+		}
+		if ip := net.ParseIP(proxy); ip != nil {
+			trusted.ips[ip.String()] = struct{}{}
+		}
+	}
+
+	return trusted
+}
+
+func (t *proxySet) contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if _, ok := t.ips[ip.String()]; ok {
+		return true
+	}
+	for _, ipNet := range t.ranges {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	forwardedMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/forwarded"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const header = "X-Forwarded-For"
+
+type ForwardedMiddlewareTest struct {
+	suite.Suite
+}
+
+func TestForwardedMiddleware(t *testing.T) {
+	suite.Run(t, new(ForwardedMiddlewareTest))
+}
+
+// newApp is configured like the router, the requests of app.Test come from 0.0.0.0
+func (t *ForwardedMiddlewareTest) newApp(trustedProxies []string) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ProxyHeader:             header,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          trustedProxies,
+		EnableIPValidation:      true,
+	})
+	app.Use(forwardedMiddleware.New(header, trustedProxies))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.IP())
+	})
+
+	return app
+}
+
+func (t *ForwardedMiddlewareTest) send(app *fiber.App, forwardedFor ...string) string {
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	for _, value := range forwardedFor {
+		req.Header.Add(header, value)
+	}
+
+	resp, err := app.Test(req)
+	assert.Nil(t.T(), err)
+	body, err := io.ReadAll(resp.Body)
+	assert.Nil(t.T(), err)
+
+	return string(body)
+}
+
+func (t *ForwardedMiddlewareTest) TestSpoofedHopsAreIgnored() {
+	app := t.newApp([]string{"0.0.0.0"})
+
+	assert.Equal(t.T(), "198.51.100.7", t.send(app, "203.0.113.1, 203.0.113.2, 198.51.100.7"))
+	assert.Equal(t.T(), "198.51.100.7", t.send(app, "203.0.113.1", "198.51.100.7"))
+}
+
+func (t *ForwardedMiddlewareTest) TestTrustedHopsAreSkipped() {
+	app := t.newApp([]string{"0.0.0.0", "10.0.0.0/8"})
+
+	assert.Equal(t.T(), "198.51.100.7", t.send(app, "203.0.113.1, 198.51.100.7, 10.0.0.2, 10.0.0.3"))
+}
+
+func (t *ForwardedMiddlewareTest) TestOnlyTrustedHops() {
+	app := t.newApp([]string{"0.0.0.0", "10.0.0.0/8"})
+
+	assert.Equal(t.T(), "10.0.0.2", t.send(app, "10.0.0.2, 10.0.0.3"))
+}
+
+func (t *ForwardedMiddlewareTest) TestInvalidHop() {
+	app := t.newApp([]string{"0.0.0.0"})
+
+	assert.Equal(t.T(), "0.0.0.0", t.send(app, "198.51.100.7, unknown"))
+}
+
+func (t *ForwardedMiddlewareTest) TestUntrustedCaller() {
+	app := t.newApp([]string{"10.0.0.1"})
+
+	assert.Equal(t.T(), "0.0.0.0", t.send(app, "203.0.113.1, 198.51.100.7"))
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// New writes an access log line for every request once it has been handled. Successful requests to the given
+// paths, such as the health checks polled by the orchestrator, are left out
+func New(skipPaths ...string) fiber.Handler {
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		latency := time.Since(start)
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		if _, ok := skip[c.Path()]; ok && status < fiber.StatusBadRequest {
+			return err
+		}
+
+		var event *zerolog.Event
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			event = log.Error()
+		case status >= fiber.StatusBadRequest:
+			event = log.Warn()
+		default:
+			event = log.Info()
+		}
+
+		userId, _ := c.Locals("UserId").(string)
+		event.
+			Ctx(c.UserContext()).
+			Err(err).
+			Str("service", "http").
+			Str("method", c.Method()).
+			Str("route", c.Route().Path).
+			Str("path", c.Path()).
+			Int("status", status).
+			Dur("latency", latency).
+			Str("user_id", userId).
+			Str("ip", c.IP()).
+			Str("user_agent", c.Get(fiber.HeaderUserAgent)).
+			Msg("Request handled")
+
+		return err
+	}
+}
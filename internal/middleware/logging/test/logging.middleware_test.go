@@ -0,0 +1,127 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	loggingMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/logging"
+	requestIdMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/requestid"
+	"github.com/isd-sgcu/johnjud-backend/internal/requestid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const userId = "3f1d6b0e-7a2c-4c59-8d1e-2b4f9a6c7e10"
+
+type LoggingMiddlewareTest struct {
+	suite.Suite
+	logger zerolog.Logger
+	output *bytes.Buffer
+	app    *fiber.App
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	suite.Run(t, new(LoggingMiddlewareTest))
+}
+
+func (t *LoggingMiddlewareTest) SetupTest() {
+	t.logger = log.Logger
+	t.output = &bytes.Buffer{}
+	log.Logger = zerolog.New(t.output).Hook(requestid.LogHook{})
+
+	r := fiber.New()
+	r.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	r.Get("/broken", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	})
+	pets := r.Group("/pets", func(c *fiber.Ctx) error {
+		c.Locals("UserId", userId)
+		return c.Next()
+	})
+	pets.Get("/:id", func(c *fiber.Ctx) error {
+		log.Info().Ctx(c.UserContext()).Str("service", "pet").Msg("Found pet")
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	t.app = fiber.New()
+	t.app.Use(requestIdMiddleware.New())
+	t.app.Use(loggingMiddleware.New("/v1/healthz"))
+	t.app.Mount("/v1", r)
+}
+
+func (t *LoggingMiddlewareTest) TearDownTest() {
+	log.Logger = t.logger
+}
+
+func (t *LoggingMiddlewareTest) TestAccessLog() {
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/pets/5c4a2a1e-3a1c-4f43-9f0e-1c2f6d0c3a11", nil)
+	req.Header.Set(requestid.Header, "request-1")
+
+	_, err := t.app.Test(req)
+	assert.Nil(t.T(), err)
+
+	lines := t.lines()
+	assert.Len(t.T(), lines, 2)
+
+	service, access := lines[0], lines[1]
+	assert.Equal(t.T(), "Found pet", service["message"])
+	assert.Equal(t.T(), "request-1", service["request_id"])
+
+	assert.Equal(t.T(), "info", access["level"])
+	assert.Equal(t.T(), "request-1", access["request_id"])
+	assert.Equal(t.T(), "GET", access["method"])
+	assert.Equal(t.T(), "/v1/pets/:id", access["route"])
+	assert.Equal(t.T(), float64(fiber.StatusOK), access["status"])
+	assert.Equal(t.T(), userId, access["user_id"])
+	assert.Contains(t.T(), access, "latency")
+}
+
+func (t *LoggingMiddlewareTest) TestErrorLevel() {
+	_, err := t.app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/broken", nil))
+	assert.Nil(t.T(), err)
+
+	lines := t.lines()
+	assert.Len(t.T(), lines, 1)
+	assert.Equal(t.T(), "error", lines[0]["level"])
+	assert.Equal(t.T(), float64(fiber.StatusInternalServerError), lines[0]["status"])
+	assert.Equal(t.T(), "", lines[0]["user_id"])
+	assert.NotEmpty(t.T(), lines[0]["request_id"])
+}
+
+func (t *LoggingMiddlewareTest) TestNotFoundLevel() {
+	_, err := t.app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/unknown", nil))
+	assert.Nil(t.T(), err)
+
+	lines := t.lines()
+	assert.Len(t.T(), lines, 1)
+	assert.Equal(t.T(), "warn", lines[0]["level"])
+	assert.Equal(t.T(), float64(fiber.StatusNotFound), lines[0]["status"])
+}
+
+func (t *LoggingMiddlewareTest) TestSkipPath() {
+	_, err := t.app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/healthz", nil))
+	assert.Nil(t.T(), err)
+
+	assert.Empty(t.T(), t.lines())
+}
+
+func (t *LoggingMiddlewareTest) lines() []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(t.output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var fields map[string]interface{}
+		assert.Nil(t.T(), json.Unmarshal([]byte(line), &fields))
+		lines = append(lines, fields)
+	}
+	return lines
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/isd-sgcu/johnjud-backend/internal/metrics"
+)
+
+// New records every request by the template of the route it matched, e.g. /v1/pets/:id, so ids do not become labels.
+// Requests stopped by a group middleware such as the auth guard are recorded under the prefix of the group
+func New() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		metrics.ObserveRequest(c.Method(), c.Route().Path, strconv.Itoa(status), time.Since(start))
+		return err
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/isd-sgcu/johnjud-backend/internal/metrics"
+	metricsMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MetricsMiddlewareTest struct {
+	suite.Suite
+	app *fiber.App
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	suite.Run(t, new(MetricsMiddlewareTest))
+}
+
+func (t *MetricsMiddlewareTest) SetupTest() {
+	r := fiber.New()
+	pets := r.Group("/pets", func(c *fiber.Ctx) error {
+		if c.Get("Authorization") == "" && c.Method() != fiber.MethodGet {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return c.Next()
+	})
+	pets.Get("/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNotFound)
+	})
+	pets.Delete("/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	t.app = fiber.New()
+	t.app.Use(metricsMiddleware.New())
+	t.app.Mount("/v1", r)
+}
+
+func (t *MetricsMiddlewareTest) TestRouteTemplate() {
+	for _, id := range []string{"5c4a2a1e-3a1c-4f43-9f0e-1c2f6d0c3a11", "0b0f6a0e-8f6e-4d7b-a4d6-6b0e4f6c9d22"} {
+		_, err := t.app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/pets/"+id, nil))
+		assert.Nil(t.T(), err)
+	}
+
+	actual := t.scrape()
+
+	assert.Contains(t.T(), actual, `johnjud_http_requests_total{method="GET",route="/v1/pets/:id",status="404"} 2`)
+	assert.Contains(t.T(), actual, `johnjud_http_request_duration_seconds_count{method="GET",route="/v1/pets/:id"} 2`)
+	assert.NotContains(t.T(), actual, "5c4a2a1e")
+}
+
+func (t *MetricsMiddlewareTest) TestStoppedByGroupMiddleware() {
+	_, err := t.app.Test(httptest.NewRequest(fiber.MethodDelete, "/v1/pets/5c4a2a1e-3a1c-4f43-9f0e-1c2f6d0c3a11", nil))
+	assert.Nil(t.T(), err)
+
+	assert.Contains(t.T(), t.scrape(), `johnjud_http_requests_total{method="DELETE",route="/v1/pets",status="401"} 1`)
+}
+
+func (t *MetricsMiddlewareTest) scrape() string {
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	return recorder.Body.String()
+}
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/ratelimit"
+	"github.com/isd-sgcu/johnjud-backend/internal/router"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultPolicy is the policy of the routes that are not listed in another one
+	DefaultPolicy = "default"
+	// GuardPolicy counts every request by ip before the guard, so the requests it rejects are limited too
+	GuardPolicy = "guard"
+)
+
+// Limiter takes from the guard policy before the guard, and from the policy of the route after it so the requests
+// of a signed in user or an api key are counted by them
+type Limiter struct {
+	service     ratelimit.Service
+	policies    map[string]config.RateLimitPolicy
+	routes      map[string]string
+	versionList map[string]struct{}
+}
+
+func NewLimiter(s ratelimit.Service, conf config.RateLimit, versionList map[string]struct{}) Limiter {
+	routes := map[string]string{}
+	for name, policy := range conf.Policies {
+		if name == GuardPolicy {
+			continue
+		}
+		for _, route := range policy.Routes {
+			routes[route] = name
+		}
+	}
+
+	return Limiter{
+		service:     s,
+		policies:    conf.Policies,
+		routes:      routes,
+		versionList: versionList,
+	}
+}
+
+// UseBeforeGuard takes from the guard policy by ip, the requests without a valid token or api key are rejected by
+// the guard and never reach Use
+func (m *Limiter) UseBeforeGuard(ctx router.IContext) error {
+	policy, ok := m.policies[GuardPolicy]
+	if !ok {
+		return ctx.Next()
+	}
+
+	return m.take(ctx, GuardPolicy, policy, "ip:"+ctx.IP())
+}
+
+// Use takes from the policy of the route after the guard
+func (m *Limiter) Use(ctx router.IContext) error {
+	path := auth.FormatRoute(ctx.Method(), ctx.Path(), m.versionList)
+
+	name, ok := m.routes[path]
+	if !ok {
+		name = DefaultPolicy
+	}
+	policy, ok := m.policies[name]
+	if !ok {
+		return ctx.Next()
+	}
+
+	return m.take(ctx, name, policy, m.key(ctx, policy.Key))
+}
+
+// take sends the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers and rejects
+// the request with 429 once the limit is reached. The request is let through when redis fails
+func (m *Limiter) take(ctx router.IContext, name string, policy config.RateLimitPolicy, key string) error {
+	result, err := m.service.Take(ctx.UserContext(), name, policy, key)
+	if err != nil {
+		log.Error().
+			Ctx(ctx.UserContext()).
+			Err(err).
+			Str("service", "rate limit").
+			Str("module", "use").
+			Str("policy", name).
+			Msg("Error taking from the rate limit, letting the request through")
+		return ctx.Next()
+	}
+
+	ctx.SetHeader("RateLimit-Limit", strconv.Itoa(policy.Limit))
+	ctx.SetHeader("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	ctx.SetHeader("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+	ctx.SetHeader("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, policy.Window))
+
+	if !result.Allowed {
+		router.JSONError(ctx, dto.TooManyRequestsError(constant.RateLimitErrorMessage, result.RetryAfter))
+		return nil
+	}
+
+	return ctx.Next()
+}
+
+// key falls back from the api key to the user and from the user to the ip
+func (m *Limiter) key(ctx router.IContext, by string) string {
+	if by == "api_key" {
+		if id := ctx.ApiKeyID(); id != "" {
+			return "api_key:" + id
+		}
+		by = "user"
+	}
+	if by == "user" {
+		if id := ctx.UserID(); id != "" {
+			return "user:" + id
+		}
+	}
+
+	return "ip:" + ctx.IP()
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
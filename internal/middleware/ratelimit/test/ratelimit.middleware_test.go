@@ -0,0 +1,311 @@
+package test
+
+import (
+	ctx "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	ratelimitMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/ratelimit"
+	"github.com/isd-sgcu/johnjud-backend/internal/ratelimit"
+	"github.com/isd-sgcu/johnjud-backend/internal/router"
+	routerMock "github.com/isd-sgcu/johnjud-backend/mocks/router"
+	mock_ratelimit "github.com/isd-sgcu/johnjud-backend/mocks/service/ratelimit"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimitMiddlewareTest struct {
+	suite.Suite
+	conf     config.RateLimit
+	ip       string
+	userId   string
+	apiKeyId string
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	suite.Run(t, new(RateLimitMiddlewareTest))
+}
+
+func (t *RateLimitMiddlewareTest) SetupTest() {
+	t.conf = config.RateLimit{
+		Policies: map[string]config.RateLimitPolicy{
+			"default": {Limit: 300, Window: 60, Key: "user"},
+			"signup":  {Limit: 5, Window: 3600, Key: "ip", Routes: []string{"POST /auth/signup"}},
+			"pets":    {Limit: 100, Window: 60, Key: "api_key", Routes: []string{"GET /pets", "GET /pets/:id"}},
+		},
+	}
+	t.ip = "10.0.0.1"
+	t.userId = uuid.NewString()
+	t.apiKeyId = uuid.NewString()
+}
+
+func (t *RateLimitMiddlewareTest) newLimiter(rateLimitSvc *mock_ratelimit.MockService) ratelimitMiddleware.Limiter {
+	return ratelimitMiddleware.NewLimiter(rateLimitSvc, t.conf, constant.VersionList)
+}
+
+func (t *RateLimitMiddlewareTest) expectHeaders(context *routerMock.MockIContext, limit string, remaining string, reset string, policy string) {
+	context.EXPECT().SetHeader("RateLimit-Limit", limit)
+	context.EXPECT().SetHeader("RateLimit-Remaining", remaining)
+	context.EXPECT().SetHeader("RateLimit-Reset", reset)
+	context.EXPECT().SetHeader("RateLimit-Policy", policy)
+}
+
+func (t *RateLimitMiddlewareTest) TestRoutePolicyByIp() {
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodPost)
+	context.EXPECT().Path().Return("/v1/auth/signup")
+	context.EXPECT().IP().Return(t.ip)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	rateLimitSvc.EXPECT().Take(gomock.Any(), "signup", t.conf.Policies["signup"], "ip:"+t.ip).Return(&ratelimit.Result{
+		Allowed:   true,
+		Remaining: 4,
+		Reset:     720 * time.Second,
+	}, nil)
+	t.expectHeaders(context, "5", "4", "720", "5;w=3600")
+	context.EXPECT().Next().Return(nil)
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.Use(context)
+}
+
+func (t *RateLimitMiddlewareTest) TestDefaultPolicyByUser() {
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodPost)
+	context.EXPECT().Path().Return("/v1/images")
+	context.EXPECT().UserID().Return(t.userId)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	rateLimitSvc.EXPECT().Take(gomock.Any(), "default", t.conf.Policies["default"], "user:"+t.userId).Return(&ratelimit.Result{
+		Allowed:   true,
+		Remaining: 299,
+		Reset:     200 * time.Millisecond,
+	}, nil)
+	t.expectHeaders(context, "300", "299", "1", "300;w=60")
+	context.EXPECT().Next().Return(nil)
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.Use(context)
+}
+
+func (t *RateLimitMiddlewareTest) TestAnonymousUserByIp() {
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodGet)
+	context.EXPECT().Path().Return("/v1/user/" + t.userId)
+	context.EXPECT().UserID().Return("")
+	context.EXPECT().IP().Return(t.ip)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	rateLimitSvc.EXPECT().Take(gomock.Any(), "default", t.conf.Policies["default"], "ip:"+t.ip).Return(&ratelimit.Result{
+		Allowed:   true,
+		Remaining: 10,
+		Reset:     time.Minute,
+	}, nil)
+	t.expectHeaders(context, "300", "10", "60", "300;w=60")
+	context.EXPECT().Next().Return(nil)
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.Use(context)
+}
+
+func (t *RateLimitMiddlewareTest) TestApiKey() {
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodGet)
+	context.EXPECT().Path().Return("/v1/pets/" + uuid.NewString())
+	context.EXPECT().ApiKeyID().Return(t.apiKeyId)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	rateLimitSvc.EXPECT().Take(gomock.Any(), "pets", t.conf.Policies["pets"], "api_key:"+t.apiKeyId).Return(&ratelimit.Result{
+		Allowed:   true,
+		Remaining: 99,
+		Reset:     time.Second,
+	}, nil)
+	t.expectHeaders(context, "100", "99", "1", "100;w=60")
+	context.EXPECT().Next().Return(nil)
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.Use(context)
+}
+
+func (t *RateLimitMiddlewareTest) TestLimitReached() {
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodPost)
+	context.EXPECT().Path().Return("/v1/auth/signup")
+	context.EXPECT().IP().Return(t.ip)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	rateLimitSvc.EXPECT().Take(gomock.Any(), "signup", t.conf.Policies["signup"], "ip:"+t.ip).Return(&ratelimit.Result{
+		Allowed:    false,
+		RetryAfter: 719500 * time.Millisecond,
+		Reset:      3600 * time.Second,
+	}, nil)
+	t.expectHeaders(context, "5", "0", "3600", "5;w=3600")
+	context.EXPECT().SetHeader("Retry-After", "720")
+	context.EXPECT().JSON(http.StatusTooManyRequests, dto.TooManyRequestsError(constant.RateLimitErrorMessage, 719500*time.Millisecond))
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.Use(context)
+}
+
+func (t *RateLimitMiddlewareTest) TestRedisFailedLetsThrough() {
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodPost)
+	context.EXPECT().Path().Return("/v1/auth/signup")
+	context.EXPECT().IP().Return(t.ip)
+	context.EXPECT().UserContext().Return(ctx.Background()).Times(2)
+	rateLimitSvc.EXPECT().Take(gomock.Any(), "signup", t.conf.Policies["signup"], "ip:"+t.ip).Return(nil, errors.New("connection refused"))
+	context.EXPECT().Next().Return(nil)
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.Use(context)
+}
+
+func (t *RateLimitMiddlewareTest) TestNoPolicy() {
+	t.conf = config.RateLimit{}
+
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Method().Return(http.MethodGet)
+	context.EXPECT().Path().Return("/v1/pets")
+	context.EXPECT().Next().Return(nil)
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.Use(context)
+}
+
+func (t *RateLimitMiddlewareTest) TestBeforeGuardByIp() {
+	t.conf.Policies["guard"] = config.RateLimitPolicy{Limit: 600, Window: 60, Key: "ip"}
+
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().IP().Return(t.ip)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	rateLimitSvc.EXPECT().Take(gomock.Any(), "guard", t.conf.Policies["guard"], "ip:"+t.ip).Return(&ratelimit.Result{
+		Allowed:   true,
+		Remaining: 599,
+		Reset:     100 * time.Millisecond,
+	}, nil)
+	t.expectHeaders(context, "600", "599", "1", "600;w=60")
+	context.EXPECT().Next().Return(nil)
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.UseBeforeGuard(context)
+}
+
+func (t *RateLimitMiddlewareTest) TestBeforeGuardLimitReached() {
+	t.conf.Policies["guard"] = config.RateLimitPolicy{Limit: 600, Window: 60, Key: "ip"}
+
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().IP().Return(t.ip)
+	context.EXPECT().UserContext().Return(ctx.Background())
+	rateLimitSvc.EXPECT().Take(gomock.Any(), "guard", t.conf.Policies["guard"], "ip:"+t.ip).Return(&ratelimit.Result{
+		Allowed:    false,
+		RetryAfter: 100 * time.Millisecond,
+		Reset:      60 * time.Second,
+	}, nil)
+	t.expectHeaders(context, "600", "0", "60", "600;w=60")
+	context.EXPECT().SetHeader("Retry-After", "1")
+	context.EXPECT().JSON(http.StatusTooManyRequests, dto.TooManyRequestsError(constant.RateLimitErrorMessage, 100*time.Millisecond))
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.UseBeforeGuard(context)
+}
+
+func (t *RateLimitMiddlewareTest) TestBeforeGuardWithoutPolicy() {
+	controller := gomock.NewController(t.T())
+	rateLimitSvc := mock_ratelimit.NewMockService(controller)
+	context := routerMock.NewMockIContext(controller)
+
+	context.EXPECT().Next().Return(nil)
+
+	limiter := t.newLimiter(rateLimitSvc)
+	limiter.UseBeforeGuard(context)
+}
+
+// rejectGuard rejects every request like the guard does for a protected route without a token
+type rejectGuard struct{}
+
+func (rejectGuard) Use(ctx router.IContext) error {
+	ctx.JSON(http.StatusUnauthorized, dto.UnauthorizedError(constant.InvalidTokenErrorMessage))
+	return nil
+}
+
+// sendForwardedFor sends requests through the api with a different X-Forwarded-For each time
+func (t *RateLimitMiddlewareTest) sendForwardedFor(trustedProxies []string, forwardedFor string, requests int) []int {
+	server := miniredis.RunT(t.T())
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	conf := config.RateLimit{Policies: map[string]config.RateLimitPolicy{
+		"guard": {Limit: 2, Window: 60, Key: "ip"},
+	}}
+	limiter := ratelimitMiddleware.NewLimiter(ratelimit.NewService(client), conf, constant.VersionList)
+	appConf := config.App{
+		MaxFileSize:    1,
+		RequestTimeout: 10,
+		ProxyHeader:    "X-Forwarded-For",
+		TrustedProxies: trustedProxies,
+	}
+	app := router.NewAPIv1(ctx.Background(), router.NewFiberRouter(rejectGuard{}, &limiter, appConf), appConf)
+
+	var statuses []int
+	for i := 0; i < requests; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/v1/pets/admin", nil)
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf(forwardedFor, i+1))
+		resp, err := app.Test(req)
+		assert.Nil(t.T(), err)
+		statuses = append(statuses, resp.StatusCode)
+	}
+
+	return statuses
+}
+
+func (t *RateLimitMiddlewareTest) TestForgedForwardedForIsLimited() {
+	statuses := t.sendForwardedFor([]string{"10.0.0.1"}, "203.0.113.%d", 3)
+
+	assert.Equal(t.T(), []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, statuses)
+}
+
+func (t *RateLimitMiddlewareTest) TestForwardedForOfTrustedProxy() {
+	statuses := t.sendForwardedFor([]string{"0.0.0.0/0"}, "203.0.113.%d", 3)
+
+	assert.Equal(t.T(), []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized}, statuses)
+}
+
+// the proxy appends the address it got the request from, the entries the client sends in front of it are ignored
+func (t *RateLimitMiddlewareTest) TestSpoofedForwardedForIsLimited() {
+	statuses := t.sendForwardedFor([]string{"0.0.0.0"}, "203.0.113.%d, 198.51.100.7", 3)
+
+	assert.Equal(t.T(), []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, statuses)
+}
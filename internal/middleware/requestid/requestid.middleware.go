@@ -0,0 +1,23 @@
+package requestid
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/isd-sgcu/johnjud-backend/internal/requestid"
+)
+
+// New keeps the X-Request-ID of the caller, or assigns a new one when it is missing or malformed. The id is
+// returned in the response, stored in the locals for IContext and in the user context for the service logs
+func New() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(requestid.Header)
+		if !requestid.IsValid(id) {
+			id = requestid.New()
+		}
+
+		c.Locals("RequestId", id)
+		c.Set(requestid.Header, id)
+		c.SetUserContext(requestid.WithContext(c.UserContext(), id))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	requestIdMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/requestid"
+	"github.com/isd-sgcu/johnjud-backend/internal/requestid"
+	"github.com/isd-sgcu/johnjud-backend/internal/router"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RequestIdMiddlewareTest struct {
+	suite.Suite
+	app *fiber.App
+}
+
+func TestRequestIdMiddleware(t *testing.T) {
+	suite.Run(t, new(RequestIdMiddlewareTest))
+}
+
+func (t *RequestIdMiddlewareTest) SetupTest() {
+	t.app = fiber.New()
+	t.app.Use(requestIdMiddleware.New())
+	t.app.Get("/", func(c *fiber.Ctx) error {
+		// the handler echoes both places the id is stored in so the tests can compare them with the header
+		return c.SendString(router.NewFiberCtx(c).RequestID() + " " + requestid.FromContext(c.UserContext()))
+	})
+}
+
+func (t *RequestIdMiddlewareTest) TestPropagate() {
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(requestid.Header, "edge-7f3c2a:1")
+
+	id, body := t.send(req)
+
+	assert.Equal(t.T(), "edge-7f3c2a:1", id)
+	assert.Equal(t.T(), "edge-7f3c2a:1 edge-7f3c2a:1", body)
+}
+
+func (t *RequestIdMiddlewareTest) TestAssign() {
+	id, body := t.send(httptest.NewRequest(fiber.MethodGet, "/", nil))
+
+	assert.Len(t.T(), id, 36)
+	assert.Equal(t.T(), id+" "+id, body)
+}
+
+func (t *RequestIdMiddlewareTest) TestReplaceInvalid() {
+	for _, invalid := range []string{"id with spaces", "id\"}{\"admin\":true", strings.Repeat("a", 129)} {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(requestid.Header, invalid)
+
+		id, body := t.send(req)
+
+		assert.NotEqual(t.T(), invalid, id)
+		assert.True(t.T(), requestid.IsValid(id))
+		assert.Equal(t.T(), id+" "+id, body)
+	}
+}
+
+func (t *RequestIdMiddlewareTest) send(req *http.Request) (string, string) {
+	res, err := t.app.Test(req)
+	assert.Nil(t.T(), err)
+
+	body, err := io.ReadAll(res.Body)
+	assert.Nil(t.T(), err)
+
+	return res.Header.Get(requestid.Header), string(body)
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	tracingMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/tracing"
+	"github.com/isd-sgcu/johnjud-backend/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceId      = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentSpanId = "00f067aa0ba902b7"
+)
+
+type TracingMiddlewareTest struct {
+	suite.Suite
+	recorder *tracetest.SpanRecorder
+	app      *fiber.App
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	suite.Run(t, new(TracingMiddlewareTest))
+}
+
+func (t *TracingMiddlewareTest) SetupTest() {
+	t.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(t.recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	r := fiber.New()
+	r.Get("/pets/:id", func(c *fiber.Ctx) error {
+		_, span := tracing.Start(c.UserContext(), "pet.FindOne")
+		span.End()
+		return c.SendStatus(fiber.StatusOK)
+	})
+	r.Get("/broken", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	})
+
+	t.app = fiber.New()
+	t.app.Use(tracingMiddleware.New())
+	t.app.Mount("/v1", r)
+}
+
+func (t *TracingMiddlewareTest) TestContinueTrace() {
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/pets/5c4a2a1e-3a1c-4f43-9f0e-1c2f6d0c3a11", nil)
+	req.Header.Set("traceparent", "00-"+traceId+"-"+parentSpanId+"-01")
+
+	_, err := t.app.Test(req)
+	assert.Nil(t.T(), err)
+
+	spans := t.recorder.Ended()
+	assert.Len(t.T(), spans, 2)
+
+	service, server := spans[0], spans[1]
+	assert.Equal(t.T(), "GET /v1/pets/:id", server.Name())
+	assert.Equal(t.T(), trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t.T(), traceId, server.SpanContext().TraceID().String())
+	assert.Equal(t.T(), parentSpanId, server.Parent().SpanID().String())
+
+	assert.Equal(t.T(), "pet.FindOne", service.Name())
+	assert.Equal(t.T(), server.SpanContext().SpanID(), service.Parent().SpanID())
+}
+
+func (t *TracingMiddlewareTest) TestNewTrace() {
+	_, err := t.app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/pets/5c4a2a1e-3a1c-4f43-9f0e-1c2f6d0c3a11", nil))
+	assert.Nil(t.T(), err)
+
+	server := t.recorder.Ended()[1]
+	assert.True(t.T(), server.SpanContext().IsValid())
+	assert.False(t.T(), server.Parent().IsValid())
+}
+
+func (t *TracingMiddlewareTest) TestServerError() {
+	_, err := t.app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/broken", nil))
+	assert.Nil(t.T(), err)
+
+	server := t.recorder.Ended()[0]
+	assert.Equal(t.T(), "GET /v1/broken", server.Name())
+	assert.Equal(t.T(), codes.Error, server.Status().Code)
+}
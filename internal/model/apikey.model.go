@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApiKey is used by partners for server to server access, only the sha256 hash of the key is stored
+type ApiKey struct {
+	Base
+	Name string `json:"name" gorm:"tinytext"`
+	// Prefix is the start of the key shown to admins so a key can be recognized
+	Prefix  string `json:"prefix" gorm:"tinytext"`
+	KeyHash string `json:"-" gorm:"type:varchar(64);uniqueIndex"`
+	// Scopes are separated by spaces
+	Scopes     string     `json:"scopes" gorm:"tinytext"`
+	RateLimit  int        `json:"rate_limit"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at" gorm:"type:timestamp"`
+	LastUsedAt *time.Time `json:"last_used_at" gorm:"type:timestamp"`
+	RevokedAt  *time.Time `json:"revoked_at" gorm:"type:timestamp"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+)
+
+// EmailOutbox is a rendered email waiting to be delivered by the outbox worker
+type EmailOutbox struct {
+	Base
+	Template      constant.EmailTemplate `json:"template" gorm:"tinytext"`
+	FromName      string                 `json:"from_name" gorm:"tinytext"`
+	FromAddress   string                 `json:"from_address" gorm:"tinytext"`
+	ToName        string                 `json:"to_name" gorm:"tinytext"`
+	ToAddress     string                 `json:"to_address" gorm:"tinytext"`
+	Subject       string                 `json:"subject" gorm:"mediumtext"`
+	Text          string                 `json:"text" gorm:"text"`
+	Html          string                 `json:"html" gorm:"text"`
+	Status        constant.EmailStatus   `json:"status" gorm:"tinytext;index;default:pending"`
+	Attempts      int                    `json:"attempts" gorm:"default:0"`
+	NextAttemptAt time.Time              `json:"next_attempt_at" gorm:"type:timestamp;index"`
+	LastError     string                 `json:"last_error" gorm:"mediumtext"`
+	SentAt        *time.Time             `json:"sent_at" gorm:"type:timestamp"`
+}
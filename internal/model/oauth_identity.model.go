@@ -0,0 +1,12 @@
+package model
+
+import "github.com/google/uuid"
+
+// OAuthIdentity links an account of an oauth provider to a user
+type OAuthIdentity struct {
+	Base
+	UserID   uuid.UUID `json:"user_id" gorm:"index"`
+	Provider string    `json:"provider" gorm:"type:varchar(32);uniqueIndex:idx_oauth_provider_subject"`
+	Subject  string    `json:"subject" gorm:"type:varchar(255);uniqueIndex:idx_oauth_provider_subject"`
+	Email    string    `json:"email" gorm:"tinytext"`
+}
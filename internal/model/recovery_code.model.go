@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode is a one-time 2fa code, only its sha256 hash is stored
+type RecoveryCode struct {
+	Base
+	UserID   uuid.UUID  `json:"user_id" gorm:"index"`
+	CodeHash string     `json:"-" gorm:"tinytext"`
+	UsedAt   *time.Time `json:"used_at" gorm:"type:timestamp"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+)
+
+// SecurityEvent is an audit record of an authentication action, UserID is nil when the email is not of any user
+type SecurityEvent struct {
+	Base
+	UserID    *uuid.UUID                 `json:"user_id" gorm:"index"`
+	Email     string                     `json:"email" gorm:"tinytext"`
+	Type      constant.SecurityEventType `json:"type" gorm:"tinytext;index"`
+	IP        string                     `json:"ip" gorm:"tinytext"`
+	UserAgent string                     `json:"user_agent" gorm:"text"`
+	Success   bool                       `json:"success"`
+}
@@ -4,9 +4,15 @@ import "github.com/isd-sgcu/johnjud-backend/constant"
 
 type User struct {
 	Base
-	Email     string        `json:"email" gorm:"tinytext;unique"`
-	Password  string        `json:"password" gorm:"tinytext"`
-	Firstname string        `json:"firstname" gorm:"tinytext"`
-	Lastname  string        `json:"lastname" gorm:"tinytext"`
-	Role      constant.Role `json:"role" gorm:"tinytext"`
+	Email       string          `json:"email" gorm:"tinytext;unique"`
+	Password    string          `json:"password" gorm:"tinytext"`
+	Firstname   string          `json:"firstname" gorm:"tinytext"`
+	Lastname    string          `json:"lastname" gorm:"tinytext"`
+	Role        constant.Role   `json:"role" gorm:"tinytext"`
+	IsSuspended bool            `json:"is_suspended" gorm:"default:false"`
+	Locale      constant.Locale `json:"locale" gorm:"tinytext;default:en"`
+	// TotpSecret is set on enrollment and only used once TotpEnabled is true
+	TotpSecret   string `json:"-" gorm:"tinytext"`
+	TotpEnabled  bool   `json:"totp_enabled" gorm:"default:false"`
+	TotpLastStep int64  `json:"-" gorm:"default:0"`
 }
@@ -0,0 +1,190 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+)
+
+// common.txt holds the sha1 of the most common passwords so the check works without a downloaded list
+//
+//go:embed common.txt
+var commonList string
+
+const (
+	prefixLength = 5
+	// the binary search of a sorted file stops once the range is this small and reads the rest line by line
+	searchBlockSize = 4096
+)
+
+// BreachedList answers k-anonymity range queries: it gets the first 5 hex characters of the sha1 of a password
+// and returns the suffixes of the breached hashes with that prefix, the same as the Pwned Passwords range api
+type BreachedList interface {
+	Range(prefix string) (map[string]struct{}, error)
+}
+
+type localList struct {
+	hashes map[string]map[string]struct{}
+}
+
+// sortedFile searches a file sorted by hash on disk, so even the full Pwned Passwords list is never held in memory
+type sortedFile struct {
+	file *os.File
+	size int64
+}
+
+type breachedLists []BreachedList
+
+// LoadBreachedList reads the embedded common passwords and opens the file at path when it is set. The file has one
+// sha1 per line sorted by hash, optionally followed by ":count", as in the Pwned Passwords downloads ordered by hash.
+// Only the lines of a prefix are read on each check, the file is kept open for the lifetime of the list
+func LoadBreachedList(path string) (BreachedList, error) {
+	common := &localList{hashes: map[string]map[string]struct{}{}}
+	if err := common.read(strings.NewReader(commonList)); err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return common, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return breachedLists{common, &sortedFile{file: file, size: info.Size()}}, nil
+}
+
+func (l breachedLists) Range(prefix string) (map[string]struct{}, error) {
+	result := map[string]struct{}{}
+	for _, list := range l {
+		suffixes, err := list.Range(prefix)
+		if err != nil {
+			return nil, err
+		}
+		for suffix := range suffixes {
+			result[suffix] = struct{}{}
+		}
+	}
+
+	return result, nil
+}
+
+func (l *localList) Range(prefix string) (map[string]struct{}, error) {
+	return l.hashes[strings.ToUpper(prefix)], nil
+}
+
+func (l *localList) read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		hash, ok := parseHash(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+		if l.hashes[prefix] == nil {
+			l.hashes[prefix] = map[string]struct{}{}
+		}
+		l.hashes[prefix][suffix] = struct{}{}
+	}
+
+	return scanner.Err()
+}
+
+// Range binary searches the byte offsets of the file for the first line of the prefix, then reads the lines of
+// the prefix. lo is always the start of a line before the first line of the prefix
+func (f *sortedFile) Range(prefix string) (map[string]struct{}, error) {
+	prefix = strings.ToUpper(prefix)
+
+	lo, hi := int64(0), f.size
+	for hi-lo > searchBlockSize {
+		mid := lo + (hi-lo)/2
+		next, line, err := f.lineAfter(mid)
+		if err != nil {
+			return nil, err
+		}
+		if hash, ok := parseHash(line); ok && hash[:prefixLength] < prefix {
+			lo = next
+		} else {
+			hi = mid
+		}
+	}
+
+	result := map[string]struct{}{}
+	reader := bufio.NewReader(io.NewSectionReader(f.file, lo, f.size-lo))
+	for {
+		line, err := reader.ReadString('\n')
+		if hash, ok := parseHash(line); ok {
+			if hash[:prefixLength] > prefix {
+				break
+			}
+			if hash[:prefixLength] == prefix {
+				result[hash[prefixLength:]] = struct{}{}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// lineAfter returns the start of the first line after offset and the content of that line
+func (f *sortedFile) lineAfter(offset int64) (int64, string, error) {
+	reader := bufio.NewReader(io.NewSectionReader(f.file, offset, f.size-offset))
+	skipped, err := reader.ReadString('\n')
+	if err == io.EOF {
+		return f.size, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, "", err
+	}
+
+	return offset + int64(len(skipped)), line, nil
+}
+
+// parseHash returns the uppercase sha1 of a line, without the count and the line ending
+func parseHash(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+	if len(line) != sha1.Size*2 {
+		return "", false
+	}
+
+	return strings.ToUpper(line), true
+}
+
+// IsBreached only gives the prefix of the hash to the list, never the password or the full hash
+func IsBreached(list BreachedList, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := list.Range(hash[:prefixLength])
+	if err != nil {
+		return false, err
+	}
+	_, ok := suffixes[hash[prefixLength:]]
+
+	return ok, nil
+}
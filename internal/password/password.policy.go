@@ -0,0 +1,88 @@
+package password
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxBytes is the limit of bcrypt, the bytes after it would be ignored
+const MaxBytes = 72
+
+type Checker interface {
+	// Check returns the reasons the password is rejected, none when it is accepted
+	Check(password string) []string
+}
+
+type policyImpl struct {
+	config   config.Password
+	breached BreachedList
+}
+
+func NewPolicy(config config.Password, breached BreachedList) Checker {
+	return &policyImpl{config: config, breached: breached}
+}
+
+// DefaultPolicy has the defaults of the password config and no breached list
+func DefaultPolicy() Checker {
+	return NewPolicy(config.Password{MinLength: 8, MaxLength: 64, RequireLower: true, RequireDigit: true}, nil)
+}
+
+func (p *policyImpl) Check(password string) []string {
+	var reasons []string
+
+	length := utf8.RuneCountInString(password)
+	if length < p.config.MinLength {
+		reasons = append(reasons, fmt.Sprintf("password must be at least %d characters", p.config.MinLength))
+	}
+	if p.config.MaxLength > 0 && length > p.config.MaxLength {
+		reasons = append(reasons, fmt.Sprintf("password must be at most %d characters", p.config.MaxLength))
+	} else if len(password) > MaxBytes {
+		reasons = append(reasons, fmt.Sprintf("password must be at most %d bytes", MaxBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.config.RequireUpper && !hasUpper {
+		reasons = append(reasons, "password must contain an uppercase letter")
+	}
+	if p.config.RequireLower && !hasLower {
+		reasons = append(reasons, "password must contain a lowercase letter")
+	}
+	if p.config.RequireDigit && !hasDigit {
+		reasons = append(reasons, "password must contain a digit")
+	}
+	if p.config.RequireSymbol && !hasSymbol {
+		reasons = append(reasons, "password must contain a symbol")
+	}
+
+	// the breach check is skipped for passwords that are already rejected
+	if len(reasons) == 0 && p.breached != nil {
+		breached, err := IsBreached(p.breached, password)
+		if err != nil {
+			log.Error().Err(err).
+				Str("service", "password").
+				Str("module", "check").
+				Msg("Error checking breached passwords")
+		}
+		if breached {
+			reasons = append(reasons, "password has appeared in a data breach, choose a different one")
+		}
+	}
+
+	return reasons
+}
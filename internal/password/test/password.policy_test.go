@@ -0,0 +1,130 @@
+package test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/internal/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordPolicyTest struct {
+	suite.Suite
+	config   config.Password
+	breached password.BreachedList
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	suite.Run(t, new(PasswordPolicyTest))
+}
+
+func (t *PasswordPolicyTest) SetupTest() {
+	t.config = config.Password{
+		MinLength:     8,
+		MaxLength:     64,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+	t.breached, _ = password.LoadBreachedList("")
+}
+
+func (t *PasswordPolicyTest) TestAccepted() {
+	policy := password.NewPolicy(t.config, t.breached)
+
+	assert.Empty(t.T(), policy.Check("Adopt-a-cat 2024"))
+}
+
+func (t *PasswordPolicyTest) TestLongPassphrase() {
+	policy := password.NewPolicy(t.config, t.breached)
+
+	assert.Empty(t.T(), policy.Check("Correct horse battery staple, 4 adopted dogs!"))
+}
+
+func (t *PasswordPolicyTest) TestSpecificReasons() {
+	policy := password.NewPolicy(t.config, t.breached)
+
+	reasons := policy.Check("abc")
+
+	assert.Equal(t.T(), []string{
+		"password must be at least 8 characters",
+		"password must contain an uppercase letter",
+		"password must contain a digit",
+		"password must contain a symbol",
+	}, reasons)
+}
+
+func (t *PasswordPolicyTest) TestTooLong() {
+	policy := password.NewPolicy(t.config, t.breached)
+
+	reasons := policy.Check("Aa1!" + strings.Repeat("x", 61))
+
+	assert.Equal(t.T(), []string{"password must be at most 64 characters"}, reasons)
+}
+
+func (t *PasswordPolicyTest) TestOverBcryptLimit() {
+	t.config.MaxLength = 0
+	policy := password.NewPolicy(t.config, t.breached)
+
+	// thai characters take 3 bytes each
+	reasons := policy.Check("Aa1!" + strings.Repeat("ก", 25))
+
+	assert.Equal(t.T(), []string{"password must be at most 72 bytes"}, reasons)
+}
+
+func (t *PasswordPolicyTest) TestBreachedCommonPassword() {
+	t.config.RequireUpper = false
+	t.config.RequireSymbol = false
+	policy := password.NewPolicy(t.config, t.breached)
+
+	reasons := policy.Check("password123")
+
+	assert.Equal(t.T(), []string{"password has appeared in a data breach, choose a different one"}, reasons)
+}
+
+func (t *PasswordPolicyTest) TestBreachedListFile() {
+	// large enough that the lines are found by the binary search and not only by the read of the last block
+	var secrets, lines []string
+	for i := 0; i < 2000; i++ {
+		secret := fmt.Sprintf("Johnjud-Shelter-%d", i)
+		sum := sha1.Sum([]byte(secret))
+		secrets = append(secrets, secret)
+		lines = append(lines, fmt.Sprintf("%s:%d\r\n", strings.ToUpper(hex.EncodeToString(sum[:])), i+1))
+	}
+	sort.Strings(lines)
+	path := filepath.Join(t.T().TempDir(), "pwned.txt")
+	assert.Nil(t.T(), os.WriteFile(path, []byte(strings.Join(lines, "")), 0o600))
+
+	list, err := password.LoadBreachedList(path)
+	assert.Nil(t.T(), err)
+
+	for _, secret := range secrets {
+		breached, err := password.IsBreached(list, secret)
+		assert.Nil(t.T(), err)
+		assert.True(t.T(), breached, secret)
+	}
+
+	breached, err := password.IsBreached(list, "password123")
+	assert.Nil(t.T(), err)
+	assert.True(t.T(), breached)
+
+	breached, err = password.IsBreached(list, "Adopt-a-cat 2024")
+	assert.Nil(t.T(), err)
+	assert.False(t.T(), breached)
+}
+
+func (t *PasswordPolicyTest) TestBreachedListFileNotFound() {
+	list, err := password.LoadBreachedList(filepath.Join(t.T().TempDir(), "missing.txt"))
+
+	assert.Nil(t.T(), list)
+	assert.NotNil(t.T(), err)
+}
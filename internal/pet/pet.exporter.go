@@ -0,0 +1,250 @@
+package pet
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/rs/zerolog/log"
+	"github.com/skip2/go-qrcode"
+)
+
+// the header uses the same column names as the import so an export can be imported back,
+// the id and images columns are ignored by the import and a missing pattern is defaulted
+var exportColumns = []string{
+	"id", "type", "name", "birthdate", "gender", "color", "habit", "caption", "status",
+	"is_sterile", "is_vaccinated", "is_visible", "origin", "owner", "contact", "tel", "images",
+}
+
+const (
+	cardWidth   = 190.0
+	cardHeight  = 132.0
+	cardMargin  = 10.0
+	cardPadding = 5.0
+	photoSize   = 85.0
+	qrSize      = 32.0
+)
+
+func ExportCsv(pets []*dto.PetResponse) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	writer := csv.NewWriter(buf)
+
+	if err := writer.Write(exportColumns); err != nil {
+		return nil, err
+	}
+
+	for _, p := range pets {
+		record := []string{
+			p.Id,
+			p.Type,
+			p.Name,
+			p.Birthdate,
+			string(p.Gender),
+			p.Color,
+			p.Habit,
+			p.Caption,
+			string(p.Status),
+			formatBool(p.IsSterile),
+			formatBool(p.IsVaccinated),
+			formatBool(p.IsVisible),
+			p.Origin,
+			p.Owner,
+			p.Contact,
+			p.Tel,
+			strings.Join(ExtractImageUrls(p.Images), " "),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ExportPdf prints one card per pet, two cards on each A4 page. photos maps pet id to the data of its primary image.
+// A utf-8 font is needed to print thai names, otherwise the core Helvetica font is used.
+func ExportPdf(ctx context.Context, pets []*dto.PetResponse, photos map[string][]byte, pageURL string, fontPath string) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(cardMargin, cardMargin, cardMargin)
+	pdf.SetAutoPageBreak(false, cardMargin)
+	pdf.SetTitle("JohnJud pets", true)
+
+	family, boldStyle := "Helvetica", "B"
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	if fontPath != "" {
+		family, boldStyle = "export", ""
+		translate = func(s string) string { return s }
+		pdf.AddUTF8Font(family, "", fontPath)
+	}
+
+	for i, p := range pets {
+		if i%2 == 0 {
+			pdf.AddPage()
+		}
+		top := cardMargin + float64(i%2)*(cardHeight+6)
+
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.Rect(cardMargin, top, cardWidth, cardHeight, "D")
+
+		if photo, ok := photos[p.Id]; ok {
+			drawPhoto(ctx, pdf, "photo-"+p.Id, photo, cardMargin+cardPadding, top+cardPadding)
+		}
+
+		left := cardMargin + cardPadding*2 + photoSize
+		pdf.SetXY(left, top+cardPadding)
+		pdf.SetFont(family, boldStyle, 18)
+		pdf.CellFormat(0, 10, translate(p.Name), "", 1, "L", false, 0, "")
+
+		pdf.SetFont(family, "", 11)
+		for _, attr := range cardAttributes(p) {
+			pdf.SetX(left)
+			pdf.CellFormat(0, 6.5, translate(attr), "", 1, "L", false, 0, "")
+		}
+
+		if p.Caption != "" {
+			pdf.SetXY(cardMargin+cardPadding, top+cardPadding*2+photoSize)
+			pdf.MultiCell(cardWidth-cardPadding*3-qrSize, 5, translate(truncate(p.Caption, 280)), "", "L", false)
+		}
+
+		if pageURL != "" {
+			url := fmt.Sprintf("%s/%s", strings.TrimSuffix(pageURL, "/"), p.Id)
+			drawQrCode(ctx, pdf, "qr-"+p.Id, url, cardMargin+cardWidth-cardPadding-qrSize, top+cardHeight-cardPadding-qrSize)
+		}
+
+		if err := pdf.Error(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(pets) == 0 {
+		pdf.AddPage()
+		pdf.SetFont(family, "", 12)
+		pdf.CellFormat(0, 10, translate("No pets found"), "", 1, "C", false, 0, "")
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := pdf.Output(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func cardAttributes(p *dto.PetResponse) []string {
+	attrs := []string{
+		"Type: " + p.Type,
+		"Gender: " + string(p.Gender),
+		"Birthdate: " + formatBirthdate(p.Birthdate),
+		"Color: " + p.Color,
+		"Status: " + string(p.Status),
+		"Sterilized: " + formatYesNo(p.IsSterile),
+		"Vaccinated: " + formatYesNo(p.IsVaccinated),
+		"Origin: " + p.Origin,
+	}
+	if p.Contact != "" {
+		attrs = append(attrs, "Contact: "+p.Contact)
+	}
+	if p.Tel != "" {
+		attrs = append(attrs, "Tel: "+p.Tel)
+	}
+
+	return attrs
+}
+
+// drawPhoto fits the image into the photo box. Broken images are skipped so one bad file does not fail the export.
+func drawPhoto(ctx context.Context, pdf *fpdf.Fpdf, name string, data []byte, x, y float64) {
+	imageType := pdfImageType(data)
+	if imageType == "" {
+		return
+	}
+
+	info := pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(data))
+	if err := pdf.Error(); err != nil || info == nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "pet").
+			Str("module", "export").
+			Str("image", name).
+			Msg("Skipping image that cannot be printed")
+		pdf.ClearError()
+		return
+	}
+
+	w, h := info.Width(), info.Height()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	scale := photoSize / w
+	if h*scale > photoSize {
+		scale = photoSize / h
+	}
+	pdf.ImageOptions(name, x, y, w*scale, h*scale, false, fpdf.ImageOptions{ImageType: imageType}, 0, "")
+}
+
+func drawQrCode(ctx context.Context, pdf *fpdf.Fpdf, name string, url string, x, y float64) {
+	png, err := qrcode.Encode(url, qrcode.Medium, 256)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "pet").
+			Str("module", "export").
+			Str("url", url).
+			Msg("Error encoding qr code")
+		return
+	}
+
+	pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	pdf.ImageOptions(name, x, y, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, url)
+}
+
+func pdfImageType(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return "JPG"
+	case "image/png":
+		return "PNG"
+	case "image/gif":
+		return "GIF"
+	default:
+		return ""
+	}
+}
+
+func formatBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+func formatYesNo(b *bool) string {
+	if b != nil && *b {
+		return "yes"
+	}
+	return "no"
+}
+
+func formatBirthdate(birthdate string) string {
+	t, err := parseDate(birthdate)
+	if err != nil {
+		return birthdate
+	}
+	return t.Format("2 Jan 2006")
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
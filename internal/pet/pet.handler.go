@@ -2,6 +2,7 @@ package pet
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/isd-sgcu/johnjud-backend/constant"
@@ -9,16 +10,23 @@ import (
 	"github.com/isd-sgcu/johnjud-backend/internal/image"
 	"github.com/isd-sgcu/johnjud-backend/internal/router"
 	"github.com/isd-sgcu/johnjud-backend/internal/validator"
+	"github.com/rs/zerolog/log"
 )
 
 type handlerImpl struct {
 	service      Service
 	imageService image.Service
 	validate     validator.IDtoValidator
+	maxFileSize  int64
 }
 
-func NewHandler(service Service, imageService image.Service, validate validator.IDtoValidator) *handlerImpl {
-	return &handlerImpl{service, imageService, validate}
+func NewHandler(service Service, imageService image.Service, validate validator.IDtoValidator, maxFileSize int64) *handlerImpl {
+	return &handlerImpl{
+		service:      service,
+		imageService: imageService,
+		validate:     validate,
+		maxFileSize:  int64(maxFileSize * 1024 * 1024),
+	}
 }
 
 // FindAll is a function that returns all VISIBLE pets in database
@@ -38,7 +46,7 @@ func (h *handlerImpl) FindAll(c router.IContext) {
 		c.JSON(http.StatusBadRequest, err)
 	}
 
-	response, respErr := h.service.FindAll(request, false)
+	response, respErr := h.service.FindAll(c.UserContext(), request, false)
 	if respErr != nil {
 		c.JSON(respErr.StatusCode, respErr)
 		return
@@ -64,7 +72,7 @@ func (h *handlerImpl) FindAllAdmin(c router.IContext) {
 		c.JSON(http.StatusBadRequest, err)
 	}
 
-	response, respErr := h.service.FindAll(request, true)
+	response, respErr := h.service.FindAll(c.UserContext(), request, true)
 	if respErr != nil {
 		c.JSON(respErr.StatusCode, respErr)
 		return
@@ -73,6 +81,44 @@ func (h *handlerImpl) FindAllAdmin(c router.IContext) {
 	c.JSON(http.StatusOK, response)
 }
 
+// Export is a function that exports the filtered pets to a csv or printable pdf file
+// @Summary exports pets
+// @Description Returns a csv file or a pdf with one card per pet including its primary image and a qr code to the pet page. Accepts the same filters as finding all pets.
+// @Param format query string false "csv or pdf" default(csv)
+// @Tags pet
+// @Produce text/csv
+// @Produce application/pdf
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid query"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/pets/admin/export [get]
+func (h *handlerImpl) Export(c router.IContext) {
+	queries := c.Queries()
+	query, err := QueriesToFindAllDto(queries)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	format := constant.ExportFormat(strings.ToLower(queries["format"]))
+	if format == "" {
+		format = constant.CSV
+	}
+
+	response, respErr := h.service.Export(c.UserContext(), &dto.ExportPetRequest{Format: format, Query: query})
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	c.Attachment(response.Filename, response.ContentType, response.Data)
+}
+
 // FindOne is a function that returns a pet by id in database
 // @Summary finds one pet
 // @Description Returns the data of a pet if successful
@@ -96,7 +142,7 @@ func (h *handlerImpl) FindOne(c router.IContext) {
 		return
 	}
 
-	response, respErr := h.service.FindOne(id)
+	response, respErr := h.service.FindOne(c.UserContext(), id)
 	if respErr != nil {
 		c.JSON(respErr.StatusCode, respErr)
 		return
@@ -129,7 +175,7 @@ func (h *handlerImpl) Create(c router.IContext) {
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
 		var errorMessage []string
 		for _, reqErr := range err {
 			errorMessage = append(errorMessage, reqErr.Message)
@@ -142,7 +188,7 @@ func (h *handlerImpl) Create(c router.IContext) {
 		return
 	}
 
-	response, respErr := h.service.Create(request)
+	response, respErr := h.service.Create(c.UserContext(), request)
 	if respErr != nil {
 		c.JSON(respErr.StatusCode, respErr)
 		return
@@ -187,7 +233,7 @@ func (h *handlerImpl) Update(c router.IContext) {
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
 		var errorMessage []string
 		for _, reqErr := range err {
 			errorMessage = append(errorMessage, reqErr.Message)
@@ -200,7 +246,7 @@ func (h *handlerImpl) Update(c router.IContext) {
 		return
 	}
 
-	pet, errRes := h.service.Update(petId, request)
+	pet, errRes := h.service.Update(c.UserContext(), petId, request)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -245,7 +291,7 @@ func (h *handlerImpl) ChangeView(c router.IContext) {
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
 		var errorMessage []string
 		for _, reqErr := range err {
 			errorMessage = append(errorMessage, reqErr.Message)
@@ -258,7 +304,7 @@ func (h *handlerImpl) ChangeView(c router.IContext) {
 		return
 	}
 
-	res, errRes := h.service.ChangeView(id, request)
+	res, errRes := h.service.ChangeView(c.UserContext(), id, request)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -290,7 +336,7 @@ func (h *handlerImpl) Delete(c router.IContext) {
 		return
 	}
 
-	res, errRes := h.service.Delete(id)
+	res, errRes := h.service.Delete(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -334,7 +380,7 @@ func (h *handlerImpl) Adopt(c router.IContext) {
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
 		var errorMessage []string
 		for _, reqErr := range err {
 			errorMessage = append(errorMessage, reqErr.Message)
@@ -347,7 +393,7 @@ func (h *handlerImpl) Adopt(c router.IContext) {
 		return
 	}
 
-	res, errRes := h.service.Adopt(petId, request)
+	res, errRes := h.service.Adopt(c.UserContext(), petId, request)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -355,3 +401,67 @@ func (h *handlerImpl) Adopt(c router.IContext) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+// Import is a function that creates pets from the rows of a csv or xlsx file
+// @Summary imports pets
+// @Description Validates every row like creating a pet and returns the errors of each row. Valid rows are created in one transaction unless dry_run is true.
+// @Param file formData file true "csv or xlsx file with a header row"
+// @Param dry_run formData bool false "only validate the rows"
+// @Tags pet
+// @Accept multipart/form-data
+// @Produce json
+// @Success 200 {object} dto.ImportPetResponse
+// @Success 201 {object} dto.ImportPetResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid file"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/pets/import [post]
+func (h *handlerImpl) Import(c router.IContext) {
+	file, err := c.File("file", constant.AllowImportContentType, h.maxFileSize)
+	if err != nil {
+		log.Error().
+			Ctx(c.UserContext()).
+			Err(err).
+			Str("service", "pet").
+			Str("module", "import").
+			Msg("Invalid content")
+		c.JSON(http.StatusBadRequest, dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.InvalidContentMessage,
+			Data:       nil,
+		})
+		return
+	}
+
+	dryRun := false
+	if value := c.GetFormData("dry_run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ResponseErr{
+				StatusCode: http.StatusBadRequest,
+				Message:    constant.InvalidArgumentMessage,
+				Data:       nil,
+			})
+			return
+		}
+	}
+
+	request := &dto.ImportPetRequest{
+		Filename: file.Filename,
+		Data:     file.Data,
+		DryRun:   dryRun,
+	}
+
+	response, respErr := h.service.Import(c.UserContext(), request)
+	if respErr != nil {
+		c.JSON(respErr.StatusCode, respErr)
+		return
+	}
+
+	if response.Created > 0 {
+		c.JSON(http.StatusCreated, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,151 @@
+package pet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/xuri/excelize/v2"
+)
+
+var importStringColumns = map[string]struct{}{
+	"type":      {},
+	"name":      {},
+	"birthdate": {},
+	"gender":    {},
+	"color":     {},
+	"pattern":   {},
+	"habit":     {},
+	"caption":   {},
+	"status":    {},
+	"origin":    {},
+	"owner":     {},
+	"contact":   {},
+	"tel":       {},
+}
+
+// the pattern is required by the create request but is not stored, and the export has no pattern column,
+// so a row without one is given this value rather than failing
+const importDefaultPattern = "-"
+
+var importBoolColumns = map[string]struct{}{
+	"is_sterile":    {},
+	"is_vaccinated": {},
+	"is_visible":    {},
+}
+
+// ParseImportFile reads every row of a csv or xlsx file, including the header row
+func ParseImportFile(filename string, data []byte) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return parseCsv(data)
+	case ".xlsx":
+		return parseXlsx(data)
+	default:
+		return nil, fmt.Errorf("unsupported file type: %s", filepath.Ext(filename))
+	}
+}
+
+// ImportRowToDto maps a row onto the create request using the header row as json keys,
+// so the custom unmarshalers of gender and status are reused
+func ImportRowToDto(header []string, row []string) (*dto.CreatePetRequest, error) {
+	fields := make(map[string]interface{})
+	for i, column := range header {
+		if i >= len(row) {
+			break
+		}
+
+		key := normalizeImportColumn(column)
+		value := strings.TrimSpace(row[i])
+		if value == "" {
+			continue
+		}
+
+		if _, ok := importStringColumns[key]; ok {
+			fields[key] = value
+			continue
+		}
+		if _, ok := importBoolColumns[key]; ok {
+			b, err := parseImportBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", key, err)
+			}
+			fields[key] = b
+		}
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &dto.CreatePetRequest{}
+	if err := json.Unmarshal(raw, request); err != nil {
+		return nil, err
+	}
+	if request.Pattern == "" {
+		request.Pattern = importDefaultPattern
+	}
+
+	return request, nil
+}
+
+func IsEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseCsv(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	return reader.ReadAll()
+}
+
+func parseXlsx(data []byte) ([][]string, error) {
+	file, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("workbook has no sheet")
+	}
+
+	return file.GetRows(sheets[0])
+}
+
+func normalizeImportColumn(column string) string {
+	column = strings.ToLower(strings.TrimSpace(column))
+	return strings.ReplaceAll(column, " ", "_")
+}
+
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "y":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q", value)
+	}
+	return b, nil
+}
@@ -0,0 +1,56 @@
+package pet
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+type collector struct {
+	repository Repository
+	timeout    time.Duration
+	pets       *prometheus.Desc
+}
+
+// NewCollector counts the pets when prometheus scrapes, so the gauges are never out of date with the database.
+// The count is given up after the timeout so a slow database cannot pile up scrapes
+func NewCollector(repository Repository, timeout time.Duration) prometheus.Collector {
+	return &collector{
+		repository: repository,
+		timeout:    timeout,
+		pets: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "", "pets"),
+			"Number of pets by status and visibility.",
+			[]string{"status", "visible"},
+			nil,
+		),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pets
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	var counts []*StatusCount
+	if err := c.repository.CountByStatus(ctx, &counts); err != nil {
+		log.Error().
+			Err(err).
+			Str("service", "pet").
+			Str("module", "collector").
+			Msg("Error counting pets by status")
+		return
+	}
+
+	for _, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.pets, prometheus.GaugeValue, float64(count.Count),
+			string(count.Status), strconv.FormatBool(count.IsVisible))
+	}
+}
@@ -1,18 +1,30 @@
 package pet
 
 import (
+	"context"
 	"errors"
 
+	"github.com/isd-sgcu/johnjud-backend/constant"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
 	"gorm.io/gorm"
 )
 
 type Repository interface {
-	FindAll(result *[]*model.Pet, isAdmin bool) error
-	FindOne(id string, result *model.Pet) error
-	Create(in *model.Pet) error
-	Update(id string, result *model.Pet) error
-	Delete(id string) error
+	FindAll(ctx context.Context, result *[]*model.Pet, isAdmin bool) error
+	FindOne(ctx context.Context, id string, result *model.Pet) error
+	FindByOwner(ctx context.Context, owner string, result *[]*model.Pet) error
+	Create(ctx context.Context, in *model.Pet) error
+	CreateMany(ctx context.Context, in []*model.Pet) error
+	Update(ctx context.Context, id string, result *model.Pet) error
+	Delete(ctx context.Context, id string) error
+	CountByStatus(ctx context.Context, result *[]*StatusCount) error
+}
+
+// StatusCount is the number of pets with the status and visibility
+type StatusCount struct {
+	Status    constant.Status
+	IsVisible bool
+	Count     int64
 }
 
 type repositoryImpl struct {
@@ -23,34 +35,56 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repositoryImpl{db: db}
 }
 
-func (r *repositoryImpl) FindAll(result *[]*model.Pet, isAdmin bool) error {
+func (r *repositoryImpl) FindAll(ctx context.Context, result *[]*model.Pet, isAdmin bool) error {
 	if isAdmin {
-		return r.db.Model(&model.Pet{}).Find(result).Error
+		return r.db.WithContext(ctx).Model(&model.Pet{}).Find(result).Error
 	}
-	return r.db.Model(&model.Pet{}).Find(result, "is_visible = ?", true).Error
+	return r.db.WithContext(ctx).Model(&model.Pet{}).Find(result, "is_visible = ?", true).Error
 }
 
-func (r *repositoryImpl) FindOne(id string, result *model.Pet) error {
-	return r.db.Model(&model.Pet{}).First(result, "id = ?", id).Error
+func (r *repositoryImpl) FindOne(ctx context.Context, id string, result *model.Pet) error {
+	return r.db.WithContext(ctx).Model(&model.Pet{}).First(result, "id = ?", id).Error
 }
 
-func (r *repositoryImpl) Create(in *model.Pet) error {
-	return r.db.Create(&in).Error
+func (r *repositoryImpl) FindByOwner(ctx context.Context, owner string, result *[]*model.Pet) error {
+	return r.db.WithContext(ctx).Model(&model.Pet{}).Order("updated_at desc").Find(result, "owner = ?", owner).Error
 }
 
-func (r *repositoryImpl) Update(id string, result *model.Pet) error {
+func (r *repositoryImpl) Create(ctx context.Context, in *model.Pet) error {
+	return r.db.WithContext(ctx).Create(&in).Error
+}
+
+// createBatchSize keeps each insert of an import under the 65535 bind parameters of postgres,
+// a pet takes about 19 of them
+const createBatchSize = 500
+
+// CreateMany inserts the pets in batches, all of them or none since the batches share a transaction
+func (r *repositoryImpl) CreateMany(ctx context.Context, in []*model.Pet) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(in, createBatchSize).Error
+	})
+}
+
+func (r *repositoryImpl) Update(ctx context.Context, id string, result *model.Pet) error {
 	updateMap := UpdateMap(result)
-	return r.db.Model(&result).Where("id = ?", id).Updates(updateMap).First(&result, "id = ?", id).Error
+	return r.db.WithContext(ctx).Model(&result).Where("id = ?", id).Updates(updateMap).First(&result, "id = ?", id).Error
 }
 
-func (r *repositoryImpl) Delete(id string) error {
+func (r *repositoryImpl) Delete(ctx context.Context, id string) error {
 	var pet model.Pet
-	err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&pet).Error
+	err := r.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&pet).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return gorm.ErrRecordNotFound
 		}
 		return err
 	}
-	return r.db.Delete(&pet).Error
+	return r.db.WithContext(ctx).Delete(&pet).Error
+}
+
+func (r *repositoryImpl) CountByStatus(ctx context.Context, result *[]*StatusCount) error {
+	return r.db.WithContext(ctx).Model(&model.Pet{}).
+		Select("status, is_visible, count(*) as count").
+		Group("status, is_visible").
+		Scan(result).Error
 }
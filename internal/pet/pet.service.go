@@ -1,38 +1,66 @@
 package pet
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/email"
 	"github.com/isd-sgcu/johnjud-backend/internal/dto"
 	"github.com/isd-sgcu/johnjud-backend/internal/image"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/tracing"
+	"github.com/isd-sgcu/johnjud-backend/internal/validator"
 	"github.com/rs/zerolog/log"
 
 	"gorm.io/gorm"
 )
 
 type Service interface {
-	FindAll(req *dto.FindAllPetRequest, isAdmin bool) (*dto.FindAllPetResponse, *dto.ResponseErr)
-	FindOne(id string) (*dto.PetResponse, *dto.ResponseErr)
-	Create(req *dto.CreatePetRequest) (*dto.PetResponse, *dto.ResponseErr)
-	Update(id string, req *dto.UpdatePetRequest) (*dto.PetResponse, *dto.ResponseErr)
-	Delete(id string) (*dto.DeleteResponse, *dto.ResponseErr)
-	ChangeView(id string, req *dto.ChangeViewPetRequest) (*dto.ChangeViewPetResponse, *dto.ResponseErr)
-	Adopt(id string, req *dto.AdoptByRequest) (*dto.AdoptByResponse, *dto.ResponseErr)
+	FindAll(ctx context.Context, req *dto.FindAllPetRequest, isAdmin bool) (*dto.FindAllPetResponse, *dto.ResponseErr)
+	FindOne(ctx context.Context, id string) (*dto.PetResponse, *dto.ResponseErr)
+	Create(ctx context.Context, req *dto.CreatePetRequest) (*dto.PetResponse, *dto.ResponseErr)
+	Update(ctx context.Context, id string, req *dto.UpdatePetRequest) (*dto.PetResponse, *dto.ResponseErr)
+	Delete(ctx context.Context, id string) (*dto.DeleteResponse, *dto.ResponseErr)
+	ChangeView(ctx context.Context, id string, req *dto.ChangeViewPetRequest) (*dto.ChangeViewPetResponse, *dto.ResponseErr)
+	Adopt(ctx context.Context, id string, req *dto.AdoptByRequest) (*dto.AdoptByResponse, *dto.ResponseErr)
+	Import(ctx context.Context, req *dto.ImportPetRequest) (*dto.ImportPetResponse, *dto.ResponseErr)
+	Export(ctx context.Context, req *dto.ExportPetRequest) (*dto.ExportPetResponse, *dto.ResponseErr)
+}
+
+// UserRepository is the part of user.Repository the pet service needs, user already imports pet
+type UserRepository interface {
+	FindById(ctx context.Context, id string, user *model.User) error
 }
 
 type serviceImpl struct {
-	repository   Repository
-	imageService image.Service
+	repository     Repository
+	imageService   image.Service
+	validate       validator.IDtoValidator
+	conf           config.Pet
+	userRepository UserRepository
+	emailService   email.Service
 }
 
-func NewService(repository Repository, imageService image.Service) Service {
-	return &serviceImpl{repository: repository, imageService: imageService}
+func NewService(repository Repository, imageService image.Service, validate validator.IDtoValidator, conf config.Pet, userRepository UserRepository, emailService email.Service) Service {
+	return &serviceImpl{
+		repository:     repository,
+		imageService:   imageService,
+		validate:       validate,
+		conf:           conf,
+		userRepository: userRepository,
+		emailService:   emailService,
+	}
 }
 
-func (s *serviceImpl) Delete(id string) (*dto.DeleteResponse, *dto.ResponseErr) {
-	err := s.repository.Delete(id)
+func (s *serviceImpl) Delete(ctx context.Context, id string) (*dto.DeleteResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "pet.Delete")
+	defer span.End()
+
+	err := s.repository.Delete(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, dto.NotFoundError("pet not found")
@@ -42,15 +70,18 @@ func (s *serviceImpl) Delete(id string) (*dto.DeleteResponse, *dto.ResponseErr)
 	return &dto.DeleteResponse{Success: true}, nil
 }
 
-func (s *serviceImpl) Update(id string, req *dto.UpdatePetRequest) (*dto.PetResponse, *dto.ResponseErr) {
+func (s *serviceImpl) Update(ctx context.Context, id string, req *dto.UpdatePetRequest) (*dto.PetResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "pet.Update")
+	defer span.End()
+
 	raw := UpdateDtoToModel(req)
 
-	err := s.repository.Update(id, raw)
+	err := s.repository.Update(ctx, id, raw)
 	if err != nil {
 		return nil, dto.NotFoundError("pet not found")
 	}
 
-	images, apperr := s.imageService.FindByPetId(id)
+	images, apperr := s.imageService.FindByPetId(ctx, id)
 	if apperr != nil {
 		return nil, dto.InternalServerError("error querying image service")
 	}
@@ -58,8 +89,11 @@ func (s *serviceImpl) Update(id string, req *dto.UpdatePetRequest) (*dto.PetResp
 	return RawToDto(raw, images), nil
 }
 
-func (s *serviceImpl) ChangeView(id string, req *dto.ChangeViewPetRequest) (*dto.ChangeViewPetResponse, *dto.ResponseErr) {
-	petData, apperr := s.FindOne(id)
+func (s *serviceImpl) ChangeView(ctx context.Context, id string, req *dto.ChangeViewPetRequest) (*dto.ChangeViewPetResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "pet.ChangeView")
+	defer span.End()
+
+	petData, apperr := s.FindOne(ctx, id)
 	if apperr != nil {
 		return nil, apperr
 	}
@@ -69,7 +103,7 @@ func (s *serviceImpl) ChangeView(id string, req *dto.ChangeViewPetRequest) (*dto
 	}
 	pet.IsVisible = req.Visible
 
-	err = s.repository.Update(id, pet)
+	err = s.repository.Update(ctx, id, pet)
 	if err != nil {
 		return nil, dto.NotFoundError("pet not found")
 	}
@@ -77,14 +111,17 @@ func (s *serviceImpl) ChangeView(id string, req *dto.ChangeViewPetRequest) (*dto
 	return &dto.ChangeViewPetResponse{Success: true}, nil
 }
 
-func (s *serviceImpl) FindAll(req *dto.FindAllPetRequest, isAdmin bool) (*dto.FindAllPetResponse, *dto.ResponseErr) {
+func (s *serviceImpl) FindAll(ctx context.Context, req *dto.FindAllPetRequest, isAdmin bool) (*dto.FindAllPetResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "pet.FindAll")
+	defer span.End()
+
 	var pets []*model.Pet
 	imagesList := make(map[string][]*dto.ImageResponse)
 	metaData := dto.FindAllMetadata{}
 
-	err := s.repository.FindAll(&pets, isAdmin)
+	err := s.repository.FindAll(ctx, &pets, isAdmin)
 	if err != nil {
-		log.Error().Err(err).Str("service", "event").Str("module", "find all").Msg("Error while querying all events")
+		log.Error().Ctx(ctx).Err(err).Str("service", "event").Str("module", "find all").Msg("Error while querying all events")
 		return nil, dto.InternalServerError("error querying all pets")
 	}
 
@@ -92,7 +129,7 @@ func (s *serviceImpl) FindAll(req *dto.FindAllPetRequest, isAdmin bool) (*dto.Fi
 	PaginatePets(&pets, req.Page, req.PageSize, &metaData)
 
 	for _, pet := range pets {
-		images, err := s.imageService.FindByPetId(pet.ID.String())
+		images, err := s.imageService.FindByPetId(ctx, pet.ID.String())
 		if err != nil {
 			return nil, dto.InternalServerError("error querying image service")
 		}
@@ -115,17 +152,20 @@ func (s *serviceImpl) FindAll(req *dto.FindAllPetRequest, isAdmin bool) (*dto.Fi
 	return &dto.FindAllPetResponse{Pets: petWithImages, Metadata: &metaData}, nil
 }
 
-func (s *serviceImpl) FindOne(id string) (*dto.PetResponse, *dto.ResponseErr) {
+func (s *serviceImpl) FindOne(ctx context.Context, id string) (*dto.PetResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "pet.FindOne")
+	defer span.End()
+
 	var pet model.Pet
 
-	err := s.repository.FindOne(id, &pet)
+	err := s.repository.FindOne(ctx, id, &pet)
 	if err != nil {
-		log.Error().Err(err).
+		log.Error().Ctx(ctx).Err(err).
 			Str("service", "pet").Str("module", "find one").Str("id", id).Msg("Not found")
 		return nil, dto.NotFoundError("pet not found")
 	}
 
-	images, apperr := s.imageService.FindByPetId(id)
+	images, apperr := s.imageService.FindByPetId(ctx, id)
 	if apperr != nil {
 		return nil, apperr
 	}
@@ -133,10 +173,13 @@ func (s *serviceImpl) FindOne(id string) (*dto.PetResponse, *dto.ResponseErr) {
 	return RawToDto(&pet, images), nil
 }
 
-func (s *serviceImpl) Create(req *dto.CreatePetRequest) (*dto.PetResponse, *dto.ResponseErr) {
+func (s *serviceImpl) Create(ctx context.Context, req *dto.CreatePetRequest) (*dto.PetResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "pet.Create")
+	defer span.End()
+
 	raw := CreateDtoToModel(req)
 
-	err := s.repository.Create(raw)
+	err := s.repository.Create(ctx, raw)
 	if err != nil {
 		return nil, dto.InternalServerError("failed to create pet")
 	}
@@ -145,12 +188,12 @@ func (s *serviceImpl) Create(req *dto.CreatePetRequest) (*dto.PetResponse, *dto.
 		PetId: raw.ID.String(),
 		Ids:   req.Images,
 	}
-	_, apperr := s.imageService.AssignPet(assignReq)
+	_, apperr := s.imageService.AssignPet(ctx, assignReq)
 	if apperr != nil {
 		return nil, apperr
 	}
 
-	images, apperr := s.imageService.FindByPetId(raw.ID.String())
+	images, apperr := s.imageService.FindByPetId(ctx, raw.ID.String())
 	if apperr != nil {
 		return nil, apperr
 	}
@@ -158,8 +201,11 @@ func (s *serviceImpl) Create(req *dto.CreatePetRequest) (*dto.PetResponse, *dto.
 	return RawToDto(raw, images), nil
 }
 
-func (s *serviceImpl) Adopt(id string, req *dto.AdoptByRequest) (*dto.AdoptByResponse, *dto.ResponseErr) {
-	dtoPet, apperr := s.FindOne(id)
+func (s *serviceImpl) Adopt(ctx context.Context, id string, req *dto.AdoptByRequest) (*dto.AdoptByResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "pet.Adopt")
+	defer span.End()
+
+	dtoPet, apperr := s.FindOne(ctx, id)
 	if apperr != nil {
 		return nil, apperr
 	}
@@ -170,10 +216,180 @@ func (s *serviceImpl) Adopt(id string, req *dto.AdoptByRequest) (*dto.AdoptByRes
 	}
 	pet.Owner = req.UserID
 
-	err = s.repository.Update(id, pet)
+	err = s.repository.Update(ctx, id, pet)
 	if err != nil {
 		return nil, dto.NotFoundError("pet not found")
 	}
 
+	s.sendAdoptionStatus(ctx, req.UserID, pet.Name)
+
 	return &dto.AdoptByResponse{Success: true}, nil
 }
+
+// sendAdoptionStatus only logs on failure, the adoption has already been saved so it is not cancelled with the request
+func (s *serviceImpl) sendAdoptionStatus(ctx context.Context, userId string, petName string) {
+	ctx = context.WithoutCancel(ctx)
+
+	var user model.User
+	if err := s.userRepository.FindById(ctx, userId, &user); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "pet").
+			Str("module", "adopt").
+			Str("user_id", userId).
+			Msg("Error finding adopter")
+		return
+	}
+
+	recipient := &email.Recipient{
+		Name:    user.Firstname,
+		Address: user.Email,
+		Locale:  user.Locale,
+	}
+	data := &email.AdoptionStatusData{Name: user.Firstname, PetName: petName}
+	if err := s.emailService.Send(ctx, constant.AdoptionStatusTemplate, recipient, data); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "pet").
+			Str("module", "adopt").
+			Str("user_id", userId).
+			Msg("Error sending adoption status email")
+	}
+}
+
+func (s *serviceImpl) Import(ctx context.Context, req *dto.ImportPetRequest) (*dto.ImportPetResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "pet.Import")
+	defer span.End()
+
+	rows, err := ParseImportFile(req.Filename, req.Data)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "pet").
+			Str("module", "import").
+			Str("filename", req.Filename).
+			Msg(constant.InvalidImportFileErrorMessage)
+		return nil, dto.BadRequestError(constant.InvalidImportFileErrorMessage)
+	}
+	if len(rows) < 2 {
+		return nil, dto.BadRequestError(constant.EmptyImportFileErrorMessage)
+	}
+
+	response := &dto.ImportPetResponse{
+		DryRun: req.DryRun,
+		Pets:   []*dto.PetResponse{},
+		Errors: []*dto.ImportPetRowError{},
+	}
+
+	header := rows[0]
+	var pets []*model.Pet
+	for i, row := range rows[1:] {
+		if IsEmptyRow(row) {
+			continue
+		}
+		response.Total++
+		// row numbers are 1-based and the header is row 1
+		rowNumber := i + 2
+
+		createReq, err := ImportRowToDto(header, row)
+		if err != nil {
+			response.Errors = append(response.Errors, &dto.ImportPetRowError{
+				Row:      rowNumber,
+				Messages: []string{err.Error()},
+			})
+			continue
+		}
+
+		if errs := s.validate.Validate(ctx, createReq); errs != nil {
+			var messages []string
+			for _, e := range errs {
+				messages = append(messages, e.Message)
+			}
+			response.Errors = append(response.Errors, &dto.ImportPetRowError{
+				Row:      rowNumber,
+				Messages: messages,
+			})
+			continue
+		}
+
+		pets = append(pets, CreateDtoToModel(createReq))
+	}
+
+	response.Valid = len(pets)
+	response.Invalid = len(response.Errors)
+
+	if !req.DryRun && len(pets) > 0 {
+		if err := s.repository.CreateMany(ctx, pets); err != nil {
+			log.Error().Ctx(ctx).Err(err).
+				Str("service", "pet").
+				Str("module", "import").
+				Int("pets", len(pets)).
+				Msg(constant.ImportPetErrorMessage)
+			return nil, dto.InternalServerError(constant.ImportPetErrorMessage)
+		}
+		response.Created = len(pets)
+	}
+
+	for _, pet := range pets {
+		petDto := RawToDto(pet, []*dto.ImageResponse{})
+		if req.DryRun {
+			petDto.Id = ""
+		}
+		response.Pets = append(response.Pets, petDto)
+	}
+
+	return response, nil
+}
+
+func (s *serviceImpl) Export(ctx context.Context, req *dto.ExportPetRequest) (*dto.ExportPetResponse, *dto.ResponseErr) {
+	ctx, span := tracing.Start(ctx, "pet.Export")
+	defer span.End()
+
+	if req.Format != constant.CSV && req.Format != constant.PDF {
+		return nil, dto.BadRequestError(constant.InvalidExportFormatErrorMessage)
+	}
+
+	pets, apperr := s.FindAll(ctx, req.Query, true)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	filename := fmt.Sprintf("pets-%s.%s", time.Now().Format("20060102-150405"), req.Format)
+
+	if req.Format == constant.CSV {
+		data, err := ExportCsv(pets.Pets)
+		if err != nil {
+			log.Error().Ctx(ctx).Err(err).
+				Str("service", "pet").
+				Str("module", "export").
+				Msg("Error writing csv")
+			return nil, dto.InternalServerError(constant.ExportPetErrorMessage)
+		}
+
+		return &dto.ExportPetResponse{Filename: filename, ContentType: "text/csv", Data: data}, nil
+	}
+
+	if len(pets.Pets) > s.conf.ExportPdfMaxPets {
+		return nil, dto.BadRequestError(constant.TooManyPetsForPdfErrorMessage)
+	}
+
+	photos := make(map[string][]byte)
+	for _, pet := range pets.Pets {
+		if len(pet.Images) == 0 {
+			continue
+		}
+		data, apperr := s.imageService.Download(ctx, pet.Images[0].ObjectKey)
+		if apperr != nil {
+			continue
+		}
+		photos[pet.Id] = data
+	}
+
+	data, err := ExportPdf(ctx, pets.Pets, photos, s.conf.PageURL, s.conf.ExportFontPath)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "pet").
+			Str("module", "export").
+			Msg("Error writing pdf")
+		return nil, dto.InternalServerError(constant.ExportPetErrorMessage)
+	}
+
+	return &dto.ExportPetResponse{Filename: filename, ContentType: "application/pdf", Data: data}, nil
+}
@@ -256,7 +256,7 @@ package test
 // 	context := routerMock.NewMockIContext(controller)
 
 // 	context.EXPECT().Bind(t.CreatePetRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.CreatePetRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.CreatePetRequest).Return(nil)
 // 	petSvc.EXPECT().Create(t.CreatePetRequest).Return(createResponse, nil)
 // 	context.EXPECT().JSON(http.StatusCreated, expectedResponse)
 
@@ -275,7 +275,7 @@ package test
 // 	context := routerMock.NewMockIContext(controller)
 
 // 	context.EXPECT().Bind(t.CreatePetRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.CreatePetRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.CreatePetRequest).Return(nil)
 // 	petSvc.EXPECT().Create(t.CreatePetRequest).Return(nil, createErrorResponse)
 // 	context.EXPECT().JSON(http.StatusServiceUnavailable, createErrorResponse)
 
@@ -296,7 +296,7 @@ package test
 
 // 	context.EXPECT().Param("id").Return(t.Pet.Id, nil)
 // 	context.EXPECT().Bind(t.UpdatePetRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.UpdatePetRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.UpdatePetRequest).Return(nil)
 // 	petSvc.EXPECT().Update(t.Pet.Id, t.UpdatePetRequest).Return(updateResponse, nil)
 // 	context.EXPECT().JSON(http.StatusOK, expectedResponse)
 
@@ -316,7 +316,7 @@ package test
 
 // 	context.EXPECT().Param("id").Return(t.Pet.Id, nil)
 // 	context.EXPECT().Bind(t.UpdatePetRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.UpdatePetRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.UpdatePetRequest).Return(nil)
 // 	petSvc.EXPECT().Update(t.Pet.Id, t.UpdatePetRequest).Return(nil, updateResponse)
 // 	context.EXPECT().JSON(http.StatusNotFound, updateResponse)
 
@@ -336,7 +336,7 @@ package test
 
 // 	context.EXPECT().Param("id").Return(t.Pet.Id, nil)
 // 	context.EXPECT().Bind(t.UpdatePetRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.UpdatePetRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.UpdatePetRequest).Return(nil)
 // 	petSvc.EXPECT().Update(t.Pet.Id, t.UpdatePetRequest).Return(nil, updateResponse)
 // 	context.EXPECT().JSON(http.StatusServiceUnavailable, updateResponse)
 
@@ -419,7 +419,7 @@ package test
 
 // 	context.EXPECT().Param("id").Return(t.Pet.Id, nil)
 // 	context.EXPECT().Bind(t.ChangeViewPetRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.ChangeViewPetRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.ChangeViewPetRequest).Return(nil)
 // 	petSvc.EXPECT().ChangeView(t.Pet.Id, t.ChangeViewPetRequest).Return(changeViewResponse, nil)
 // 	context.EXPECT().JSON(http.StatusOK, expectedResponse)
 
@@ -441,7 +441,7 @@ package test
 
 // 	context.EXPECT().Param("id").Return(t.Pet.Id, nil)
 // 	context.EXPECT().Bind(t.ChangeViewPetRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.ChangeViewPetRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.ChangeViewPetRequest).Return(nil)
 // 	petSvc.EXPECT().ChangeView(t.Pet.Id, t.ChangeViewPetRequest).Return(changeViewResponse, t.NotFoundErr)
 // 	context.EXPECT().JSON(http.StatusNotFound, t.NotFoundErr)
 
@@ -463,7 +463,7 @@ package test
 
 // 	context.EXPECT().Param("id").Return(t.Pet.Id, nil)
 // 	context.EXPECT().Bind(t.ChangeViewPetRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.ChangeViewPetRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.ChangeViewPetRequest).Return(nil)
 // 	petSvc.EXPECT().ChangeView(t.Pet.Id, t.ChangeViewPetRequest).Return(changeViewResponse, t.ServiceDownErr)
 // 	context.EXPECT().JSON(http.StatusServiceUnavailable, t.ServiceDownErr)
 
@@ -486,7 +486,7 @@ package test
 
 // 	context.EXPECT().Param("id").Return(t.Pet.Id, nil)
 // 	context.EXPECT().Bind(t.AdoptByRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.AdoptByRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.AdoptByRequest).Return(nil)
 // 	petSvc.EXPECT().Adopt(t.Pet.Id, t.AdoptByRequest).Return(adoptByResponse, nil)
 // 	context.EXPECT().JSON(http.StatusOK, expectedResponse)
 
@@ -508,7 +508,7 @@ package test
 
 // 	context.EXPECT().Param("id").Return(t.Pet.Id, nil)
 // 	context.EXPECT().Bind(t.AdoptByRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.AdoptByRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.AdoptByRequest).Return(nil)
 // 	petSvc.EXPECT().Adopt(t.Pet.Id, t.AdoptByRequest).Return(adoptByResponse, t.NotFoundErr)
 // 	context.EXPECT().JSON(http.StatusNotFound, t.NotFoundErr)
 
@@ -530,7 +530,7 @@ package test
 
 // 	context.EXPECT().Param("id").Return(t.Pet.Id, nil)
 // 	context.EXPECT().Bind(t.AdoptByRequest).Return(nil)
-// 	validator.EXPECT().Validate(t.AdoptByRequest).Return(nil)
+// 	validator.EXPECT().Validate(gomock.Any(), t.AdoptByRequest).Return(nil)
 // 	petSvc.EXPECT().Adopt(t.Pet.Id, t.AdoptByRequest).Return(adoptByResponse, t.ServiceDownErr)
 // 	context.EXPECT().JSON(http.StatusServiceUnavailable, t.ServiceDownErr)
 
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/pet"
+	mock "github.com/isd-sgcu/johnjud-backend/mocks/repository/pet"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PetCollectorTest struct {
+	suite.Suite
+	counts []*pet.StatusCount
+}
+
+func TestPetCollector(t *testing.T) {
+	suite.Run(t, new(PetCollectorTest))
+}
+
+func (t *PetCollectorTest) SetupTest() {
+	t.counts = []*pet.StatusCount{
+		{Status: constant.FINDHOME, IsVisible: true, Count: 12},
+		{Status: constant.FINDHOME, IsVisible: false, Count: 3},
+		{Status: constant.ADOPTED, IsVisible: true, Count: 7},
+	}
+}
+
+func (t *PetCollectorTest) TestCollectSuccess() {
+	repo := &mock.RepositoryMock{}
+	var counts []*pet.StatusCount
+	repo.On("CountByStatus", testifyMock.Anything, &counts).Return(&t.counts, nil)
+
+	expected := `
+# HELP johnjud_pets Number of pets by status and visibility.
+# TYPE johnjud_pets gauge
+johnjud_pets{status="adopted",visible="true"} 7
+johnjud_pets{status="findhome",visible="false"} 3
+johnjud_pets{status="findhome",visible="true"} 12
+`
+	err := testutil.CollectAndCompare(pet.NewCollector(repo, time.Second), strings.NewReader(expected))
+
+	assert.Nil(t.T(), err)
+}
+
+func (t *PetCollectorTest) TestCollectFailed() {
+	repo := &mock.RepositoryMock{}
+	var counts []*pet.StatusCount
+	repo.On("CountByStatus", testifyMock.Anything, &counts).Return(nil, errors.New("connection refused"))
+
+	assert.Equal(t.T(), 0, testutil.CollectAndCount(pet.NewCollector(repo, time.Second)))
+}
+
+func (t *PetCollectorTest) TestCollectWithTimeout() {
+	repo := &mock.RepositoryMock{}
+	var counts []*pet.StatusCount
+	hasDeadline := testifyMock.MatchedBy(func(ctx context.Context) bool {
+		deadline, ok := ctx.Deadline()
+		return ok && time.Until(deadline) <= time.Second
+	})
+	repo.On("CountByStatus", hasDeadline, &counts).Return(&t.counts, nil)
+
+	assert.Equal(t.T(), len(t.counts), testutil.CollectAndCount(pet.NewCollector(repo, time.Second)))
+	repo.AssertExpectations(t.T())
+}
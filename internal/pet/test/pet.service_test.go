@@ -1,24 +1,37 @@
 package test
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"errors"
+	"image"
+	"image/png"
 	"math/rand"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/bxcodec/faker/v3"
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/config"
 	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/email"
 	"github.com/isd-sgcu/johnjud-backend/internal/dto"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
 	"github.com/isd-sgcu/johnjud-backend/internal/pet"
+	"github.com/isd-sgcu/johnjud-backend/internal/validator"
 	mock "github.com/isd-sgcu/johnjud-backend/mocks/repository/pet"
+	user_mock "github.com/isd-sgcu/johnjud-backend/mocks/repository/user"
+	email_mock "github.com/isd-sgcu/johnjud-backend/mocks/service/email"
 	img_mock "github.com/isd-sgcu/johnjud-backend/mocks/service/image"
 	"gorm.io/gorm"
 
 	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/xuri/excelize/v2"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -38,6 +51,9 @@ type PetServiceTest struct {
 	ImagesList           [][]*dto.ImageResponse
 	ChangeAdoptBy        *model.Pet
 	AdoptByReq           *dto.AdoptByRequest
+	Validator            validator.IDtoValidator
+	Config               config.Pet
+	ImportCsv            []byte
 }
 
 func TestPetService(t *testing.T) {
@@ -229,16 +245,25 @@ func (t *PetServiceTest) SetupTest() {
 		UserID: t.ChangeAdoptBy.Owner,
 	}
 
+	t.Validator, _ = validator.NewIValidator(nil)
+	t.Config = config.Pet{PageURL: "https://johnjud.example/pets", ExportPdfMaxPets: 100}
+
+	t.ImportCsv = []byte("type,name,birthdate,gender,color,pattern,habit,caption,status,is_sterile,is_vaccinated,is_visible,origin\n" +
+		"dog,Lucky,2020-01-01T00:00:00Z,male,brown,spots,friendly,,findhome,yes,true,1,shelter\n" +
+		"cat,,2021-05-01T00:00:00Z,female,white,plain,calm,,findhome,no,false,0,street\n" +
+		",,,,,,,,,,,,\n" +
+		"cat,Milo,2022-03-01T00:00:00Z,unknown,black,plain,shy,,findhome,no,no,yes,street\n")
+
 }
 func (t *PetServiceTest) TestDeleteSuccess() {
 	want := &dto.DeleteResponse{Success: true}
 
 	repo := new(mock.RepositoryMock)
-	repo.On("Delete", t.Pet.ID.String()).Return(nil)
+	repo.On("Delete", testifyMock.Anything, t.Pet.ID.String()).Return(nil)
 	imgSrv := new(img_mock.ServiceMock)
 
-	srv := pet.NewService(repo, imgSrv)
-	actual, err := srv.Delete(t.Pet.ID.String())
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Delete(context.Background(), t.Pet.ID.String())
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), want, actual)
@@ -247,11 +272,11 @@ func (t *PetServiceTest) TestDeleteSuccess() {
 
 func (t *PetServiceTest) TestDeleteNotFound() {
 	repo := new(mock.RepositoryMock)
-	repo.On("Delete", t.Pet.ID.String()).Return(gorm.ErrRecordNotFound)
+	repo.On("Delete", testifyMock.Anything, t.Pet.ID.String()).Return(gorm.ErrRecordNotFound)
 	imgSrv := new(img_mock.ServiceMock)
 
-	srv := pet.NewService(repo, imgSrv)
-	_, err := srv.Delete(t.Pet.ID.String())
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	_, err := srv.Delete(context.Background(), t.Pet.ID.String())
 
 	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
 	repo.AssertExpectations(t.T())
@@ -259,11 +284,11 @@ func (t *PetServiceTest) TestDeleteNotFound() {
 
 func (t *PetServiceTest) TestDeleteWithDatabaseError() {
 	repo := new(mock.RepositoryMock)
-	repo.On("Delete", t.Pet.ID.String()).Return(errors.New("internal server error"))
+	repo.On("Delete", testifyMock.Anything, t.Pet.ID.String()).Return(errors.New("internal server error"))
 	imgSrv := new(img_mock.ServiceMock)
 
-	srv := pet.NewService(repo, imgSrv)
-	_, err := srv.Delete(t.Pet.ID.String())
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	_, err := srv.Delete(context.Background(), t.Pet.ID.String())
 
 	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
 	repo.AssertExpectations(t.T())
@@ -271,11 +296,11 @@ func (t *PetServiceTest) TestDeleteWithDatabaseError() {
 
 func (t *PetServiceTest) TestDeleteWithUnexpectedError() {
 	repo := new(mock.RepositoryMock)
-	repo.On("Delete", t.Pet.ID.String()).Return(errors.New("unexpected error"))
+	repo.On("Delete", testifyMock.Anything, t.Pet.ID.String()).Return(errors.New("unexpected error"))
 	imgSrv := new(img_mock.ServiceMock)
 
-	srv := pet.NewService(repo, imgSrv)
-	_, err := srv.Delete(t.Pet.ID.String())
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	_, err := srv.Delete(context.Background(), t.Pet.ID.String())
 
 	assert.NotNil(t.T(), err)
 	repo.AssertExpectations(t.T())
@@ -285,12 +310,12 @@ func (t *PetServiceTest) TestFindOneSuccess() {
 	want := t.PetDto
 
 	repo := &mock.RepositoryMock{}
-	repo.On("FindOne", t.Pet.ID.String(), &model.Pet{}).Return(t.Pet, nil)
+	repo.On("FindOne", testifyMock.Anything, t.Pet.ID.String(), &model.Pet{}).Return(t.Pet, nil)
 	imgSrv := new(img_mock.ServiceMock)
-	imgSrv.On("FindByPetId", t.Pet.ID.String()).Return(t.Images, nil)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.Pet.ID.String()).Return(t.Images, nil)
 
-	srv := pet.NewService(repo, imgSrv)
-	actual, err := srv.FindOne(t.Pet.ID.String())
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.FindOne(context.Background(), t.Pet.ID.String())
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), want, actual)
@@ -318,7 +343,7 @@ func (t *PetServiceTest) TestFindOneSuccess() {
 // 		imgSrv.On("FindByPetId", pet.ID.String()).Return(t.ImagesList[i], nil)
 // 	}
 
-// 	srv := pet.NewService(repo, imgSrv)
+// 	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
 
 // 	actual, err := srv.FindAll()
 // 	assert.Nil(t.T(), err)
@@ -327,12 +352,12 @@ func (t *PetServiceTest) TestFindOneSuccess() {
 
 func (t *PetServiceTest) TestFindOneNotFound() {
 	repo := &mock.RepositoryMock{}
-	repo.On("FindOne", t.Pet.ID.String(), &model.Pet{}).Return(nil, errors.New("Not found pet"))
+	repo.On("FindOne", testifyMock.Anything, t.Pet.ID.String(), &model.Pet{}).Return(nil, errors.New("Not found pet"))
 	imgSrv := new(img_mock.ServiceMock)
-	imgSrv.On("FindByPetId", t.Pet.ID.String()).Return(nil, nil)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.Pet.ID.String()).Return(nil, nil)
 
-	srv := pet.NewService(repo, imgSrv)
-	actual, err := srv.FindOne(t.Pet.ID.String())
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.FindOne(context.Background(), t.Pet.ID.String())
 
 	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
 	assert.Nil(t.T(), actual)
@@ -426,17 +451,17 @@ func (t *PetServiceTest) TestCreateSuccess() {
 		Contact:      t.Pet.Contact,
 	}
 
-	repo.On("Create", in).Return(t.Pet, nil)
+	repo.On("Create", testifyMock.Anything, in).Return(t.Pet, nil)
 	imgSrv := new(img_mock.ServiceMock)
 
 	// imageIds := []string{t.CreatePetReqMock.Images[0], t.CreatePetReqMock.Images[1], t.CreatePetReqMock.Images[2]}
-	imgSrv.On("AssignPet", &dto.AssignPetRequest{PetId: t.Pet.ID.String(), Ids: t.ImageUrls}).Return(&dto.AssignPetResponse{Success: true})
+	imgSrv.On("AssignPet", testifyMock.Anything, &dto.AssignPetRequest{PetId: t.Pet.ID.String(), Ids: t.ImageUrls}).Return(&dto.AssignPetResponse{Success: true})
 
-	imgSrv.On("FindByPetId", t.Pet.ID.String()).Return(t.Images, nil)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.Pet.ID.String()).Return(t.Images, nil)
 
-	srv := pet.NewService(repo, imgSrv)
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
 
-	actual, err := srv.Create(t.CreatePetReqMock)
+	actual, err := srv.Create(context.Background(), t.CreatePetReqMock)
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), want, actual)
@@ -463,12 +488,12 @@ func (t *PetServiceTest) TestCreateInternalErr() {
 		Contact:      t.Pet.Contact,
 	}
 
-	repo.On("Create", in).Return(nil, errors.New("something wrong"))
+	repo.On("Create", testifyMock.Anything, in).Return(nil, errors.New("something wrong"))
 	imgSrv := new(img_mock.ServiceMock)
 
-	srv := pet.NewService(repo, imgSrv)
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
 
-	actual, err := srv.Create(t.CreatePetReqMock)
+	actual, err := srv.Create(context.Background(), t.CreatePetReqMock)
 
 	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
 	assert.Nil(t.T(), actual)
@@ -480,12 +505,12 @@ func (t *PetServiceTest) TestUpdateSuccess() {
 	updatePet.ID = uuid.Nil
 
 	repo := &mock.RepositoryMock{}
-	repo.On("Update", t.Pet.ID.String(), t.UpdatePet).Return(t.Pet, nil)
+	repo.On("Update", testifyMock.Anything, t.Pet.ID.String(), t.UpdatePet).Return(t.Pet, nil)
 	imgSrv := new(img_mock.ServiceMock)
-	imgSrv.On("FindByPetId", t.Pet.ID.String()).Return(t.Images, nil)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.Pet.ID.String()).Return(t.Images, nil)
 
-	srv := pet.NewService(repo, imgSrv)
-	actual, err := srv.Update(t.Pet.ID.String(), t.UpdatePetReqMock)
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Update(context.Background(), t.Pet.ID.String(), t.UpdatePetReqMock)
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), want, actual)
@@ -495,12 +520,12 @@ func (t *PetServiceTest) TestUpdateNotFound() {
 	updatePet := t.UpdatePet
 	updatePet.ID = uuid.Nil
 	repo := &mock.RepositoryMock{}
-	repo.On("Update", t.UpdatePet.ID.String(), t.UpdatePet).Return(nil, errors.New("Not found pet"))
+	repo.On("Update", testifyMock.Anything, t.UpdatePet.ID.String(), t.UpdatePet).Return(nil, errors.New("Not found pet"))
 	imgSrv := new(img_mock.ServiceMock)
-	imgSrv.On("FindByPetId", t.UpdatePet.ID.String()).Return(t.Images, nil)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.UpdatePet.ID.String()).Return(t.Images, nil)
 
-	srv := pet.NewService(repo, imgSrv)
-	actual, err := srv.Update(t.UpdatePet.ID.String(), t.UpdatePetReqMock)
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Update(context.Background(), t.UpdatePet.ID.String(), t.UpdatePetReqMock)
 
 	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
 	assert.Nil(t.T(), actual)
@@ -510,13 +535,13 @@ func (t *PetServiceTest) TestChangeViewSuccess() {
 	want := &dto.ChangeViewPetResponse{Success: true}
 
 	repo := &mock.RepositoryMock{}
-	repo.On("FindOne", t.Pet.ID.String(), &model.Pet{}).Return(t.Pet, nil)
-	repo.On("Update", t.Pet.ID.String(), t.ChangeViewPet).Return(t.ChangeViewPet, nil)
+	repo.On("FindOne", testifyMock.Anything, t.Pet.ID.String(), &model.Pet{}).Return(t.Pet, nil)
+	repo.On("Update", testifyMock.Anything, t.Pet.ID.String(), t.ChangeViewPet).Return(t.ChangeViewPet, nil)
 	imgSrv := new(img_mock.ServiceMock)
-	imgSrv.On("FindByPetId", t.Pet.ID.String()).Return(t.Images, nil)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.Pet.ID.String()).Return(t.Images, nil)
 
-	srv := pet.NewService(repo, imgSrv)
-	actual, err := srv.ChangeView(t.Pet.ID.String(), t.ChangeViewPetReqMock)
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.ChangeView(context.Background(), t.Pet.ID.String(), t.ChangeViewPetReqMock)
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), want, actual)
@@ -524,12 +549,12 @@ func (t *PetServiceTest) TestChangeViewSuccess() {
 
 func (t *PetServiceTest) TestChangeViewNotFound() {
 	repo := &mock.RepositoryMock{}
-	repo.On("FindOne", t.Pet.ID.String(), &model.Pet{}).Return(nil, errors.New("Not found pet"))
-	repo.On("Update", t.Pet.ID.String(), t.UpdatePet).Return(nil, errors.New("Not found pet"))
+	repo.On("FindOne", testifyMock.Anything, t.Pet.ID.String(), &model.Pet{}).Return(nil, errors.New("Not found pet"))
+	repo.On("Update", testifyMock.Anything, t.Pet.ID.String(), t.UpdatePet).Return(nil, errors.New("Not found pet"))
 	imgSrv := new(img_mock.ServiceMock)
 
-	srv := pet.NewService(repo, imgSrv)
-	actual, err := srv.ChangeView(t.Pet.ID.String(), t.ChangeViewPetReqMock)
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.ChangeView(context.Background(), t.Pet.ID.String(), t.ChangeViewPetReqMock)
 
 	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
 	assert.Nil(t.T(), actual)
@@ -539,15 +564,53 @@ func (t *PetServiceTest) TestAdoptBySuccess() {
 	want := &dto.AdoptByResponse{Success: true}
 	repo := &mock.RepositoryMock{}
 
-	repo.On("FindOne", t.Pet.ID.String(), &model.Pet{}).Return(t.Pet, nil)
-	repo.On("Update", t.Pet.ID.String(), t.ChangeAdoptBy).Return(t.ChangeAdoptBy, nil)
+	repo.On("FindOne", testifyMock.Anything, t.Pet.ID.String(), &model.Pet{}).Return(t.Pet, nil)
+	repo.On("Update", testifyMock.Anything, t.Pet.ID.String(), t.ChangeAdoptBy).Return(t.ChangeAdoptBy, nil)
 
 	imgSrv := new(img_mock.ServiceMock)
-	imgSrv.On("FindByPetId", t.Pet.ID.String()).Return(t.Images, nil)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.Pet.ID.String()).Return(t.Images, nil)
+
+	adopter := &model.User{Email: faker.Email(), Firstname: faker.FirstName(), Locale: constant.TH}
+	userRepo := &user_mock.UserRepositoryMock{}
+	userRepo.On("FindById", testifyMock.Anything, t.AdoptByReq.UserID, &model.User{}).Return(adopter, nil)
+
+	controller := gomock.NewController(t.T())
+	emailSrv := email_mock.NewMockService(controller)
+	emailSrv.EXPECT().Send(
+		gomock.Any(),
+		constant.AdoptionStatusTemplate,
+		&email.Recipient{Name: adopter.Firstname, Address: adopter.Email, Locale: adopter.Locale},
+		&email.AdoptionStatusData{Name: adopter.Firstname, PetName: t.Pet.Name},
+	).Return(nil)
 
-	srv := pet.NewService(repo, imgSrv)
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, userRepo, emailSrv)
 
-	actual, err := srv.Adopt(t.Pet.ID.String(), t.AdoptByReq)
+	actual, err := srv.Adopt(context.Background(), t.Pet.ID.String(), t.AdoptByReq)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), want, actual)
+}
+
+func (t *PetServiceTest) TestAdoptBySendEmailError() {
+	want := &dto.AdoptByResponse{Success: true}
+	repo := &mock.RepositoryMock{}
+
+	repo.On("FindOne", testifyMock.Anything, t.Pet.ID.String(), &model.Pet{}).Return(t.Pet, nil)
+	repo.On("Update", testifyMock.Anything, t.Pet.ID.String(), t.ChangeAdoptBy).Return(t.ChangeAdoptBy, nil)
+
+	imgSrv := new(img_mock.ServiceMock)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.Pet.ID.String()).Return(t.Images, nil)
+
+	userRepo := &user_mock.UserRepositoryMock{}
+	userRepo.On("FindById", testifyMock.Anything, t.AdoptByReq.UserID, &model.User{}).Return(&model.User{Email: faker.Email()}, nil)
+
+	controller := gomock.NewController(t.T())
+	emailSrv := email_mock.NewMockService(controller)
+	emailSrv.EXPECT().Send(gomock.Any(), constant.AdoptionStatusTemplate, gomock.Any(), gomock.Any()).Return(errors.New("transport error"))
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, userRepo, emailSrv)
+
+	actual, err := srv.Adopt(context.Background(), t.Pet.ID.String(), t.AdoptByReq)
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), want, actual)
@@ -557,12 +620,12 @@ func (t *PetServiceTest) TestAdoptByPetNotFound() {
 	wantError := status.Error(codes.NotFound, "pet not found")
 	repo := &mock.RepositoryMock{}
 
-	repo.On("FindOne", t.Pet.ID.String(), &model.Pet{}).Return(nil, wantError)
+	repo.On("FindOne", testifyMock.Anything, t.Pet.ID.String(), &model.Pet{}).Return(nil, wantError)
 
 	imgSrv := new(img_mock.ServiceMock)
-	srv := pet.NewService(repo, imgSrv)
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
 
-	actual, err := srv.Adopt(t.Pet.ID.String(), t.AdoptByReq)
+	actual, err := srv.Adopt(context.Background(), t.Pet.ID.String(), t.AdoptByReq)
 
 	assert.NotNil(t.T(), err)
 	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
@@ -575,17 +638,196 @@ func (t *PetServiceTest) TestAdoptByUpdateError() {
 	wantError := &dto.ResponseErr{StatusCode: http.StatusInternalServerError}
 	repo := &mock.RepositoryMock{}
 
-	repo.On("FindOne", t.Pet.ID.String(), &model.Pet{}).Return(t.Pet, nil)
-	repo.On("Update", t.Pet.ID.String(), t.ChangeAdoptBy).Return(nil, errors.New("update error"))
+	repo.On("FindOne", testifyMock.Anything, t.Pet.ID.String(), &model.Pet{}).Return(t.Pet, nil)
+	repo.On("Update", testifyMock.Anything, t.Pet.ID.String(), t.ChangeAdoptBy).Return(nil, errors.New("update error"))
 
 	imgSrv := new(img_mock.ServiceMock)
-	imgSrv.On("FindByPetId", t.Pet.ID.String()).Return(nil, wantError)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.Pet.ID.String()).Return(nil, wantError)
 
-	srv := pet.NewService(repo, imgSrv)
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
 
-	actual, err := srv.Adopt(t.Pet.ID.String(), t.AdoptByReq)
+	actual, err := srv.Adopt(context.Background(), t.Pet.ID.String(), t.AdoptByReq)
 
 	assert.NotNil(t.T(), err)
 	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
 	assert.Nil(t.T(), actual)
 }
+
+func (t *PetServiceTest) TestImportDryRunSuccess() {
+	repo := &mock.RepositoryMock{}
+	imgSrv := new(img_mock.ServiceMock)
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Import(context.Background(), &dto.ImportPetRequest{Filename: "pets.csv", Data: t.ImportCsv, DryRun: true})
+
+	assert.Nil(t.T(), err)
+	assert.True(t.T(), actual.DryRun)
+	assert.Equal(t.T(), 3, actual.Total)
+	assert.Equal(t.T(), 1, actual.Valid)
+	assert.Equal(t.T(), 2, actual.Invalid)
+	assert.Equal(t.T(), 0, actual.Created)
+	assert.Equal(t.T(), "Lucky", actual.Pets[0].Name)
+	assert.Equal(t.T(), "", actual.Pets[0].Id)
+	assert.Equal(t.T(), 3, actual.Errors[0].Row)
+	assert.Equal(t.T(), 5, actual.Errors[1].Row)
+	repo.AssertNotCalled(t.T(), "CreateMany", testifyMock.Anything)
+}
+
+func (t *PetServiceTest) TestImportCommitSuccess() {
+	repo := &mock.RepositoryMock{}
+	repo.On("CreateMany", testifyMock.Anything, testifyMock.AnythingOfType("[]*model.Pet")).Return(nil)
+	imgSrv := new(img_mock.ServiceMock)
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Import(context.Background(), &dto.ImportPetRequest{Filename: "pets.csv", Data: t.ImportCsv})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), 1, actual.Created)
+	assert.Equal(t.T(), 2, actual.Invalid)
+	repo.AssertExpectations(t.T())
+}
+
+func (t *PetServiceTest) TestImportCommitInternalErr() {
+	repo := &mock.RepositoryMock{}
+	repo.On("CreateMany", testifyMock.Anything, testifyMock.AnythingOfType("[]*model.Pet")).Return(errors.New("something wrong"))
+	imgSrv := new(img_mock.ServiceMock)
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Import(context.Background(), &dto.ImportPetRequest{Filename: "pets.csv", Data: t.ImportCsv})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
+}
+
+func (t *PetServiceTest) TestImportXlsxDryRunSuccess() {
+	file := excelize.NewFile()
+	rows := [][]interface{}{
+		{"Type", "Name", "Birthdate", "Gender", "Color", "Pattern", "Habit", "Status", "Is Sterile", "Is Vaccinated", "Is Visible", "Origin"},
+		{"dog", "Lucky", "2020-01-01T00:00:00Z", "male", "brown", "spots", "friendly", "findhome", "yes", "yes", "yes", "shelter"},
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		assert.Nil(t.T(), file.SetSheetRow("Sheet1", cell, &row))
+	}
+	buf, err := file.WriteToBuffer()
+	assert.Nil(t.T(), err)
+
+	repo := &mock.RepositoryMock{}
+	imgSrv := new(img_mock.ServiceMock)
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, errRes := srv.Import(context.Background(), &dto.ImportPetRequest{Filename: "pets.xlsx", Data: buf.Bytes(), DryRun: true})
+
+	assert.Nil(t.T(), errRes)
+	assert.Equal(t.T(), 1, actual.Valid)
+	assert.Equal(t.T(), 0, actual.Invalid)
+	assert.Equal(t.T(), constant.MALE, actual.Pets[0].Gender)
+}
+
+func (t *PetServiceTest) TestImportInvalidFile() {
+	repo := &mock.RepositoryMock{}
+	imgSrv := new(img_mock.ServiceMock)
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Import(context.Background(), &dto.ImportPetRequest{Filename: "pets.txt", Data: t.ImportCsv})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+}
+
+func (t *PetServiceTest) TestExportCsvSuccess() {
+	repo := &mock.RepositoryMock{}
+	repo.On("FindAll", testifyMock.Anything, testifyMock.Anything).Return(&t.Pets, nil)
+	imgSrv := new(img_mock.ServiceMock)
+	for i, p := range t.Pets {
+		imgSrv.On("FindByPetId", testifyMock.Anything, p.ID.String()).Return(t.ImagesList[i], nil)
+	}
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Export(context.Background(), &dto.ExportPetRequest{Format: constant.CSV, Query: &dto.FindAllPetRequest{}})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), "text/csv", actual.ContentType)
+
+	rows, parseErr := csv.NewReader(bytes.NewReader(actual.Data)).ReadAll()
+	assert.Nil(t.T(), parseErr)
+	assert.Len(t.T(), rows, len(t.Pets)+1)
+	assert.Equal(t.T(), "id", rows[0][0])
+	assert.Equal(t.T(), t.Pet.ID.String(), rows[1][0])
+	assert.Equal(t.T(), t.Pet.Name, rows[1][2])
+}
+
+func (t *PetServiceTest) TestExportCsvImportRoundTrip() {
+	repo := &mock.RepositoryMock{}
+	repo.On("FindAll", testifyMock.Anything, testifyMock.Anything).Return(&t.Pets, nil)
+	imgSrv := new(img_mock.ServiceMock)
+	for i, p := range t.Pets {
+		imgSrv.On("FindByPetId", testifyMock.Anything, p.ID.String()).Return(t.ImagesList[i], nil)
+	}
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	exported, err := srv.Export(context.Background(), &dto.ExportPetRequest{Format: constant.CSV, Query: &dto.FindAllPetRequest{}})
+	assert.Nil(t.T(), err)
+
+	actual, err := srv.Import(context.Background(), &dto.ImportPetRequest{Filename: "pets.csv", Data: exported.Data, DryRun: true})
+
+	assert.Nil(t.T(), err)
+	assert.Empty(t.T(), actual.Errors)
+	assert.Equal(t.T(), len(t.Pets), actual.Valid)
+	for i, p := range t.Pets {
+		assert.Equal(t.T(), p.Name, actual.Pets[i].Name)
+		assert.Equal(t.T(), p.Type, actual.Pets[i].Type)
+		assert.Equal(t.T(), p.Gender, actual.Pets[i].Gender)
+		assert.Equal(t.T(), p.Status, actual.Pets[i].Status)
+	}
+}
+
+func (t *PetServiceTest) TestExportPdfSuccess() {
+	photo := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	photoData := bytes.NewBuffer(nil)
+	assert.Nil(t.T(), png.Encode(photoData, photo))
+
+	pets := []*model.Pet{t.Pet}
+	repo := &mock.RepositoryMock{}
+	repo.On("FindAll", testifyMock.Anything, testifyMock.Anything).Return(&pets, nil)
+	imgSrv := new(img_mock.ServiceMock)
+	imgSrv.On("FindByPetId", testifyMock.Anything, t.Pet.ID.String()).Return(t.Images, nil)
+	imgSrv.On("Download", testifyMock.Anything, t.Images[0].ObjectKey).Return(photoData.Bytes(), nil)
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Export(context.Background(), &dto.ExportPetRequest{Format: constant.PDF, Query: &dto.FindAllPetRequest{}})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), "application/pdf", actual.ContentType)
+	assert.True(t.T(), bytes.HasPrefix(actual.Data, []byte("%PDF")))
+	imgSrv.AssertExpectations(t.T())
+}
+
+func (t *PetServiceTest) TestExportPdfTooManyPets() {
+	t.Config.ExportPdfMaxPets = 1
+	repo := &mock.RepositoryMock{}
+	repo.On("FindAll", testifyMock.Anything, testifyMock.Anything).Return(&t.Pets, nil)
+	imgSrv := new(img_mock.ServiceMock)
+	for i, p := range t.Pets {
+		imgSrv.On("FindByPetId", testifyMock.Anything, p.ID.String()).Return(t.ImagesList[i], nil)
+	}
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Export(context.Background(), &dto.ExportPetRequest{Format: constant.PDF, Query: &dto.FindAllPetRequest{}})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+	assert.Equal(t.T(), constant.TooManyPetsForPdfErrorMessage, err.Message)
+	imgSrv.AssertNotCalled(t.T(), "Download", testifyMock.Anything, testifyMock.Anything)
+}
+
+func (t *PetServiceTest) TestExportInvalidFormat() {
+	repo := &mock.RepositoryMock{}
+	imgSrv := new(img_mock.ServiceMock)
+
+	srv := pet.NewService(repo, imgSrv, t.Validator, t.Config, nil, nil)
+	actual, err := srv.Export(context.Background(), &dto.ExportPetRequest{Format: "docx", Query: &dto.FindAllPetRequest{}})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript is a token bucket kept as the time the bucket is full again (GCRA), so it is a single key per client.
+// The clock of redis is used so the replicas agree on the time
+//
+// KEYS[1] the bucket, ARGV[1] the limit, ARGV[2] the window in milliseconds.
+// Returns whether the request is allowed, the remaining requests, the retry after and the reset in milliseconds
+var takeScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local interval = window / limit
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local full = tonumber(redis.call('GET', KEYS[1]) or now)
+if full < now then
+	full = now
+end
+
+local nextFull = full + interval
+local allowAt = nextFull - window
+if now < allowAt then
+	return {0, 0, math.ceil(allowAt - now), math.ceil(full - now)}
+end
+
+redis.call('SET', KEYS[1], tostring(nextFull), 'PX', math.ceil(nextFull - now))
+return {1, math.floor((now - allowAt) / interval), 0, math.ceil(nextFull - now)}
+`)
+
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next request is allowed, zero when this one is
+	RetryAfter time.Duration
+	// Reset is how long until the whole limit is available again
+	Reset time.Duration
+}
+
+type Service interface {
+	Take(ctx context.Context, name string, policy config.RateLimitPolicy, key string) (*Result, error)
+}
+
+type serviceImpl struct {
+	client *redis.Client
+}
+
+func NewService(client *redis.Client) Service {
+	return &serviceImpl{client: client}
+}
+
+// Take counts a request of the key against the policy, the buckets are shared by every replica through redis
+func (s *serviceImpl) Take(ctx context.Context, name string, policy config.RateLimitPolicy, key string) (*Result, error) {
+	window := time.Duration(policy.Window) * time.Second
+	values, err := takeScript.Run(ctx, s.client, []string{bucketKey(name, key)}, policy.Limit, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		Reset:      time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+func bucketKey(name string, key string) string {
+	return fmt.Sprintf("ratelimit:%s:%s", name, key)
+}
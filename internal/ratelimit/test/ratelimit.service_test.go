@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/internal/ratelimit"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimitServiceTest struct {
+	suite.Suite
+	server  *miniredis.Miniredis
+	now     time.Time
+	service ratelimit.Service
+	policy  config.RateLimitPolicy
+}
+
+func TestRateLimitService(t *testing.T) {
+	suite.Run(t, new(RateLimitServiceTest))
+}
+
+func (t *RateLimitServiceTest) SetupTest() {
+	t.server = miniredis.RunT(t.T())
+	t.now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t.server.SetTime(t.now)
+
+	client := redis.NewClient(&redis.Options{Addr: t.server.Addr()})
+	t.T().Cleanup(func() { client.Close() })
+	t.service = ratelimit.NewService(client)
+
+	// a request every 2 seconds, 5 at once
+	t.policy = config.RateLimitPolicy{Limit: 5, Window: 10, Key: "ip"}
+}
+
+// advance moves both the clock the script reads with TIME and the expiry of the keys
+func (t *RateLimitServiceTest) advance(d time.Duration) {
+	t.now = t.now.Add(d)
+	t.server.SetTime(t.now)
+	t.server.FastForward(d)
+}
+
+func (t *RateLimitServiceTest) take() *ratelimit.Result {
+	result, err := t.service.Take(context.Background(), "default", t.policy, "ip:10.0.0.1")
+	assert.Nil(t.T(), err)
+	return result
+}
+
+func (t *RateLimitServiceTest) TestBurst() {
+	for remaining := 4; remaining >= 0; remaining-- {
+		result := t.take()
+		assert.True(t.T(), result.Allowed)
+		assert.Equal(t.T(), remaining, result.Remaining)
+		assert.Equal(t.T(), time.Duration(0), result.RetryAfter)
+	}
+
+	result := t.take()
+
+	assert.Equal(t.T(), &ratelimit.Result{
+		Allowed:    false,
+		Remaining:  0,
+		RetryAfter: 2 * time.Second,
+		Reset:      10 * time.Second,
+	}, result)
+}
+
+func (t *RateLimitServiceTest) TestRejectedRequestIsNotCounted() {
+	for i := 0; i < 5; i++ {
+		t.take()
+	}
+	t.take()
+	t.take()
+
+	t.advance(2 * time.Second)
+
+	assert.True(t.T(), t.take().Allowed)
+}
+
+func (t *RateLimitServiceTest) TestRefill() {
+	for i := 0; i < 5; i++ {
+		t.take()
+	}
+
+	t.advance(1500 * time.Millisecond)
+	result := t.take()
+	assert.False(t.T(), result.Allowed)
+	assert.Equal(t.T(), 500*time.Millisecond, result.RetryAfter)
+
+	t.advance(500 * time.Millisecond)
+	result = t.take()
+	assert.True(t.T(), result.Allowed)
+	assert.Equal(t.T(), 0, result.Remaining)
+	assert.Equal(t.T(), 10*time.Second, result.Reset)
+
+	t.advance(4 * time.Second)
+	result = t.take()
+	assert.True(t.T(), result.Allowed)
+	assert.Equal(t.T(), 1, result.Remaining)
+}
+
+func (t *RateLimitServiceTest) TestReset() {
+	result := t.take()
+	assert.Equal(t.T(), 2*time.Second, result.Reset)
+
+	t.take()
+	result = t.take()
+	assert.Equal(t.T(), 6*time.Second, result.Reset)
+
+	// the bucket is kept until it is full again
+	assert.Equal(t.T(), 6*time.Second, t.server.TTL("ratelimit:default:ip:10.0.0.1"))
+
+	t.advance(6 * time.Second)
+	assert.False(t.T(), t.server.Exists("ratelimit:default:ip:10.0.0.1"))
+
+	result = t.take()
+	assert.True(t.T(), result.Allowed)
+	assert.Equal(t.T(), 4, result.Remaining)
+}
+
+func (t *RateLimitServiceTest) TestKeysAreSeparate() {
+	for i := 0; i < 5; i++ {
+		t.take()
+	}
+
+	result, err := t.service.Take(context.Background(), "default", t.policy, "ip:10.0.0.2")
+
+	assert.Nil(t.T(), err)
+	assert.True(t.T(), result.Allowed)
+	assert.Equal(t.T(), 4, result.Remaining)
+}
@@ -0,0 +1,65 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// Header is the header the request id is read from and returned in
+const Header = "X-Request-ID"
+
+// maxLength bounds the ids accepted from callers so they can't flood the logs
+const maxLength = 128
+
+type contextKey struct{}
+
+// WithContext stores the request id in the context, the repositories and services log it with Ctx
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id stored in the context, or an empty string outside of a request
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New generates a request id for the requests that don't come with one
+func New() string {
+	return uuid.New().String()
+}
+
+// IsValid reports whether an id given by the caller is safe to log, only short ids of letters, digits and
+// the separators used by proxies and tracers are kept
+func IsValid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// LogHook adds the request id to the log lines that were given the context of a request with Ctx
+type LogHook struct{}
+
+func (h LogHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	ctx := e.GetCtx()
+	if ctx == nil {
+		return
+	}
+
+	if id := FromContext(ctx); id != "" {
+		e.Str("request_id", id)
+	}
+}
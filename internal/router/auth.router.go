@@ -15,3 +15,17 @@ func (r *FiberRouter) PutAuth(path string, h func(ctx IContext)) {
 		return nil
 	})
 }
+
+func (r *FiberRouter) GetAuth(path string, h func(ctx IContext)) {
+	r.auth.Get(path, func(c *fiber.Ctx) error {
+		h(NewFiberCtx(c))
+		return nil
+	})
+}
+
+func (r *FiberRouter) DeleteAuth(path string, h func(ctx IContext)) {
+	r.auth.Delete(path, func(c *fiber.Ctx) error {
+		h(NewFiberCtx(c))
+		return nil
+	})
+}
@@ -2,9 +2,12 @@ package router
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,10 +17,17 @@ import (
 )
 
 type IContext interface {
+	// UserID is empty on the paths excluded from the guard
 	UserID() string
+	// ApiKeyID is the id of the api key the request was authorized with, empty otherwise
+	ApiKeyID() string
 	Role() string
+	AuthSessionID() string
+	// RequestID is the X-Request-ID given by the caller or assigned by the request id middleware
+	RequestID() string
 	Bind(interface{}) error
 	JSON(int, interface{})
+	Attachment(string, string, []byte)
 	ID() (string, error)
 	Param(string) (string, error)
 	Token() string
@@ -28,6 +38,12 @@ type IContext interface {
 	Queries() map[string]string
 	File(string, map[string]struct{}, int64) (*dto.DecomposedFile, error)
 	GetFormData(string) string
+	IP() string
+	UserAgent() string
+	SetHeader(string, string)
+	Header(string) string
+	// UserContext is the context of the request, it carries the trace of the request down to the repositories
+	UserContext() context.Context
 }
 
 type FiberCtx struct {
@@ -39,13 +55,28 @@ func NewFiberCtx(c *fiber.Ctx) *FiberCtx {
 }
 
 func (c *FiberCtx) UserID() string {
-	return c.Ctx.Locals("UserId").(string)
+	id, _ := c.Ctx.Locals("UserId").(string)
+	return id
+}
+
+func (c *FiberCtx) ApiKeyID() string {
+	id, _ := c.Ctx.Locals("ApiKeyId").(string)
+	return id
 }
 
 func (c *FiberCtx) Role() string {
 	return c.Ctx.Locals("Role").(string)
 }
 
+func (c *FiberCtx) AuthSessionID() string {
+	return c.Ctx.Locals("AuthSessionId").(string)
+}
+
+func (c *FiberCtx) RequestID() string {
+	id, _ := c.Ctx.Locals("RequestId").(string)
+	return id
+}
+
 func (c *FiberCtx) Bind(v interface{}) error {
 	return c.Ctx.BodyParser(v)
 }
@@ -54,6 +85,12 @@ func (c *FiberCtx) JSON(statusCode int, v interface{}) {
 	c.Ctx.Status(statusCode).JSON(v)
 }
 
+func (c *FiberCtx) Attachment(filename string, contentType string, data []byte) {
+	c.Ctx.Attachment(filename)
+	c.Ctx.Set(fiber.HeaderContentType, contentType)
+	c.Ctx.Status(http.StatusOK).Send(data)
+}
+
 func (c *FiberCtx) ID() (id string, err error) {
 	id = c.Params("id")
 
@@ -139,8 +176,37 @@ func (c *FiberCtx) GetFormData(key string) string {
 	return c.Ctx.FormValue(key)
 }
 
+func (c *FiberCtx) IP() string {
+	return c.Ctx.IP()
+}
+
+func (c *FiberCtx) UserAgent() string {
+	return c.Ctx.Get(fiber.HeaderUserAgent)
+}
+
+func (c *FiberCtx) SetHeader(key string, value string) {
+	c.Ctx.Set(key, value)
+}
+
+func (c *FiberCtx) Header(key string) string {
+	return c.Ctx.Get(key)
+}
+
 //func (c *FiberCtx) Next() {
 //	err := c.Ctx.Next()
 //	fmt.Println(c.Route().Path)
 //	fmt.Println("next error:", err)
 //}
+
+// RequestMeta collects the client details of the request
+func RequestMeta(c IContext) *dto.RequestMeta {
+	return &dto.RequestMeta{IP: c.IP(), UserAgent: c.UserAgent()}
+}
+
+// JSONError writes the error response, setting Retry-After when the error carries one
+func JSONError(c IContext, respErr *dto.ResponseErr) {
+	if data, ok := respErr.Data.(*dto.RetryAfterData); ok {
+		c.SetHeader(fiber.HeaderRetryAfter, strconv.Itoa(data.RetryAfter))
+	}
+	c.JSON(respErr.StatusCode, respErr)
+}
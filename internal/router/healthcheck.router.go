@@ -4,7 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func (r *FiberRouter) GetHealthCheck(path string, h func(ctx *FiberCtx)) {
+func (r *FiberRouter) GetHealthCheck(path string, h func(ctx IContext)) {
 	r.Get(path, func(c *fiber.Ctx) error {
 		h(NewFiberCtx(c))
 		return nil
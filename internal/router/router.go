@@ -1,12 +1,20 @@
 package router
 
 import (
+	"context"
+	"time"
+
 	swagger "github.com/arsmn/fiber-swagger/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/isd-sgcu/johnjud-backend/config"
 	_ "github.com/isd-sgcu/johnjud-backend/docs"
+	deadlineMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/deadline"
+	forwardedMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/forwarded"
+	loggingMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/logging"
+	metricsMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/metrics"
+	requestIdMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/requestid"
+	tracingMiddleware "github.com/isd-sgcu/johnjud-backend/internal/middleware/tracing"
 )
 
 type FiberRouter struct {
@@ -21,11 +29,14 @@ type IGuard interface {
 	Use(IContext) error
 }
 
-func NewAPIv1(r *FiberRouter, conf config.App) *fiber.App {
+type IRateLimiter interface {
+	IGuard
+	UseBeforeGuard(IContext) error
+}
+
+// NewAPIv1 mounts the routes under /v1. The requests are cancelled when ctx is, see the deadline middleware
+func NewAPIv1(ctx context.Context, r *FiberRouter, conf config.App) *fiber.App {
 	if conf.IsDevelopment() {
-		r.Use(logger.New(logger.Config{Next: func(c *fiber.Ctx) bool {
-			return c.Path() == "/v1/"
-		}}))
 		r.Get("/docs/*", swagger.HandlerDefault)
 	}
 
@@ -33,33 +44,56 @@ func NewAPIv1(r *FiberRouter, conf config.App) *fiber.App {
 		StrictRouting:     true,
 		AppName:           "JohnJud API",
 		EnablePrintRoutes: conf.IsDevelopment(),
+		// the ip keys the throttle and the rate limits, so the proxy header is only read from the trusted proxies
+		ProxyHeader:             conf.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          conf.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
+	app.Use(forwardedMiddleware.New(conf.ProxyHeader, conf.TrustedProxies))
+	app.Use(requestIdMiddleware.New())
+	app.Use(deadlineMiddleware.New(ctx, time.Duration(conf.RequestTimeout)*time.Second))
+	app.Use(loggingMiddleware.New("/v1/", "/v1/healthz", "/v1/readyz"))
+	app.Use(tracingMiddleware.New())
+	app.Use(metricsMiddleware.New())
 	app.Mount("/v1", r.App)
 
 	return app
 }
 
-func NewFiberRouter(authGuard IGuard, conf config.App) *FiberRouter {
+// NewFiberRouter groups the routes behind the guard. The rate limiter runs before it to count every request by ip,
+// and after it to count the requests by user or api key
+func NewFiberRouter(authGuard IGuard, rateLimiter IRateLimiter, conf config.App) *FiberRouter {
 	r := fiber.New(fiber.Config{
-		BodyLimit: int(conf.MaxFileSize * 1024 * 1024),
+		BodyLimit:               int(conf.MaxFileSize * 1024 * 1024),
+		ProxyHeader:             conf.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          conf.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 	}))
 
-	auth := GroupWithAuthMiddleware(r, "/auth", authGuard.Use)
-	user := GroupWithAuthMiddleware(r, "/user", authGuard.Use)
-	pet := GroupWithAuthMiddleware(r, "/pets", authGuard.Use)
+	auth := GroupWithAuthMiddleware(r, "/auth", rateLimiter.UseBeforeGuard, authGuard.Use, rateLimiter.Use)
+	user := GroupWithAuthMiddleware(r, "/user", rateLimiter.UseBeforeGuard, authGuard.Use, rateLimiter.Use)
+	pet := GroupWithAuthMiddleware(r, "/pets", rateLimiter.UseBeforeGuard, authGuard.Use, rateLimiter.Use)
 
-	image := GroupWithAuthMiddleware(r, "/images", authGuard.Use)
+	image := GroupWithAuthMiddleware(r, "/images", rateLimiter.UseBeforeGuard, authGuard.Use, rateLimiter.Use)
 
 	return &FiberRouter{r, auth, user, pet, image}
 }
 
-func GroupWithAuthMiddleware(r *fiber.App, path string, middleware func(ctx IContext) error) fiber.Router {
-	return r.Group(path, func(c *fiber.Ctx) error {
-		return middleware(NewFiberCtx(c))
-	})
+func GroupWithAuthMiddleware(r *fiber.App, path string, middlewares ...func(ctx IContext) error) fiber.Router {
+	handlers := make([]fiber.Handler, 0, len(middlewares))
+	for _, middleware := range middlewares {
+		middleware := middleware
+		handlers = append(handlers, func(c *fiber.Ctx) error {
+			return middleware(NewFiberCtx(c))
+		})
+	}
+
+	return r.Group(path, handlers...)
 }
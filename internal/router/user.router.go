@@ -22,3 +22,17 @@ func (r *FiberRouter) DeleteUser(path string, h func(ctx IContext)) {
 		return nil
 	})
 }
+
+func (r *FiberRouter) PatchUser(path string, h func(ctx IContext)) {
+	r.user.Patch(path, func(c *fiber.Ctx) error {
+		h(NewFiberCtx(c))
+		return nil
+	})
+}
+
+func (r *FiberRouter) PostUser(path string, h func(ctx IContext)) {
+	r.user.Post(path, func(c *fiber.Ctx) error {
+		h(NewFiberCtx(c))
+		return nil
+	})
+}
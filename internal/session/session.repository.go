@@ -0,0 +1,28 @@
+package session
+
+import (
+	"context"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindByUserId(ctx context.Context, userId string, result *[]*model.AuthSession) error
+	Delete(ctx context.Context, id string) error
+}
+
+type repositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repositoryImpl{Db: db}
+}
+
+func (r *repositoryImpl) FindByUserId(ctx context.Context, userId string, result *[]*model.AuthSession) error {
+	return r.Db.WithContext(ctx).Order("created_at desc").Find(result, "user_id = ?", userId).Error
+}
+
+func (r *repositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.Db.WithContext(ctx).Delete(&model.AuthSession{}, "id = ?", id).Error
+}
@@ -0,0 +1,91 @@
+package session
+
+import (
+	"context"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/token"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+type Service interface {
+	FindByUserId(ctx context.Context, userId string) ([]*dto.Session, *dto.ResponseErr)
+	RevokeByUserId(ctx context.Context, userId string, exceptSessionId string) *dto.ResponseErr
+}
+
+type serviceImpl struct {
+	repository   Repository
+	tokenService token.Service
+}
+
+func NewService(repository Repository, tokenService token.Service) Service {
+	return &serviceImpl{repository: repository, tokenService: tokenService}
+}
+
+func (s *serviceImpl) FindByUserId(ctx context.Context, userId string) ([]*dto.Session, *dto.ResponseErr) {
+	var sessions []*model.AuthSession
+	if err := s.repository.FindByUserId(ctx, userId, &sessions); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "session").
+			Str("module", "find by user id").
+			Str("userId", userId).
+			Msg("Error finding auth sessions")
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return RawToDtoList(sessions), nil
+}
+
+// RevokeByUserId removes the tokens and the auth session of every session of the user except exceptSessionId,
+// pass an empty exceptSessionId to revoke all of them
+func (s *serviceImpl) RevokeByUserId(ctx context.Context, userId string, exceptSessionId string) *dto.ResponseErr {
+	var sessions []*model.AuthSession
+	if err := s.repository.FindByUserId(ctx, userId, &sessions); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "session").
+			Str("module", "revoke by user id").
+			Str("userId", userId).
+			Msg("Error finding auth sessions")
+		return dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	for _, session := range sessions {
+		sessionId := session.ID.String()
+		if sessionId == exceptSessionId {
+			continue
+		}
+
+		if err := s.tokenService.RemoveCredential(ctx, sessionId); err != nil {
+			log.Error().Ctx(ctx).Err(err).
+				Str("service", "session").
+				Str("module", "revoke by user id").
+				Str("sessionId", sessionId).
+				Msg("Error removing credential")
+			return dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+
+		if err := s.repository.Delete(ctx, sessionId); err != nil {
+			log.Error().Ctx(ctx).Err(err).
+				Str("service", "session").
+				Str("module", "revoke by user id").
+				Str("sessionId", sessionId).
+				Msg("Error deleting auth session")
+			return dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+	}
+
+	return nil
+}
+
+func RawToDtoList(in []*model.AuthSession) []*dto.Session {
+	result := []*dto.Session{}
+	for _, session := range in {
+		result = append(result, &dto.Session{
+			Id:        session.ID.String(),
+			CreatedAt: session.CreatedAt,
+		})
+	}
+
+	return result
+}
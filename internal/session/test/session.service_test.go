@@ -0,0 +1,116 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/session"
+	mock_session "github.com/isd-sgcu/johnjud-backend/mocks/repository/session"
+	mock_token "github.com/isd-sgcu/johnjud-backend/mocks/service/token"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionServiceTest struct {
+	suite.Suite
+	userId   string
+	sessions []*model.AuthSession
+}
+
+func TestSessionService(t *testing.T) {
+	suite.Run(t, new(SessionServiceTest))
+}
+
+func (t *SessionServiceTest) SetupTest() {
+	userId := uuid.New()
+
+	t.userId = userId.String()
+	t.sessions = []*model.AuthSession{
+		{Base: model.Base{ID: uuid.New(), CreatedAt: time.Now()}, UserID: userId},
+		{Base: model.Base{ID: uuid.New(), CreatedAt: time.Now().Add(-time.Hour)}, UserID: userId},
+	}
+}
+
+func (t *SessionServiceTest) TestFindByUserIdSuccess() {
+	controller := gomock.NewController(t.T())
+	repository := mock_session.NewMockRepository(controller)
+	tokenService := mock_token.NewMockService(controller)
+
+	repository.EXPECT().FindByUserId(gomock.Any(), t.userId, gomock.Any()).SetArg(2, t.sessions).Return(nil)
+
+	service := session.NewService(repository, tokenService)
+	actual, err := service.FindByUserId(context.Background(), t.userId)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), session.RawToDtoList(t.sessions), actual)
+}
+
+func (t *SessionServiceTest) TestFindByUserIdInternalErr() {
+	controller := gomock.NewController(t.T())
+	repository := mock_session.NewMockRepository(controller)
+	tokenService := mock_token.NewMockService(controller)
+
+	repository.EXPECT().FindByUserId(gomock.Any(), t.userId, gomock.Any()).Return(errors.New("Connection lost"))
+
+	service := session.NewService(repository, tokenService)
+	actual, err := service.FindByUserId(context.Background(), t.userId)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
+}
+
+func (t *SessionServiceTest) TestRevokeAllSuccess() {
+	controller := gomock.NewController(t.T())
+	repository := mock_session.NewMockRepository(controller)
+	tokenService := mock_token.NewMockService(controller)
+
+	repository.EXPECT().FindByUserId(gomock.Any(), t.userId, gomock.Any()).SetArg(2, t.sessions).Return(nil)
+	for _, s := range t.sessions {
+		tokenService.EXPECT().RemoveCredential(gomock.Any(), s.ID.String()).Return(nil)
+		repository.EXPECT().Delete(gomock.Any(), s.ID.String()).Return(nil)
+	}
+
+	service := session.NewService(repository, tokenService)
+	err := service.RevokeByUserId(context.Background(), t.userId, "")
+
+	assert.Nil(t.T(), err)
+}
+
+func (t *SessionServiceTest) TestRevokeExceptCurrentSuccess() {
+	controller := gomock.NewController(t.T())
+	repository := mock_session.NewMockRepository(controller)
+	tokenService := mock_token.NewMockService(controller)
+
+	current := t.sessions[0].ID.String()
+	other := t.sessions[1].ID.String()
+
+	repository.EXPECT().FindByUserId(gomock.Any(), t.userId, gomock.Any()).SetArg(2, t.sessions).Return(nil)
+	tokenService.EXPECT().RemoveCredential(gomock.Any(), other).Return(nil)
+	repository.EXPECT().Delete(gomock.Any(), other).Return(nil)
+
+	service := session.NewService(repository, tokenService)
+	err := service.RevokeByUserId(context.Background(), t.userId, current)
+
+	assert.Nil(t.T(), err)
+}
+
+func (t *SessionServiceTest) TestRevokeRemoveCredentialErr() {
+	controller := gomock.NewController(t.T())
+	repository := mock_session.NewMockRepository(controller)
+	tokenService := mock_token.NewMockService(controller)
+
+	repository.EXPECT().FindByUserId(gomock.Any(), t.userId, gomock.Any()).SetArg(2, t.sessions).Return(nil)
+	tokenService.EXPECT().RemoveCredential(gomock.Any(), t.sessions[0].ID.String()).Return(errors.New("Connection lost"))
+
+	service := session.NewService(repository, tokenService)
+	err := service.RevokeByUserId(context.Background(), t.userId, "")
+
+	assert.Equal(t.T(), &dto.ResponseErr{StatusCode: http.StatusInternalServerError, Message: "Internal server error"}, err)
+}
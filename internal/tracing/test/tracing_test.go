@@ -0,0 +1,66 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/isd-sgcu/johnjud-backend/internal/tracing"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type TracingTest struct {
+	suite.Suite
+	recorder *tracetest.SpanRecorder
+	out      *bytes.Buffer
+	logger   zerolog.Logger
+}
+
+func TestTracing(t *testing.T) {
+	suite.Run(t, new(TracingTest))
+}
+
+func (t *TracingTest) SetupTest() {
+	t.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(t.recorder)))
+
+	t.out = &bytes.Buffer{}
+	t.logger = zerolog.New(t.out).Hook(tracing.LogHook{})
+}
+
+func (t *TracingTest) TestLogHookAddsTraceIds() {
+	ctx, span := tracing.Start(context.Background(), "pet.FindAll")
+	t.logger.Error().Ctx(ctx).Msg("Error while querying all pets")
+	span.End()
+
+	line := map[string]string{}
+	assert.Nil(t.T(), json.Unmarshal(t.out.Bytes(), &line))
+	assert.Equal(t.T(), span.SpanContext().TraceID().String(), line["trace_id"])
+	assert.Equal(t.T(), span.SpanContext().SpanID().String(), line["span_id"])
+}
+
+func (t *TracingTest) TestLogHookWithoutTrace() {
+	t.logger.Error().Ctx(context.Background()).Msg("Error while querying all pets")
+	t.logger.Error().Msg("Error while querying all pets")
+
+	assert.NotContains(t.T(), t.out.String(), "trace_id")
+}
+
+func (t *TracingTest) TestStartChildOnlyInsideTrace() {
+	_, _, ok := tracing.StartChild(context.Background(), "gorm.query")
+	assert.False(t.T(), ok)
+
+	ctx, parent := tracing.Start(context.Background(), "pet.FindAll")
+	_, child, ok := tracing.StartChild(ctx, "gorm.query")
+	child.End()
+	parent.End()
+
+	assert.True(t.T(), ok)
+	assert.Len(t.T(), t.recorder.Ended(), 2)
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/isd-sgcu/johnjud-backend"
+
+// Init sets the global tracer provider and the w3c trace context propagator. The returned function flushes the
+// spans that are still buffered, it does nothing when tracing is disabled
+func Init(ctx context.Context, conf config.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !conf.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(conf.Endpoint)}
+	if conf.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(conf.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span named after the service and method, e.g. pet.FindAll
+func Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, options...)
+}
+
+// StartChild only starts a span inside of a trace, so the queries of the migrations and the workers are not
+// exported as traces of their own
+func StartChild(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span, bool) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx), false
+	}
+
+	ctx, span := Tracer().Start(ctx, name, options...)
+	return ctx, span, true
+}
+
+// End records the error on the span before ending it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogHook adds the trace and span ids to the log lines that were given the context of a traced request with Ctx
+type LogHook struct{}
+
+func (h LogHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	ctx := e.GetCtx()
+	if ctx == nil {
+		return
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return
+	}
+
+	e.Str("trace_id", spanContext.TraceID().String()).
+		Str("span_id", spanContext.SpanID().String())
+}
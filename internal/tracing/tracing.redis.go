@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook starts a span for every command sent with the context of a traced request
+type RedisHook struct{}
+
+func NewRedisHook() redis.Hook {
+	return &RedisHook{}
+}
+
+func (h *RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span, ok := StartChild(ctx, "redis."+cmd.Name(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperation(cmd.Name())),
+		)
+		if !ok {
+			return next(ctx, cmd)
+		}
+
+		err := next(ctx, cmd)
+		// a missing key is an expected answer, not a failed command
+		if errors.Is(err, redis.Nil) {
+			End(span, nil)
+		} else {
+			End(span, err)
+		}
+		return err
+	}
+}
+
+func (h *RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span, ok := StartChild(ctx, "redis.pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemRedis),
+		)
+		if !ok {
+			return next(ctx, cmds)
+		}
+
+		err := next(ctx, cmds)
+		End(span, err)
+		return err
+	}
+}
@@ -1,21 +1,34 @@
 package user
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/constant"
+	emailSvc "github.com/isd-sgcu/johnjud-backend/internal/auth/email"
 	"github.com/isd-sgcu/johnjud-backend/internal/dto"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
 	"github.com/isd-sgcu/johnjud-backend/internal/user"
+	petMock "github.com/isd-sgcu/johnjud-backend/mocks/repository/pet"
 	mock "github.com/isd-sgcu/johnjud-backend/mocks/repository/user"
+	emailMock "github.com/isd-sgcu/johnjud-backend/mocks/service/email"
+	eventMock "github.com/isd-sgcu/johnjud-backend/mocks/service/securityevent"
+	sessionMock "github.com/isd-sgcu/johnjud-backend/mocks/service/session"
+	tokenMock "github.com/isd-sgcu/johnjud-backend/mocks/service/token"
 	"github.com/isd-sgcu/johnjud-backend/mocks/utils"
 
 	"github.com/go-faker/faker/v4"
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 )
 
@@ -27,6 +40,7 @@ type UserServiceTest struct {
 	UserDtoNoPassword *dto.User
 	HashedPassword    string
 	UpdateUserReqMock *dto.UpdateUserRequest
+	Meta              *dto.RequestMeta
 }
 
 func TestUserService(t *testing.T) {
@@ -62,20 +76,19 @@ func (t *UserServiceTest) SetupTest() {
 		Lastname:  t.User.Lastname,
 	}
 
+	firstname := faker.FirstName()
+	lastname := faker.LastName()
 	t.UpdateUserReqMock = &dto.UpdateUserRequest{
-		Email:     t.User.Email,
-		Password:  t.User.Password,
-		Firstname: t.User.Firstname,
-		Lastname:  t.User.Lastname,
+		Firstname: &firstname,
+		Lastname:  &lastname,
 	}
 
 	t.HashedPassword = faker.Password()
+	t.Meta = &dto.RequestMeta{IP: faker.IPv4(), UserAgent: "Mozilla/5.0"}
 
 	t.UpdateUser = &model.User{
-		Email:     t.User.Email,
-		Password:  t.HashedPassword,
-		Firstname: t.User.Firstname,
-		Lastname:  t.User.Lastname,
+		Firstname: firstname,
+		Lastname:  lastname,
 	}
 }
 
@@ -83,11 +96,11 @@ func (t *UserServiceTest) TestFindOneSuccess() {
 	want := t.UserDtoNoPassword
 
 	repo := &mock.UserRepositoryMock{}
-	repo.On("FindById", t.User.ID.String(), &model.User{}).Return(t.User, nil)
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
 
 	brcyptUtil := &utils.BcryptUtilMock{}
-	srv := user.NewService(repo, brcyptUtil)
-	actual, err := srv.FindOne(t.User.ID.String())
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.FindOne(context.Background(), t.User.ID.String())
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), want, actual)
@@ -95,11 +108,11 @@ func (t *UserServiceTest) TestFindOneSuccess() {
 
 func (t *UserServiceTest) TestFindOneNotFoundErr() {
 	repo := &mock.UserRepositoryMock{}
-	repo.On("FindById", t.User.ID.String(), &model.User{}).Return(nil, gorm.ErrRecordNotFound)
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(nil, gorm.ErrRecordNotFound)
 
 	brcyptUtil := &utils.BcryptUtilMock{}
-	srv := user.NewService(repo, brcyptUtil)
-	actual, err := srv.FindOne(t.User.ID.String())
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.FindOne(context.Background(), t.User.ID.String())
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
@@ -107,27 +120,31 @@ func (t *UserServiceTest) TestFindOneNotFoundErr() {
 
 func (t *UserServiceTest) TestFindOneInternalErr() {
 	repo := &mock.UserRepositoryMock{}
-	repo.On("FindById", t.User.ID.String(), &model.User{}).Return(nil, errors.New("Not found user"))
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(nil, errors.New("Not found user"))
 
 	brcyptUtil := &utils.BcryptUtilMock{}
-	srv := user.NewService(repo, brcyptUtil)
-	actual, err := srv.FindOne(t.User.ID.String())
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.FindOne(context.Background(), t.User.ID.String())
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
 }
 
 func (t *UserServiceTest) TestUpdateSuccess() {
-	want := t.UserDtoNoPassword
+	updated := *t.User
+	updated.Firstname = t.UpdateUser.Firstname
+	updated.Lastname = t.UpdateUser.Lastname
+
+	want := &dto.UpdateUserResponse{User: *user.RawToDto(&updated)}
 
 	repo := &mock.UserRepositoryMock{}
-	repo.On("Update", t.User.ID.String(), t.UpdateUser).Return(t.User, nil)
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+	repo.On("Update", testifyMock.Anything, t.User.ID.String(), t.UpdateUser).Return(&updated, nil)
 
 	brcyptUtil := &utils.BcryptUtilMock{}
-	brcyptUtil.On("GenerateHashedPassword", t.User.Password).Return(t.HashedPassword, nil)
 
-	srv := user.NewService(repo, brcyptUtil)
-	actual, err := srv.Update(t.User.ID.String(), t.UpdateUserReqMock)
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.Update(context.Background(), t.User.ID.String(), t.UpdateUserReqMock)
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), want, actual)
@@ -135,27 +152,166 @@ func (t *UserServiceTest) TestUpdateSuccess() {
 
 func (t *UserServiceTest) TestUpdateInternalErr() {
 	repo := &mock.UserRepositoryMock{}
-	repo.On("Update", t.User.ID.String(), t.UpdateUser).Return(nil, errors.New("Not found user"))
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+	repo.On("Update", testifyMock.Anything, t.User.ID.String(), t.UpdateUser).Return(nil, errors.New("Not found user"))
 
 	brcyptUtil := &utils.BcryptUtilMock{}
-	brcyptUtil.On("GenerateHashedPassword", t.User.Password).Return(t.HashedPassword, nil)
 
-	srv := user.NewService(repo, brcyptUtil)
-	actual, err := srv.Update(t.User.ID.String(), t.UpdateUserReqMock)
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.Update(context.Background(), t.User.ID.String(), t.UpdateUserReqMock)
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
 }
 
+func (t *UserServiceTest) TestUpdateEmailSendsVerification() {
+	controller := gomock.NewController(t.T())
+	newEmail := faker.Email()
+	verifyToken := faker.UUIDDigit()
+	conf := config.Auth{ClientURL: "https://johnjud.example"}
+
+	want := &dto.UpdateUserResponse{User: *t.UserDto, PendingEmail: newEmail}
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+	repo.On("FindByEmail", testifyMock.Anything, newEmail, &model.User{}).Return(nil, gorm.ErrRecordNotFound)
+
+	tokenService := tokenMock.NewMockService(controller)
+	tokenService.EXPECT().CreateVerifyEmailToken(gomock.Any(), t.User.ID.String(), newEmail).Return(verifyToken, nil)
+
+	emailService := emailMock.NewMockService(controller)
+	emailService.EXPECT().Send(gomock.Any(), constant.VerifyEmailTemplate, &emailSvc.Recipient{Name: t.User.Firstname, Address: newEmail, Locale: t.User.Locale}, gomock.Any()).Return(nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, nil, nil, tokenService, emailService, nil, conf)
+	actual, err := srv.Update(context.Background(), t.User.ID.String(), &dto.UpdateUserRequest{Email: &newEmail})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), want, actual)
+}
+
+func (t *UserServiceTest) TestUpdateEmailDuplicate() {
+	controller := gomock.NewController(t.T())
+	newEmail := faker.Email()
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+	repo.On("FindByEmail", testifyMock.Anything, newEmail, &model.User{}).Return(&model.User{Email: newEmail}, nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, nil, nil, tokenMock.NewMockService(controller), emailMock.NewMockService(controller), nil, config.Auth{})
+	actual, err := srv.Update(context.Background(), t.User.ID.String(), &dto.UpdateUserRequest{Email: &newEmail})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusConflict, err.StatusCode)
+}
+
+func (t *UserServiceTest) TestChangePasswordSuccess() {
+	controller := gomock.NewController(t.T())
+	authSessionId := faker.UUIDDigit()
+	request := &dto.ChangePasswordRequest{CurrentPassword: faker.Password(), NewPassword: faker.Password()}
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+	repo.On("Update", testifyMock.Anything, t.User.ID.String(), &model.User{Password: t.HashedPassword}).Return(t.User, nil)
+
+	brcyptUtil := &utils.BcryptUtilMock{}
+	brcyptUtil.On("CompareHashedPassword", t.User.Password, request.CurrentPassword).Return(nil)
+	brcyptUtil.On("CompareHashedPassword", t.User.Password, request.NewPassword).Return(errors.New("Mismatched password"))
+	brcyptUtil.On("GenerateHashedPassword", request.NewPassword).Return(t.HashedPassword, nil)
+
+	sessionService := sessionMock.NewMockService(controller)
+	sessionService.EXPECT().RevokeByUserId(gomock.Any(), t.User.ID.String(), authSessionId).Return(nil)
+
+	eventService := eventMock.NewMockService(controller)
+	eventService.EXPECT().Record(gomock.Any(), constant.ChangePasswordEvent, t.User.ID.String(), t.User.Email, t.Meta, true)
+
+	srv := user.NewService(repo, brcyptUtil, sessionService, nil, nil, nil, eventService, config.Auth{})
+	actual, err := srv.ChangePassword(context.Background(), t.User.ID.String(), authSessionId, request, t.Meta)
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.ChangePasswordResponse{IsSuccess: true}, actual)
+}
+
+func (t *UserServiceTest) TestChangePasswordIncorrectCurrent() {
+	controller := gomock.NewController(t.T())
+	request := &dto.ChangePasswordRequest{CurrentPassword: faker.Password(), NewPassword: faker.Password()}
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+
+	brcyptUtil := &utils.BcryptUtilMock{}
+	brcyptUtil.On("CompareHashedPassword", t.User.Password, request.CurrentPassword).Return(errors.New("Mismatched password"))
+
+	eventService := eventMock.NewMockService(controller)
+	eventService.EXPECT().Record(gomock.Any(), constant.ChangePasswordEvent, t.User.ID.String(), t.User.Email, t.Meta, false)
+
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, eventService, config.Auth{})
+	actual, err := srv.ChangePassword(context.Background(), t.User.ID.String(), faker.UUIDDigit(), request, t.Meta)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusForbidden, err.StatusCode)
+}
+
+func (t *UserServiceTest) TestChangePasswordSamePassword() {
+	password := faker.Password()
+	request := &dto.ChangePasswordRequest{CurrentPassword: password, NewPassword: password}
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+
+	brcyptUtil := &utils.BcryptUtilMock{}
+	brcyptUtil.On("CompareHashedPassword", t.User.Password, password).Return(nil)
+
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.ChangePassword(context.Background(), t.User.ID.String(), faker.UUIDDigit(), request, t.Meta)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+}
+
+func (t *UserServiceTest) TestVerifyEmailSuccess() {
+	controller := gomock.NewController(t.T())
+	verifyToken := faker.UUIDDigit()
+	newEmail := faker.Email()
+	updated := *t.User
+	updated.Email = newEmail
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("Update", testifyMock.Anything, t.User.ID.String(), &model.User{Email: newEmail}).Return(&updated, nil)
+
+	tokenService := tokenMock.NewMockService(controller)
+	tokenService.EXPECT().FindVerifyEmailToken(gomock.Any(), verifyToken).Return(&dto.VerifyEmailTokenCache{UserID: t.User.ID.String(), Email: newEmail}, nil)
+	tokenService.EXPECT().RemoveVerifyEmailToken(gomock.Any(), verifyToken).Return(nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, nil, nil, tokenService, nil, nil, config.Auth{})
+	actual, err := srv.VerifyEmail(context.Background(), &dto.VerifyEmailRequest{Token: verifyToken})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), user.RawToDto(&updated), actual)
+}
+
+func (t *UserServiceTest) TestVerifyEmailInvalidToken() {
+	controller := gomock.NewController(t.T())
+	verifyToken := faker.UUIDDigit()
+
+	tokenService := tokenMock.NewMockService(controller)
+	tokenService.EXPECT().FindVerifyEmailToken(gomock.Any(), verifyToken).Return(nil, status.Error(codes.InvalidArgument, "redis: nil"))
+
+	srv := user.NewService(&mock.UserRepositoryMock{}, &utils.BcryptUtilMock{}, nil, nil, tokenService, nil, nil, config.Auth{})
+	actual, err := srv.VerifyEmail(context.Background(), &dto.VerifyEmailRequest{Token: verifyToken})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusBadRequest, err.StatusCode)
+}
+
 func (t *UserServiceTest) TestDeleteSuccess() {
 	want := &dto.DeleteUserResponse{Success: true}
 
 	repo := &mock.UserRepositoryMock{}
-	repo.On("Delete", t.User.ID.String()).Return(nil)
+	repo.On("Delete", testifyMock.Anything, t.User.ID.String()).Return(nil)
 
 	brcyptUtil := &utils.BcryptUtilMock{}
-	srv := user.NewService(repo, brcyptUtil)
-	actual, err := srv.Delete(t.UserDto.Id)
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.Delete(context.Background(), t.UserDto.Id)
 
 	assert.Nil(t.T(), err)
 	assert.Equal(t.T(), want, actual)
@@ -163,12 +319,294 @@ func (t *UserServiceTest) TestDeleteSuccess() {
 
 func (t *UserServiceTest) TestDeleteInternalErr() {
 	repo := &mock.UserRepositoryMock{}
-	repo.On("Delete", t.User.ID.String()).Return(errors.New("Not found user"))
+	repo.On("Delete", testifyMock.Anything, t.User.ID.String()).Return(errors.New("Not found user"))
 
 	brcyptUtil := &utils.BcryptUtilMock{}
-	srv := user.NewService(repo, brcyptUtil)
-	actual, err := srv.Delete(t.UserDto.Id)
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.Delete(context.Background(), t.UserDto.Id)
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
+}
+
+func (t *UserServiceTest) TestFindAllSuccess() {
+	users := []*model.User{t.User}
+	want := &dto.FindAllUserResponse{
+		Users: []*dto.AdminUser{user.RawToAdminDto(t.User)},
+		Metadata: &dto.FindAllMetadata{
+			Page:       2,
+			TotalPages: 1,
+			PageSize:   20,
+			Total:      1,
+		},
+	}
+
+	var result []*model.User
+	var total int64
+	repo := &mock.UserRepositoryMock{}
+	repo.On("Search", testifyMock.Anything, "john", 20, 20, &result, &total).Return(&users, nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.FindAll(context.Background(), &dto.FindAllUserRequest{Search: "john", Page: 2})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), want, actual)
+}
+
+func (t *UserServiceTest) TestFindAllLimitPageSize() {
+	users := []*model.User{}
+
+	var result []*model.User
+	var total int64
+	repo := &mock.UserRepositoryMock{}
+	repo.On("Search", testifyMock.Anything, "", 100, 0, &result, &total).Return(&users, nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.FindAll(context.Background(), &dto.FindAllUserRequest{PageSize: 1000})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), 100, actual.Metadata.PageSize)
+	assert.Empty(t.T(), actual.Users)
+}
+
+func (t *UserServiceTest) TestFindAllInternalErr() {
+	var result []*model.User
+	var total int64
+	repo := &mock.UserRepositoryMock{}
+	repo.On("Search", testifyMock.Anything, "", 20, 0, &result, &total).Return(nil, errors.New("Connection lost"))
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.FindAll(context.Background(), &dto.FindAllUserRequest{})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
+}
+
+func (t *UserServiceTest) TestFindAdoptionsSuccess() {
+	pets := []*model.Pet{{Base: model.Base{ID: uuid.New()}, Name: faker.Name(), Owner: t.User.ID.String()}}
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+
+	var result []*model.Pet
+	petRepo := &petMock.RepositoryMock{}
+	petRepo.On("FindByOwner", testifyMock.Anything, t.User.ID.String(), &result).Return(&pets, nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, nil, petRepo, nil, nil, nil, config.Auth{})
+	actual, err := srv.FindAdoptions(context.Background(), t.User.ID.String())
+
+	assert.Nil(t.T(), err)
+	assert.Len(t.T(), actual, 1)
+	assert.Equal(t.T(), pets[0].ID.String(), actual[0].Id)
+	assert.Equal(t.T(), t.User.ID.String(), actual[0].Owner)
+}
+
+func (t *UserServiceTest) TestFindAdoptionsNotFound() {
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(nil, gorm.ErrRecordNotFound)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, nil, &petMock.RepositoryMock{}, nil, nil, nil, config.Auth{})
+	actual, err := srv.FindAdoptions(context.Background(), t.User.ID.String())
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
+}
+
+func (t *UserServiceTest) TestUpdateRoleSuccess() {
+	controller := gomock.NewController(t.T())
+	admin := *t.User
+	admin.Role = constant.ADMIN
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("UpdateRole", testifyMock.Anything, t.User.ID.String(), constant.ADMIN).Return(nil)
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(&admin, nil)
+
+	sessionService := sessionMock.NewMockService(controller)
+	sessionService.EXPECT().RevokeByUserId(gomock.Any(), t.User.ID.String(), "").Return(nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, sessionService, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.UpdateRole(context.Background(), t.User.ID.String(), &dto.UpdateUserRoleRequest{Role: constant.ADMIN})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), constant.ADMIN, actual.Role)
+}
+
+func (t *UserServiceTest) TestUpdateRoleNotFound() {
+	repo := &mock.UserRepositoryMock{}
+	repo.On("UpdateRole", testifyMock.Anything, t.User.ID.String(), constant.ADMIN).Return(gorm.ErrRecordNotFound)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.UpdateRole(context.Background(), t.User.ID.String(), &dto.UpdateUserRoleRequest{Role: constant.ADMIN})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusNotFound, err.StatusCode)
+}
+
+func (t *UserServiceTest) TestSuspendRevokesSessions() {
+	controller := gomock.NewController(t.T())
+	isSuspended := true
+	suspended := *t.User
+	suspended.IsSuspended = true
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("UpdateSuspended", testifyMock.Anything, t.User.ID.String(), true).Return(nil)
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(&suspended, nil)
+
+	sessionService := sessionMock.NewMockService(controller)
+	sessionService.EXPECT().RevokeByUserId(gomock.Any(), t.User.ID.String(), "").Return(nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, sessionService, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.Suspend(context.Background(), t.User.ID.String(), &dto.SuspendUserRequest{IsSuspended: &isSuspended})
+
+	assert.Nil(t.T(), err)
+	assert.True(t.T(), actual.IsSuspended)
+}
+
+func (t *UserServiceTest) TestUnsuspendKeepsSessions() {
+	controller := gomock.NewController(t.T())
+	isSuspended := false
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("UpdateSuspended", testifyMock.Anything, t.User.ID.String(), false).Return(nil)
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+
+	sessionService := sessionMock.NewMockService(controller)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, sessionService, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.Suspend(context.Background(), t.User.ID.String(), &dto.SuspendUserRequest{IsSuspended: &isSuspended})
+
+	assert.Nil(t.T(), err)
+	assert.False(t.T(), actual.IsSuspended)
+}
+
+func (t *UserServiceTest) TestSuspendRevokeErr() {
+	controller := gomock.NewController(t.T())
+	isSuspended := true
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("UpdateSuspended", testifyMock.Anything, t.User.ID.String(), true).Return(nil)
+
+	sessionService := sessionMock.NewMockService(controller)
+	sessionService.EXPECT().RevokeByUserId(gomock.Any(), t.User.ID.String(), "").Return(dto.InternalServerError(constant.InternalServerErrorMessage))
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, sessionService, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.Suspend(context.Background(), t.User.ID.String(), &dto.SuspendUserRequest{IsSuspended: &isSuspended})
 
 	assert.Nil(t.T(), actual)
 	assert.Equal(t.T(), http.StatusInternalServerError, err.StatusCode)
 }
+
+func (t *UserServiceTest) TestExportSuccess() {
+	controller := gomock.NewController(t.T())
+	sessions := []*dto.Session{{Id: faker.UUIDDigit(), CreatedAt: time.Now()}}
+	pets := []*model.Pet{{Base: model.Base{ID: uuid.New()}, Owner: t.User.ID.String()}}
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+
+	var result []*model.Pet
+	petRepo := &petMock.RepositoryMock{}
+	petRepo.On("FindByOwner", testifyMock.Anything, t.User.ID.String(), &result).Return(&pets, nil)
+
+	sessionService := sessionMock.NewMockService(controller)
+	sessionService.EXPECT().FindByUserId(gomock.Any(), t.User.ID.String()).Return(sessions, nil)
+
+	events := []*dto.SecurityEvent{{Id: faker.UUIDDigit(), UserId: t.User.ID.String(), Type: constant.SignInEvent, Success: true}}
+	eventService := eventMock.NewMockService(controller)
+	eventService.EXPECT().FindAllByUser(gomock.Any(), t.User.ID.String()).Return(events, nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, sessionService, petRepo, nil, nil, eventService, config.Auth{})
+	actual, err := srv.Export(context.Background(), t.User.ID.String())
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), user.RawToAdminDto(t.User), actual.Profile)
+	assert.Equal(t.T(), sessions, actual.Sessions)
+	assert.Empty(t.T(), actual.Likes)
+	assert.Len(t.T(), actual.Adoptions, 1)
+	assert.Equal(t.T(), events, actual.SecurityEvents)
+}
+
+func (t *UserServiceTest) TestDeleteAccountSuccess() {
+	controller := gomock.NewController(t.T())
+	password := faker.Password()
+	anonymized := &model.User{
+		Email:     "deleted-" + t.User.ID.String() + "@" + constant.AnonymizedEmailDomain,
+		Firstname: constant.AnonymizedName,
+		Lastname:  constant.AnonymizedName,
+	}
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+	repo.On("Anonymize", testifyMock.Anything, t.User.ID.String(), anonymized).Return(nil)
+
+	brcyptUtil := &utils.BcryptUtilMock{}
+	brcyptUtil.On("CompareHashedPassword", t.User.Password, password).Return(nil)
+
+	sessionService := sessionMock.NewMockService(controller)
+	sessionService.EXPECT().RevokeByUserId(gomock.Any(), t.User.ID.String(), "").Return(nil)
+
+	srv := user.NewService(repo, brcyptUtil, sessionService, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.DeleteAccount(context.Background(), t.User.ID.String(), faker.UUIDDigit(), &dto.DeleteAccountRequest{Password: password})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.DeleteUserResponse{Success: true}, actual)
+}
+
+func (t *UserServiceTest) TestDeleteAccountRecentSignIn() {
+	controller := gomock.NewController(t.T())
+	authSessionId := faker.UUIDDigit()
+	sessions := []*dto.Session{{Id: authSessionId, CreatedAt: time.Now().Add(-time.Minute)}}
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+	repo.On("Anonymize", testifyMock.Anything, t.User.ID.String(), testifyMock.Anything).Return(nil)
+
+	sessionService := sessionMock.NewMockService(controller)
+	sessionService.EXPECT().FindByUserId(gomock.Any(), t.User.ID.String()).Return(sessions, nil)
+	sessionService.EXPECT().RevokeByUserId(gomock.Any(), t.User.ID.String(), "").Return(nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, sessionService, nil, nil, nil, nil, config.Auth{ReauthTTL: 300})
+	actual, err := srv.DeleteAccount(context.Background(), t.User.ID.String(), authSessionId, &dto.DeleteAccountRequest{})
+
+	assert.Nil(t.T(), err)
+	assert.Equal(t.T(), &dto.DeleteUserResponse{Success: true}, actual)
+}
+
+func (t *UserServiceTest) TestDeleteAccountStaleSignIn() {
+	controller := gomock.NewController(t.T())
+	authSessionId := faker.UUIDDigit()
+	sessions := []*dto.Session{
+		{Id: authSessionId, CreatedAt: time.Now().Add(-time.Hour)},
+		{Id: faker.UUIDDigit(), CreatedAt: time.Now()},
+	}
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+
+	sessionService := sessionMock.NewMockService(controller)
+	sessionService.EXPECT().FindByUserId(gomock.Any(), t.User.ID.String()).Return(sessions, nil)
+
+	srv := user.NewService(repo, &utils.BcryptUtilMock{}, sessionService, nil, nil, nil, nil, config.Auth{ReauthTTL: 300})
+	actual, err := srv.DeleteAccount(context.Background(), t.User.ID.String(), authSessionId, &dto.DeleteAccountRequest{})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusForbidden, err.StatusCode)
+	assert.Equal(t.T(), constant.ReauthRequiredErrorMessage, err.Message)
+	repo.AssertNotCalled(t.T(), "Anonymize", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+}
+
+func (t *UserServiceTest) TestDeleteAccountIncorrectPassword() {
+	password := faker.Password()
+
+	repo := &mock.UserRepositoryMock{}
+	repo.On("FindById", testifyMock.Anything, t.User.ID.String(), &model.User{}).Return(t.User, nil)
+
+	brcyptUtil := &utils.BcryptUtilMock{}
+	brcyptUtil.On("CompareHashedPassword", t.User.Password, password).Return(errors.New("Mismatched password"))
+
+	srv := user.NewService(repo, brcyptUtil, nil, nil, nil, nil, nil, config.Auth{})
+	actual, err := srv.DeleteAccount(context.Background(), t.User.ID.String(), faker.UUIDDigit(), &dto.DeleteAccountRequest{Password: password})
+
+	assert.Nil(t.T(), actual)
+	assert.Equal(t.T(), http.StatusForbidden, err.StatusCode)
+}
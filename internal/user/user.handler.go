@@ -1,7 +1,11 @@
 package user
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/isd-sgcu/johnjud-backend/constant"
@@ -42,7 +46,7 @@ func (h *Handler) FindOne(c router.IContext) {
 		return
 	}
 
-	user, errRes := h.service.FindOne(id)
+	user, errRes := h.service.FindOne(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -51,69 +55,173 @@ func (h *Handler) FindOne(c router.IContext) {
 	c.JSON(http.StatusOK, user)
 }
 
-// Update is a function that updates user in database
+// Update is a function that updates the profile of the signed in user
 // @Summary updates user
-// @Description Returns the data of user if successfully
+// @Description Updates only the given fields. A new email is applied after it is verified through the link sent to that address.
 // @Param update body dto.UpdateUserRequest true "update user dto"
-// @Tags auth
+// @Tags user
 // @Accept json
 // @Produce json
-// @Success 201 {object} dto.User
+// @Success 200 {object} dto.UpdateUserResponse
 // @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 409 {object} dto.ResponseConflictErr "Duplicate email"
 // @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
 // @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
-// @Router /v1/users [put]
+// @Router /v1/users [patch]
 func (h *Handler) Update(c router.IContext) {
 	usrId := c.UserID()
 
 	request := &dto.UpdateUserRequest{}
+	if !h.bindAndValidate(c, request) {
+		return
+	}
+
+	user, errRes := h.service.Update(c.UserContext(), usrId, request)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ChangePassword is a function that changes the password of the signed in user
+// @Summary changes password
+// @Description Returns isSuccess, the other sessions of the user are signed out
+// @Param request body dto.ChangePasswordRequest true "change password dto"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.ChangePasswordResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 403 {object} dto.ResponseForbiddenErr "Incorrect current password"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/users/password [put]
+func (h *Handler) ChangePassword(c router.IContext) {
+	request := &dto.ChangePasswordRequest{}
+	if !h.bindAndValidate(c, request) {
+		return
+	}
 
-	err := c.Bind(request)
+	response, errRes := h.service.ChangePassword(c.UserContext(), c.UserID(), c.AuthSessionID(), request, router.RequestMeta(c))
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// VerifyEmail is a function that applies the new email of the user from the verification link
+// @Summary verifies new email
+// @Description Returns the user with the new email
+// @Param request body dto.VerifyEmailRequest true "verify email dto"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.User
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid token"
+// @Failure 409 {object} dto.ResponseConflictErr "Duplicate email"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/users/verify-email [post]
+func (h *Handler) VerifyEmail(c router.IContext) {
+	request := &dto.VerifyEmailRequest{}
+	if !h.bindAndValidate(c, request) {
+		return
+	}
+
+	user, errRes := h.service.VerifyEmail(c.UserContext(), request)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Delete is a function that deletes user in database
+// @Summary deletes user
+// @Description Returns successful status if user is successfully deleted
+// @Param id path string true "user id"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 201 {object} bool
+// @Success 201 {object} dto.DeleteUserResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/users/{id} [delete]
+func (h *Handler) Delete(c router.IContext) {
+	id, err := c.ID()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
 			StatusCode: http.StatusBadRequest,
-			Message:    constant.BindingRequestErrorMessage + err.Error(),
+			Message:    err.Error(),
 			Data:       nil,
 		})
 		return
 	}
 
-	if err := h.validate.Validate(request); err != nil {
-		var errorMessage []string
-		for _, reqErr := range err {
-			errorMessage = append(errorMessage, reqErr.Message)
-		}
+	res, errRes := h.service.Delete(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// FindAll is a function that returns users in database with pagination
+// @Summary finds all users
+// @Description Returns the users matching the search by email or name, 20 users per page by default and at most 100
+// @Param search query string false "email or name"
+// @Param page query int false "page number"
+// @Param pageSize query int false "page size"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.FindAllUserResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid query"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/users/admin [get]
+func (h *Handler) FindAll(c router.IContext) {
+	request, err := QueriesToFindAllDto(c.Queries())
+	if err != nil {
 		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
 			StatusCode: http.StatusBadRequest,
-			Message:    constant.InvalidRequestBodyMessage + strings.Join(errorMessage, ", "),
+			Message:    err.Error(),
 			Data:       nil,
 		})
 		return
 	}
 
-	user, errRes := h.service.Update(usrId, request)
+	response, errRes := h.service.FindAll(c.UserContext(), request)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, response)
 }
 
-// Delete is a function that deletes user in database
-// @Summary deletes user
-// @Description Returns successful status if user is successfully deleted
+// FindSessions is a function that returns the auth sessions of a user
+// @Summary finds sessions of user
+// @Description Returns the auth sessions of the user, newest first
 // @Param id path string true "user id"
 // @Tags user
 // @Accept json
 // @Produce json
-// @Success 201 {object} bool
-// @Success 201 {object} dto.DeleteUserResponse
+// @Success 200 {object} []dto.Session
 // @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 404 {object} dto.ResponseNotfoundErr "User not found"
 // @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
 // @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
-// @Router /v1/users/{id} [delete]
-func (h *Handler) Delete(c router.IContext) {
+// @Router /v1/users/admin/{id}/sessions [get]
+func (h *Handler) FindSessions(c router.IContext) {
 	id, err := c.ID()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
@@ -124,7 +232,167 @@ func (h *Handler) Delete(c router.IContext) {
 		return
 	}
 
-	res, errRes := h.service.Delete(id)
+	sessions, errRes := h.service.FindSessions(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// FindAdoptions is a function that returns the pets adopted by a user
+// @Summary finds adoption history of user
+// @Description Returns the pets adopted by the user
+// @Param id path string true "user id"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} []dto.PetResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 404 {object} dto.ResponseNotfoundErr "User not found"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/users/admin/{id}/adoptions [get]
+func (h *Handler) FindAdoptions(c router.IContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	pets, errRes := h.service.FindAdoptions(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, pets)
+}
+
+// UpdateRole is a function that changes the role of a user
+// @Summary updates role of user
+// @Description Returns the user if successful, the sessions of the user are revoked so the user has to sign in again
+// @Param id path string true "user id"
+// @Param request body dto.UpdateUserRoleRequest true "update role dto"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.AdminUser
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 404 {object} dto.ResponseNotfoundErr "User not found"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/users/admin/{id}/role [put]
+func (h *Handler) UpdateRole(c router.IContext) {
+	id, ok := h.bindOtherUserId(c)
+	if !ok {
+		return
+	}
+
+	request := &dto.UpdateUserRoleRequest{}
+	if !h.bindAndValidate(c, request) {
+		return
+	}
+
+	user, errRes := h.service.UpdateRole(c.UserContext(), id, request)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Suspend is a function that suspends or unsuspends a user
+// @Summary suspends user
+// @Description Returns the user if successful, suspending a user also revokes every session of the user
+// @Param id path string true "user id"
+// @Param request body dto.SuspendUserRequest true "suspend user dto"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.AdminUser
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 404 {object} dto.ResponseNotfoundErr "User not found"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/users/admin/{id}/suspend [put]
+func (h *Handler) Suspend(c router.IContext) {
+	id, ok := h.bindOtherUserId(c)
+	if !ok {
+		return
+	}
+
+	request := &dto.SuspendUserRequest{}
+	if !h.bindAndValidate(c, request) {
+		return
+	}
+
+	user, errRes := h.service.Suspend(c.UserContext(), id, request)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Export is a function that returns the data stored about the signed in user
+// @Summary exports personal data
+// @Description Returns a json file with the profile, sessions, likes and adoption records of the user
+// @Tags user
+// @Produce json
+// @Success 200 {object} dto.UserDataExport
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/users/me/export [get]
+func (h *Handler) Export(c router.IContext) {
+	usrId := c.UserID()
+
+	export, errRes := h.service.Export(c.UserContext(), usrId)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    constant.InternalServerErrorMessage,
+			Data:       nil,
+		})
+		return
+	}
+
+	c.Attachment(fmt.Sprintf("johnjud-data-%s.json", usrId), "application/json", data)
+}
+
+// DeleteAccount is a function that deletes the account of the signed in user
+// @Summary deletes own account
+// @Description Signs the user out of every session and anonymizes the account, adoption records are retained
+// @Param request body dto.DeleteAccountRequest true "delete account dto"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.DeleteUserResponse
+// @Failure 400 {object} dto.ResponseBadRequestErr "Invalid request body"
+// @Failure 403 {object} dto.ResponseForbiddenErr "Incorrect password or the user must sign in again"
+// @Failure 500 {object} dto.ResponseInternalErr "Internal service error"
+// @Failure 503 {object} dto.ResponseServiceDownErr "Service is down"
+// @Router /v1/users/me [delete]
+func (h *Handler) DeleteAccount(c router.IContext) {
+	request := &dto.DeleteAccountRequest{}
+	if !h.bindAndValidate(c, request) {
+		return
+	}
+
+	res, errRes := h.service.DeleteAccount(c.UserContext(), c.UserID(), c.AuthSessionID(), request)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -132,3 +400,78 @@ func (h *Handler) Delete(c router.IContext) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+// bindOtherUserId reads the user id from the path, admins cannot change their own account so they cannot lock themselves out
+func (h *Handler) bindOtherUserId(c router.IContext) (string, bool) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return "", false
+	}
+
+	if id == c.UserID() {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.ChangeOwnAccountErrorMessage,
+			Data:       nil,
+		})
+		return "", false
+	}
+
+	return id, true
+}
+
+func (h *Handler) bindAndValidate(c router.IContext, request interface{}) bool {
+	if err := c.Bind(request); err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.BindingRequestErrorMessage + err.Error(),
+			Data:       nil,
+		})
+		return false
+	}
+
+	if err := h.validate.Validate(c.UserContext(), request); err != nil {
+		var errorMessage []string
+		for _, reqErr := range err {
+			errorMessage = append(errorMessage, reqErr.Message)
+		}
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    constant.InvalidRequestBodyMessage + strings.Join(errorMessage, ", "),
+			Data:       nil,
+		})
+		return false
+	}
+
+	return true
+}
+
+func QueriesToFindAllDto(queries map[string]string) (*dto.FindAllUserRequest, error) {
+	request := &dto.FindAllUserRequest{}
+
+	for q, v := range queries {
+		switch q {
+		case "search":
+			request.Search = strings.TrimSpace(v)
+		case "page":
+			page, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errors.New("error parsing page")
+			}
+			request.Page = page
+		case "pageSize":
+			pageSize, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errors.New("error parsing pageSize")
+			}
+			request.PageSize = pageSize
+		}
+	}
+
+	return request, nil
+}
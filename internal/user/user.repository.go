@@ -1,17 +1,25 @@
 package user
 
 import (
+	"context"
+	"time"
+
+	"github.com/isd-sgcu/johnjud-backend/constant"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
 	"gorm.io/gorm"
 )
 
 type Repository interface {
-	FindAll(user *[]*model.User) error
-	FindById(id string, user *model.User) error
-	FindByEmail(email string, user *model.User) error
-	Create(user *model.User) error
-	Update(id string, user *model.User) error
-	Delete(id string) error
+	FindAll(ctx context.Context, user *[]*model.User) error
+	FindById(ctx context.Context, id string, user *model.User) error
+	FindByEmail(ctx context.Context, email string, user *model.User) error
+	Search(ctx context.Context, search string, limit int, offset int, result *[]*model.User, total *int64) error
+	Create(ctx context.Context, user *model.User) error
+	Update(ctx context.Context, id string, user *model.User) error
+	UpdateRole(ctx context.Context, id string, role constant.Role) error
+	UpdateSuspended(ctx context.Context, id string, isSuspended bool) error
+	Delete(ctx context.Context, id string) error
+	Anonymize(ctx context.Context, id string, anonymized *model.User) error
 }
 
 type repositoryImpl struct {
@@ -22,26 +30,116 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repositoryImpl{Db: db}
 }
 
-func (r *repositoryImpl) FindAll(user *[]*model.User) error {
-	return r.Db.Find(user).Error
+func (r *repositoryImpl) FindAll(ctx context.Context, user *[]*model.User) error {
+	return r.Db.WithContext(ctx).Find(user).Error
+}
+
+func (r *repositoryImpl) FindById(ctx context.Context, id string, user *model.User) error {
+	return r.Db.WithContext(ctx).First(user, "id = ?", id).Error
+}
+
+func (r *repositoryImpl) FindByEmail(ctx context.Context, email string, user *model.User) error {
+	return r.Db.WithContext(ctx).First(user, "email = ?", email).Error
+}
+
+// Search matches the email, first name, last name or full name case-insensitively, an empty search matches every user
+func (r *repositoryImpl) Search(ctx context.Context, search string, limit int, offset int, result *[]*model.User, total *int64) error {
+	query := r.Db.WithContext(ctx).Model(&model.User{})
+	if search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("email ILIKE ? OR firstname ILIKE ? OR lastname ILIKE ? OR CONCAT(firstname, ' ', lastname) ILIKE ?",
+			pattern, pattern, pattern, pattern)
+	}
+
+	if err := query.Count(total).Error; err != nil {
+		return err
+	}
+
+	return query.Order("created_at desc").Limit(limit).Offset(offset).Find(result).Error
+}
+
+func (r *repositoryImpl) Create(ctx context.Context, user *model.User) error {
+	return r.Db.WithContext(ctx).Create(user).Error
+}
+
+func (r *repositoryImpl) Update(ctx context.Context, id string, user *model.User) error {
+	return r.Db.WithContext(ctx).Where("id = ?", id).Updates(user).First(user, "id = ?", id).Error
 }
 
-func (r *repositoryImpl) FindById(id string, user *model.User) error {
-	return r.Db.First(user, "id = ?", id).Error
+func (r *repositoryImpl) UpdateRole(ctx context.Context, id string, role constant.Role) error {
+	return r.updateColumn(ctx, id, "role", role)
 }
 
-func (r *repositoryImpl) FindByEmail(email string, user *model.User) error {
-	return r.Db.First(user, "email = ?", email).Error
+// UpdateSuspended updates the column directly since gorm skips false when updating with a struct
+func (r *repositoryImpl) UpdateSuspended(ctx context.Context, id string, isSuspended bool) error {
+	return r.updateColumn(ctx, id, "is_suspended", isSuspended)
 }
 
-func (r *repositoryImpl) Create(user *model.User) error {
-	return r.Db.Create(user).Error
+func (r *repositoryImpl) updateColumn(ctx context.Context, id string, column string, value interface{}) error {
+	result := r.Db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update(column, value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (r *repositoryImpl) Update(id string, user *model.User) error {
-	return r.Db.Where("id = ?", id).Updates(user).First(user, "id = ?", id).Error
+func (r *repositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.Db.WithContext(ctx).Delete(&model.User{}, "id = ?", id).Error
 }
 
-func (r *repositoryImpl) Delete(id string) error {
-	return r.Db.Delete(&model.User{}, "id = ?", id).Error
+// Anonymize overwrites the personal data of the user then soft deletes the record,
+// the id is kept so the pets adopted by the user still refer to it
+func (r *repositoryImpl) Anonymize(ctx context.Context, id string, anonymized *model.User) error {
+	return r.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user := &model.User{}
+		if err := tx.Select("email").First(user, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		err := tx.Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"email":          anonymized.Email,
+			"password":       anonymized.Password,
+			"firstname":      anonymized.Firstname,
+			"lastname":       anonymized.Lastname,
+			"is_suspended":   true,
+			"totp_secret":    "",
+			"totp_enabled":   false,
+			"totp_last_step": 0,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Delete(&model.RecoveryCode{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		// the outbox keeps the address and the rendered bodies of every email sent to the user
+		if err := tx.Unscoped().Delete(&model.EmailOutbox{}, "to_address = ?", user.Email).Error; err != nil {
+			return err
+		}
+
+		// the keys act as the user so they must stop working with the account
+		err = tx.Model(&model.ApiKey{}).
+			Where("created_by = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		// the linked provider accounts hold the email too and must not sign in to the deleted user
+		if err := tx.Unscoped().Delete(&model.OAuthIdentity{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		// the ips and user agents of the security events are personal data too
+		if err := tx.Unscoped().Delete(&model.SecurityEvent{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&model.User{}, "id = ?", id).Error
+	})
 }
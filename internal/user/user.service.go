@@ -1,35 +1,78 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/isd-sgcu/johnjud-backend/config"
 	"github.com/isd-sgcu/johnjud-backend/constant"
+	emailSvc "github.com/isd-sgcu/johnjud-backend/internal/auth/email"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/securityevent"
+	"github.com/isd-sgcu/johnjud-backend/internal/auth/token"
 	"github.com/isd-sgcu/johnjud-backend/internal/dto"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/pet"
+	"github.com/isd-sgcu/johnjud-backend/internal/session"
 	"github.com/isd-sgcu/johnjud-backend/internal/utils"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 )
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type Service interface {
-	FindOne(id string) (*dto.User, *dto.ResponseErr)
-	Update(id string, request *dto.UpdateUserRequest) (*dto.User, *dto.ResponseErr)
-	Delete(id string) (*dto.DeleteUserResponse, *dto.ResponseErr)
+	FindOne(ctx context.Context, id string) (*dto.User, *dto.ResponseErr)
+	Update(ctx context.Context, id string, request *dto.UpdateUserRequest) (*dto.UpdateUserResponse, *dto.ResponseErr)
+	ChangePassword(ctx context.Context, id string, authSessionId string, request *dto.ChangePasswordRequest, meta *dto.RequestMeta) (*dto.ChangePasswordResponse, *dto.ResponseErr)
+	VerifyEmail(ctx context.Context, request *dto.VerifyEmailRequest) (*dto.User, *dto.ResponseErr)
+	Delete(ctx context.Context, id string) (*dto.DeleteUserResponse, *dto.ResponseErr)
+	FindAll(ctx context.Context, request *dto.FindAllUserRequest) (*dto.FindAllUserResponse, *dto.ResponseErr)
+	FindSessions(ctx context.Context, id string) ([]*dto.Session, *dto.ResponseErr)
+	FindAdoptions(ctx context.Context, id string) ([]*dto.PetResponse, *dto.ResponseErr)
+	UpdateRole(ctx context.Context, id string, request *dto.UpdateUserRoleRequest) (*dto.AdminUser, *dto.ResponseErr)
+	Suspend(ctx context.Context, id string, request *dto.SuspendUserRequest) (*dto.AdminUser, *dto.ResponseErr)
+	Export(ctx context.Context, id string) (*dto.UserDataExport, *dto.ResponseErr)
+	DeleteAccount(ctx context.Context, id string, authSessionId string, request *dto.DeleteAccountRequest) (*dto.DeleteUserResponse, *dto.ResponseErr)
 }
 
 type serviceImpl struct {
-	repo       Repository
-	bcryptUtil utils.IBcryptUtil
+	repo           Repository
+	bcryptUtil     utils.IBcryptUtil
+	sessionService session.Service
+	petRepo        pet.Repository
+	tokenService   token.Service
+	emailService   emailSvc.Service
+	eventService   securityevent.Service
+	config         config.Auth
 }
 
-func NewService(repo Repository, bcryptUtil utils.IBcryptUtil) Service {
-	return &serviceImpl{repo: repo, bcryptUtil: bcryptUtil}
+func NewService(repo Repository, bcryptUtil utils.IBcryptUtil, sessionService session.Service, petRepo pet.Repository, tokenService token.Service, emailService emailSvc.Service, eventService securityevent.Service, config config.Auth) Service {
+	return &serviceImpl{
+		repo:           repo,
+		bcryptUtil:     bcryptUtil,
+		sessionService: sessionService,
+		petRepo:        petRepo,
+		tokenService:   tokenService,
+		emailService:   emailService,
+		eventService:   eventService,
+		config:         config,
+	}
 }
 
-func (s *serviceImpl) FindOne(id string) (*dto.User, *dto.ResponseErr) {
+func (s *serviceImpl) FindOne(ctx context.Context, id string) (*dto.User, *dto.ResponseErr) {
 	raw := model.User{}
 
-	err := s.repo.FindById(id, &raw)
+	err := s.repo.FindById(ctx, id, &raw)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, &dto.ResponseErr{
@@ -46,41 +89,161 @@ func (s *serviceImpl) FindOne(id string) (*dto.User, *dto.ResponseErr) {
 	return RawToDto(&raw), nil
 }
 
-func (s *serviceImpl) Update(id string, request *dto.UpdateUserRequest) (*dto.User, *dto.ResponseErr) {
-	hashPassword, err := s.bcryptUtil.GenerateHashedPassword(request.Password)
-	if err != nil {
-		return nil, &dto.ResponseErr{
-			StatusCode: http.StatusInternalServerError,
-			Message:    constant.InternalServerErrorMessage,
+// Update changes only the given fields of the user. A new email is not applied until the link sent to that address is opened.
+func (s *serviceImpl) Update(ctx context.Context, id string, request *dto.UpdateUserRequest) (*dto.UpdateUserResponse, *dto.ResponseErr) {
+	raw, errRes := s.findUser(ctx, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	updateUser := &model.User{}
+	if request.Firstname != nil {
+		updateUser.Firstname = *request.Firstname
+	}
+	if request.Lastname != nil {
+		updateUser.Lastname = *request.Lastname
+	}
+	if request.Locale != nil {
+		updateUser.Locale = *request.Locale
+	}
+
+	if updateUser.Firstname != "" || updateUser.Lastname != "" || updateUser.Locale != "" {
+		err := s.repo.Update(ctx, id, updateUser)
+		if err != nil {
+			log.Error().Ctx(ctx).Err(err).
+				Str("service", "user").
+				Str("module", "update").
+				Str("id", id).
+				Msg("Error updating user")
+			return nil, &dto.ResponseErr{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Update user failed",
+			}
 		}
+		raw = updateUser
+	}
+
+	response := &dto.UpdateUserResponse{User: *RawToDto(raw)}
+	if request.Email != nil && !strings.EqualFold(*request.Email, raw.Email) {
+		if errRes := s.sendVerifyEmail(ctx, raw, *request.Email); errRes != nil {
+			return nil, errRes
+		}
+		response.PendingEmail = *request.Email
+	}
+
+	return response, nil
+}
+
+// ChangePassword requires the current password and signs the user out of every other session. Both the change and
+// a wrong current password are recorded as security events
+func (s *serviceImpl) ChangePassword(ctx context.Context, id string, authSessionId string, request *dto.ChangePasswordRequest, meta *dto.RequestMeta) (*dto.ChangePasswordResponse, *dto.ResponseErr) {
+	raw, errRes := s.findUser(ctx, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if err := s.bcryptUtil.CompareHashedPassword(raw.Password, request.CurrentPassword); err != nil {
+		s.eventService.Record(ctx, constant.ChangePasswordEvent, id, raw.Email, meta, false)
+		return nil, dto.ForbiddenError(constant.IncorrectCurrentPasswordErrorMessage)
+	}
+
+	if err := s.bcryptUtil.CompareHashedPassword(raw.Password, request.NewPassword); err == nil {
+		return nil, dto.BadRequestError(constant.IncorrectPasswordErrorMessage)
+	}
+
+	hashPassword, err := s.bcryptUtil.GenerateHashedPassword(request.NewPassword)
+	if err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
-	updateUser := &model.User{
-		Email:     request.Email,
-		Password:  hashPassword,
-		Firstname: request.Firstname,
-		Lastname:  request.Lastname,
+	err = s.repo.Update(ctx, id, &model.User{Password: hashPassword})
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "user").
+			Str("module", "change password").
+			Str("id", id).
+			Msg("Error updating password")
+		return nil, dto.InternalServerError("Change password failed")
 	}
+	s.eventService.Record(ctx, constant.ChangePasswordEvent, id, raw.Email, meta, true)
 
-	err = s.repo.Update(id, updateUser)
+	if errRes := s.sessionService.RevokeByUserId(ctx, id, authSessionId); errRes != nil {
+		return nil, errRes
+	}
+
+	return &dto.ChangePasswordResponse{IsSuccess: true}, nil
+}
+
+func (s *serviceImpl) VerifyEmail(ctx context.Context, request *dto.VerifyEmailRequest) (*dto.User, *dto.ResponseErr) {
+	tokenCache, err := s.tokenService.FindVerifyEmailToken(ctx, request.Token)
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.InvalidArgument:
+			return nil, dto.BadRequestError(constant.InvalidTokenErrorMessage)
+		default:
+			return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
+		}
+	}
+
+	updateUser := &model.User{Email: tokenCache.Email}
+	err = s.repo.Update(ctx, tokenCache.UserID, updateUser)
 	if err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			return nil, &dto.ResponseErr{
-				StatusCode: http.StatusConflict,
-				Message:    constant.DuplicateEmailErrorMessage,
-			}
+			return nil, dto.ConflictError(constant.DuplicateEmailErrorMessage)
 		}
-		return nil, &dto.ResponseErr{
-			StatusCode: http.StatusInternalServerError,
-			Message:    "Update user failed",
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.NotFoundError(constant.UserNotFoundErrorMessage)
 		}
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "user").
+			Str("module", "verify email").
+			Str("id", tokenCache.UserID).
+			Msg("Error updating email")
+		return nil, dto.InternalServerError("Update email failed")
+	}
+
+	if err := s.tokenService.RemoveVerifyEmailToken(ctx, request.Token); err != nil {
+		return nil, dto.InternalServerError(constant.InternalServerErrorMessage)
 	}
 
 	return RawToDto(updateUser), nil
 }
 
-func (s *serviceImpl) Delete(id string) (*dto.DeleteUserResponse, *dto.ResponseErr) {
-	err := s.repo.Delete(id)
+func (s *serviceImpl) sendVerifyEmail(ctx context.Context, user *model.User, email string) *dto.ResponseErr {
+	existing := &model.User{}
+	err := s.repo.FindByEmail(ctx, email, existing)
+	if err == nil {
+		return dto.ConflictError(constant.DuplicateEmailErrorMessage)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	verifyEmailToken, err := s.tokenService.CreateVerifyEmailToken(ctx, user.ID.String(), email)
+	if err != nil {
+		return dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	recipient := &emailSvc.Recipient{Name: user.Firstname, Address: email, Locale: user.Locale}
+	data := &emailSvc.LinkData{
+		Name: user.Firstname,
+		URL:  fmt.Sprintf("%s/verify-email/%s", s.config.ClientURL, verifyEmailToken),
+	}
+	if err := s.emailService.Send(ctx, constant.VerifyEmailTemplate, recipient, data); err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "user").
+			Str("module", "send verify email").
+			Str("id", user.ID.String()).
+			Msg("Error sending verify email")
+		return dto.InternalServerError(constant.InternalServerErrorMessage)
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) Delete(ctx context.Context, id string) (*dto.DeleteUserResponse, *dto.ResponseErr) {
+	err := s.repo.Delete(ctx, id)
 	if err != nil {
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusInternalServerError,
@@ -91,11 +254,252 @@ func (s *serviceImpl) Delete(id string) (*dto.DeleteUserResponse, *dto.ResponseE
 	return &dto.DeleteUserResponse{Success: true}, nil
 }
 
+func (s *serviceImpl) FindAll(ctx context.Context, request *dto.FindAllUserRequest) (*dto.FindAllUserResponse, *dto.ResponseErr) {
+	page := request.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := request.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	var users []*model.User
+	var total int64
+	err := s.repo.Search(ctx, request.Search, pageSize, (page-1)*pageSize, &users, &total)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "user").
+			Str("module", "find all").
+			Msg("Error searching users")
+		return nil, dto.InternalServerError("Find all users failed")
+	}
+
+	result := []*dto.AdminUser{}
+	for _, user := range users {
+		result = append(result, RawToAdminDto(user))
+	}
+
+	return &dto.FindAllUserResponse{
+		Users: result,
+		Metadata: &dto.FindAllMetadata{
+			Page:       page,
+			TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+			PageSize:   pageSize,
+			Total:      int(total),
+		},
+	}, nil
+}
+
+func (s *serviceImpl) FindSessions(ctx context.Context, id string) ([]*dto.Session, *dto.ResponseErr) {
+	if _, errRes := s.findUser(ctx, id); errRes != nil {
+		return nil, errRes
+	}
+
+	return s.sessionService.FindByUserId(ctx, id)
+}
+
+// FindAdoptions returns the pets adopted by the user, which are the pets owned by the user id
+func (s *serviceImpl) FindAdoptions(ctx context.Context, id string) ([]*dto.PetResponse, *dto.ResponseErr) {
+	if _, errRes := s.findUser(ctx, id); errRes != nil {
+		return nil, errRes
+	}
+
+	var pets []*model.Pet
+	err := s.petRepo.FindByOwner(ctx, id, &pets)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "user").
+			Str("module", "find adoptions").
+			Str("id", id).
+			Msg("Error finding adopted pets")
+		return nil, dto.InternalServerError("Find adoptions failed")
+	}
+
+	result := []*dto.PetResponse{}
+	for _, p := range pets {
+		result = append(result, pet.RawToDto(p, nil))
+	}
+
+	return result, nil
+}
+
+// UpdateRole changes the role and revokes the sessions of the user since the role is kept in the token cache
+func (s *serviceImpl) UpdateRole(ctx context.Context, id string, request *dto.UpdateUserRoleRequest) (*dto.AdminUser, *dto.ResponseErr) {
+	err := s.repo.UpdateRole(ctx, id, request.Role)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.NotFoundError(constant.UserNotFoundErrorMessage)
+		}
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "user").
+			Str("module", "update role").
+			Str("id", id).
+			Msg("Error updating role")
+		return nil, dto.InternalServerError("Update role failed")
+	}
+
+	if errRes := s.sessionService.RevokeByUserId(ctx, id, ""); errRes != nil {
+		return nil, errRes
+	}
+
+	raw, errRes := s.findUser(ctx, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return RawToAdminDto(raw), nil
+}
+
+// Suspend suspends or unsuspends the user, suspending also revokes every session of the user
+func (s *serviceImpl) Suspend(ctx context.Context, id string, request *dto.SuspendUserRequest) (*dto.AdminUser, *dto.ResponseErr) {
+	err := s.repo.UpdateSuspended(ctx, id, *request.IsSuspended)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.NotFoundError(constant.UserNotFoundErrorMessage)
+		}
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "user").
+			Str("module", "suspend").
+			Str("id", id).
+			Msg("Error updating suspension")
+		return nil, dto.InternalServerError("Suspend user failed")
+	}
+
+	if *request.IsSuspended {
+		if errRes := s.sessionService.RevokeByUserId(ctx, id, ""); errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	raw, errRes := s.findUser(ctx, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return RawToAdminDto(raw), nil
+}
+
+// Export collects the data stored about the user. Likes are not stored by this service so the list is always empty.
+func (s *serviceImpl) Export(ctx context.Context, id string) (*dto.UserDataExport, *dto.ResponseErr) {
+	raw, errRes := s.findUser(ctx, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	sessions, errRes := s.sessionService.FindByUserId(ctx, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	adoptions, errRes := s.FindAdoptions(ctx, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	events, errRes := s.eventService.FindAllByUser(ctx, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return &dto.UserDataExport{
+		ExportedAt:     time.Now(),
+		Profile:        RawToAdminDto(raw),
+		Sessions:       sessions,
+		Likes:          []*dto.LikeResponse{},
+		Adoptions:      adoptions,
+		SecurityEvents: events,
+	}, nil
+}
+
+// DeleteAccount signs the user out everywhere then anonymizes the account. The adopted pets keep the user id as their owner
+// since the shelter has to retain adoption records.
+// DeleteAccount checks the password when it is sent, otherwise the current session must have signed in within
+// the reauth ttl so that users of oauth and magic links, who have no password, can delete their account too
+func (s *serviceImpl) DeleteAccount(ctx context.Context, id string, authSessionId string, request *dto.DeleteAccountRequest) (*dto.DeleteUserResponse, *dto.ResponseErr) {
+	raw, errRes := s.findUser(ctx, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if request.Password != "" {
+		if err := s.bcryptUtil.CompareHashedPassword(raw.Password, request.Password); err != nil {
+			return nil, dto.ForbiddenError(constant.IncorrectCurrentPasswordErrorMessage)
+		}
+	} else if errRes := s.checkRecentSignIn(ctx, id, authSessionId); errRes != nil {
+		return nil, errRes
+	}
+
+	if errRes := s.sessionService.RevokeByUserId(ctx, id, ""); errRes != nil {
+		return nil, errRes
+	}
+
+	err := s.repo.Anonymize(ctx, id, &model.User{
+		Email:     fmt.Sprintf("deleted-%s@%s", id, constant.AnonymizedEmailDomain),
+		Firstname: constant.AnonymizedName,
+		Lastname:  constant.AnonymizedName,
+	})
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).
+			Str("service", "user").
+			Str("module", "delete account").
+			Str("id", id).
+			Msg("Error anonymizing user")
+		return nil, dto.InternalServerError("Delete account failed")
+	}
+
+	return &dto.DeleteUserResponse{Success: true}, nil
+}
+
+func (s *serviceImpl) checkRecentSignIn(ctx context.Context, id string, authSessionId string) *dto.ResponseErr {
+	sessions, errRes := s.sessionService.FindByUserId(ctx, id)
+	if errRes != nil {
+		return errRes
+	}
+
+	for _, authSession := range sessions {
+		if authSession.Id == authSessionId && time.Since(authSession.CreatedAt) <= time.Duration(s.config.ReauthTTL)*time.Second {
+			return nil
+		}
+	}
+
+	return dto.ForbiddenError(constant.ReauthRequiredErrorMessage)
+}
+
+func (s *serviceImpl) findUser(ctx context.Context, id string) (*model.User, *dto.ResponseErr) {
+	raw := &model.User{}
+	err := s.repo.FindById(ctx, id, raw)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.NotFoundError(constant.UserNotFoundErrorMessage)
+		}
+		return nil, dto.InternalServerError("Find user failed")
+	}
+
+	return raw, nil
+}
+
+func RawToAdminDto(in *model.User) *dto.AdminUser {
+	return &dto.AdminUser{
+		Id:          in.ID.String(),
+		Email:       in.Email,
+		Firstname:   in.Firstname,
+		Lastname:    in.Lastname,
+		Role:        in.Role,
+		IsSuspended: in.IsSuspended,
+		CreatedAt:   in.CreatedAt,
+	}
+}
+
 func RawToDto(in *model.User) *dto.User {
 	return &dto.User{
 		Id:        in.ID.String(),
 		Email:     in.Email,
 		Firstname: in.Firstname,
 		Lastname:  in.Lastname,
+		Locale:    in.Locale,
 	}
 }
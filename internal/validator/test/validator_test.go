@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/isd-sgcu/johnjud-backend/config"
+	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/password"
+	"github.com/isd-sgcu/johnjud-backend/internal/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ValidatorTest struct {
+	suite.Suite
+	validator validator.IDtoValidator
+}
+
+func TestValidator(t *testing.T) {
+	suite.Run(t, new(ValidatorTest))
+}
+
+func (t *ValidatorTest) SetupTest() {
+	breached, _ := password.LoadBreachedList("")
+	policy := password.NewPolicy(config.Password{MinLength: 8, MaxLength: 64, RequireDigit: true}, breached)
+	t.validator, _ = validator.NewIValidator(policy)
+}
+
+func (t *ValidatorTest) TestPasswordReasons() {
+	errs := t.validator.Validate(context.Background(), &dto.ResetPasswordRequest{Token: "token", Password: "short"})
+
+	assert.Len(t.T(), errs, 2)
+	assert.Equal(t.T(), "password must be at least 8 characters", errs[0].Message)
+	assert.Equal(t.T(), "password must contain a digit", errs[1].Message)
+	for _, err := range errs {
+		assert.Equal(t.T(), "Password", err.FailedField)
+		assert.Nil(t.T(), err.Value)
+	}
+}
+
+func (t *ValidatorTest) TestPasswordAccepted() {
+	errs := t.validator.Validate(context.Background(), &dto.ChangePasswordRequest{CurrentPassword: "old", NewPassword: "adopt a cat 2024"})
+
+	assert.Empty(t.T(), errs)
+}
+
+func (t *ValidatorTest) TestSignInIgnoresPolicy() {
+	errs := t.validator.Validate(context.Background(), &dto.SignInRequest{Email: "user@example.com", Password: "short"})
+
+	assert.Empty(t.T(), errs)
+}
+
+func (t *ValidatorTest) TestDefaultPolicyWithoutChecker() {
+	v, err := validator.NewIValidator(nil)
+	assert.Nil(t.T(), err)
+
+	errs := v.Validate(context.Background(), &dto.ResetPasswordRequest{Token: "token", Password: "short"})
+
+	assert.Len(t.T(), errs, 2)
+	assert.Equal(t.T(), "password must be at least 8 characters", errs[0].Message)
+	assert.Equal(t.T(), "password must contain a digit", errs[1].Message)
+}
+
+func (t *ValidatorTest) TestPasswordCheckedOnce() {
+	checker := &countingChecker{reasons: []string{"password is too weak"}}
+	v, err := validator.NewIValidator(checker)
+	assert.Nil(t.T(), err)
+
+	errs := v.Validate(context.Background(), &dto.ResetPasswordRequest{Token: "token", Password: "password"})
+
+	assert.Len(t.T(), errs, 1)
+	assert.Equal(t.T(), "password is too weak", errs[0].Message)
+	assert.Equal(t.T(), 1, checker.calls)
+}
+
+type countingChecker struct {
+	reasons []string
+	calls   int
+}
+
+func (c *countingChecker) Check(string) []string {
+	c.calls++
+	return c.reasons
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -9,11 +10,12 @@ import (
 	"github.com/go-playground/validator/v10"
 	en_translations "github.com/go-playground/validator/v10/translations/en"
 	"github.com/isd-sgcu/johnjud-backend/internal/dto"
+	"github.com/isd-sgcu/johnjud-backend/internal/password"
 	"github.com/rs/zerolog/log"
 )
 
 type IDtoValidator interface {
-	Validate(interface{}) []*dto.BadReqErrResponse
+	Validate(ctx context.Context, in interface{}) []*dto.BadReqErrResponse
 }
 
 type DtoValidator struct {
@@ -21,19 +23,32 @@ type DtoValidator struct {
 	trans ut.Translator
 }
 
-func (v *DtoValidator) Validate(in interface{}) []*dto.BadReqErrResponse {
-	err := v.v.Struct(in)
+// passwordReasonsKey holds the reasons of every rejected password of a Validate call, in the order of the errors
+type passwordReasonsKey struct{}
+
+func (v *DtoValidator) Validate(ctx context.Context, in interface{}) []*dto.BadReqErrResponse {
+	var reasons [][]string
+	err := v.v.StructCtx(context.WithValue(ctx, passwordReasonsKey{}, &reasons), in)
 
 	var errors []*dto.BadReqErrResponse
 	if err != nil {
 		for _, e := range err.(validator.ValidationErrors) {
+			if e.Tag() == passwordTag {
+				var fieldReasons []string
+				if len(reasons) > 0 {
+					fieldReasons, reasons = reasons[0], reasons[1:]
+				}
+				errors = append(errors, passwordErrors(ctx, e, fieldReasons)...)
+				continue
+			}
+
 			element := dto.BadReqErrResponse{
 				Message:     e.Translate(v.trans),
 				FailedField: e.StructField(),
 				Value:       e.Value(),
 			}
 
-			log.Error().
+			log.Error().Ctx(ctx).
 				Str("module", "validate").
 				Int("status", http.StatusBadRequest).
 				Interface("error", element).
@@ -45,7 +60,33 @@ func (v *DtoValidator) Validate(in interface{}) []*dto.BadReqErrResponse {
 	return errors
 }
 
-func NewValidator() (*DtoValidator, error) {
+// passwordErrors has one error for every rule the password breaks, the password itself is left out
+func passwordErrors(ctx context.Context, e validator.FieldError, reasons []string) []*dto.BadReqErrResponse {
+	var errors []*dto.BadReqErrResponse
+	if len(reasons) == 0 {
+		reasons = []string{"password is not allowed"}
+	}
+	for _, reason := range reasons {
+		errors = append(errors, &dto.BadReqErrResponse{
+			Message:     reason,
+			FailedField: e.StructField(),
+		})
+	}
+
+	log.Error().Ctx(ctx).
+		Str("module", "validate").
+		Int("status", http.StatusBadRequest).
+		Str("field", e.StructField()).
+		Int("reasons", len(errors)).
+		Msg("Password policy failed")
+
+	return errors
+}
+
+const passwordTag = "password"
+
+// NewValidator registers the password tag with the checker, or with the default policy when it is nil
+func NewValidator(checker password.Checker) (*DtoValidator, error) {
 	translator := en.New()
 	uni := ut.New(translator, translator)
 
@@ -60,12 +101,30 @@ func NewValidator() (*DtoValidator, error) {
 		return nil, err
 	}
 
+	if checker == nil {
+		checker = password.DefaultPolicy()
+	}
+	// the reasons are kept for Validate so the password is only checked once
+	err := v.RegisterValidationCtx(passwordTag, func(ctx context.Context, fl validator.FieldLevel) bool {
+		reasons := checker.Check(fl.Field().String())
+		if len(reasons) == 0 {
+			return true
+		}
+		if collected, ok := ctx.Value(passwordReasonsKey{}).(*[][]string); ok {
+			*collected = append(*collected, reasons)
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &DtoValidator{
 		v:     v,
 		trans: trans,
 	}, nil
 }
 
-func NewIValidator() (IDtoValidator, error) {
-	return NewValidator()
+func NewIValidator(checker password.Checker) (IDtoValidator, error) {
+	return NewValidator(checker)
 }
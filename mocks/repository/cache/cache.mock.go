@@ -5,7 +5,9 @@
 package mock_cache
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,43 +36,87 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // DeleteValue mocks base method.
-func (m *MockRepository) DeleteValue(key string) error {
+func (m *MockRepository) DeleteValue(ctx context.Context, key string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteValue", key)
+	ret := m.ctrl.Call(m, "DeleteValue", ctx, key)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteValue indicates an expected call of DeleteValue.
-func (mr *MockRepositoryMockRecorder) DeleteValue(key interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) DeleteValue(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteValue", reflect.TypeOf((*MockRepository)(nil).DeleteValue), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteValue", reflect.TypeOf((*MockRepository)(nil).DeleteValue), ctx, key)
+}
+
+// GetTTL mocks base method.
+func (m *MockRepository) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTTL", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTTL indicates an expected call of GetTTL.
+func (mr *MockRepositoryMockRecorder) GetTTL(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTTL", reflect.TypeOf((*MockRepository)(nil).GetTTL), ctx, key)
 }
 
 // GetValue mocks base method.
-func (m *MockRepository) GetValue(key string, value interface{}) error {
+func (m *MockRepository) GetValue(ctx context.Context, key string, value interface{}) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetValue", key, value)
+	ret := m.ctrl.Call(m, "GetValue", ctx, key, value)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // GetValue indicates an expected call of GetValue.
-func (mr *MockRepositoryMockRecorder) GetValue(key, value interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetValue(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValue", reflect.TypeOf((*MockRepository)(nil).GetValue), ctx, key, value)
+}
+
+// Increment mocks base method.
+func (m *MockRepository) Increment(ctx context.Context, key string, ttl int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, ttl)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockRepositoryMockRecorder) Increment(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockRepository)(nil).Increment), ctx, key, ttl)
+}
+
+// PopValue mocks base method.
+func (m *MockRepository) PopValue(ctx context.Context, key string, value interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PopValue", ctx, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PopValue indicates an expected call of PopValue.
+func (mr *MockRepositoryMockRecorder) PopValue(ctx, key, value interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValue", reflect.TypeOf((*MockRepository)(nil).GetValue), key, value)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PopValue", reflect.TypeOf((*MockRepository)(nil).PopValue), ctx, key, value)
 }
 
 // SetValue mocks base method.
-func (m *MockRepository) SetValue(key string, value interface{}, ttl int) error {
+func (m *MockRepository) SetValue(ctx context.Context, key string, value interface{}, ttl int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetValue", key, value, ttl)
+	ret := m.ctrl.Call(m, "SetValue", ctx, key, value, ttl)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetValue indicates an expected call of SetValue.
-func (mr *MockRepositoryMockRecorder) SetValue(key, value, ttl interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) SetValue(ctx, key, value, ttl interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValue", reflect.TypeOf((*MockRepository)(nil).SetValue), key, value, ttl)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValue", reflect.TypeOf((*MockRepository)(nil).SetValue), ctx, key, value, ttl)
 }
@@ -1,6 +1,9 @@
 package image
 
 import (
+	"context"
+	"time"
+
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,8 +12,8 @@ type ImageRepositoryMock struct {
 	mock.Mock
 }
 
-func (m *ImageRepositoryMock) FindAll(images *[]*model.Image) error {
-	args := m.Called(images)
+func (m *ImageRepositoryMock) FindAll(ctx context.Context, images *[]*model.Image) error {
+	args := m.Called(ctx, images)
 	if args.Get(0) != nil {
 		*images = *args.Get(0).(*[]*model.Image)
 		return nil
@@ -19,8 +22,8 @@ func (m *ImageRepositoryMock) FindAll(images *[]*model.Image) error {
 	return args.Error(1)
 }
 
-func (m *ImageRepositoryMock) FindOne(id string, image *model.Image) error {
-	args := m.Called(id, image)
+func (m *ImageRepositoryMock) FindOne(ctx context.Context, id string, image *model.Image) error {
+	args := m.Called(ctx, id, image)
 	if args.Get(0) != nil {
 		*image = *args.Get(0).(*model.Image)
 		return nil
@@ -29,8 +32,8 @@ func (m *ImageRepositoryMock) FindOne(id string, image *model.Image) error {
 	return args.Error(1)
 }
 
-func (m *ImageRepositoryMock) FindByPetId(id string, image *[]*model.Image) error {
-	args := m.Called(id, image)
+func (m *ImageRepositoryMock) FindByPetId(ctx context.Context, id string, image *[]*model.Image) error {
+	args := m.Called(ctx, id, image)
 	if args.Get(0) != nil {
 		*image = *args.Get(0).(*[]*model.Image)
 		return nil
@@ -39,8 +42,8 @@ func (m *ImageRepositoryMock) FindByPetId(id string, image *[]*model.Image) erro
 	return args.Error(1)
 }
 
-func (m *ImageRepositoryMock) Create(image *model.Image) error {
-	args := m.Called(image)
+func (m *ImageRepositoryMock) Create(ctx context.Context, image *model.Image) error {
+	args := m.Called(ctx, image)
 	if args.Get(0) != nil {
 		*image = *args.Get(0).(*model.Image)
 		return nil
@@ -49,8 +52,8 @@ func (m *ImageRepositoryMock) Create(image *model.Image) error {
 	return args.Error(1)
 }
 
-func (m *ImageRepositoryMock) Update(id string, image *model.Image) error {
-	args := m.Called(id, image)
+func (m *ImageRepositoryMock) Update(ctx context.Context, id string, image *model.Image) error {
+	args := m.Called(ctx, id, image)
 	if args.Get(0) != nil {
 		*image = *args.Get(0).(*model.Image)
 		return nil
@@ -59,14 +62,24 @@ func (m *ImageRepositoryMock) Update(id string, image *model.Image) error {
 	return args.Error(1)
 }
 
-func (m *ImageRepositoryMock) Delete(id string) error {
-	args := m.Called(id)
+func (m *ImageRepositoryMock) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
 
 	return args.Error(0)
 }
 
-func (m *ImageRepositoryMock) DeleteMany(ids []string) error {
-	args := m.Called(ids)
+func (m *ImageRepositoryMock) DeleteMany(ctx context.Context, ids []string) error {
+	args := m.Called(ctx, ids)
 
 	return args.Error(0)
 }
+
+func (m *ImageRepositoryMock) FindOrphans(ctx context.Context, before time.Time, images *[]*model.Image) error {
+	args := m.Called(ctx, before, images)
+	if args.Get(0) != nil {
+		*images = *args.Get(0).(*[]*model.Image)
+		return nil
+	}
+
+	return args.Error(1)
+}
@@ -1,7 +1,9 @@
 package pet
 
 import (
+	"context"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
+	"github.com/isd-sgcu/johnjud-backend/internal/pet"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -9,8 +11,8 @@ type RepositoryMock struct {
 	mock.Mock
 }
 
-func (r *RepositoryMock) FindOne(id string, result *model.Pet) error {
-	args := r.Called(id, result)
+func (r *RepositoryMock) FindOne(ctx context.Context, id string, result *model.Pet) error {
+	args := r.Called(ctx, id, result)
 
 	if args.Get(0) != nil {
 		*result = *args.Get(0).(*model.Pet)
@@ -19,8 +21,8 @@ func (r *RepositoryMock) FindOne(id string, result *model.Pet) error {
 	return args.Error(1)
 }
 
-func (r *RepositoryMock) Create(in *model.Pet) error {
-	args := r.Called(in)
+func (r *RepositoryMock) Create(ctx context.Context, in *model.Pet) error {
+	args := r.Called(ctx, in)
 
 	if args.Get(0) != nil {
 		*in = *args.Get(0).(*model.Pet)
@@ -29,8 +31,23 @@ func (r *RepositoryMock) Create(in *model.Pet) error {
 	return args.Error(1)
 }
 
-func (r *RepositoryMock) FindAll(result *[]*model.Pet, isAdmin bool) error {
-	args := r.Called(*result)
+func (r *RepositoryMock) CreateMany(ctx context.Context, in []*model.Pet) error {
+	args := r.Called(ctx, in)
+	return args.Error(0)
+}
+
+func (r *RepositoryMock) FindAll(ctx context.Context, result *[]*model.Pet, isAdmin bool) error {
+	args := r.Called(ctx, *result)
+
+	if args.Get(0) != nil {
+		*result = *args.Get(0).(*[]*model.Pet)
+	}
+
+	return args.Error(1)
+}
+
+func (r *RepositoryMock) FindByOwner(ctx context.Context, owner string, result *[]*model.Pet) error {
+	args := r.Called(ctx, owner, result)
 
 	if args.Get(0) != nil {
 		*result = *args.Get(0).(*[]*model.Pet)
@@ -39,8 +56,8 @@ func (r *RepositoryMock) FindAll(result *[]*model.Pet, isAdmin bool) error {
 	return args.Error(1)
 }
 
-func (r *RepositoryMock) Update(id string, result *model.Pet) error {
-	args := r.Called(id, result)
+func (r *RepositoryMock) Update(ctx context.Context, id string, result *model.Pet) error {
+	args := r.Called(ctx, id, result)
 
 	if args.Get(0) != nil {
 		*result = *args.Get(0).(*model.Pet)
@@ -49,7 +66,17 @@ func (r *RepositoryMock) Update(id string, result *model.Pet) error {
 	return args.Error(1)
 }
 
-func (r *RepositoryMock) Delete(id string) error {
-	args := r.Called(id)
+func (r *RepositoryMock) Delete(ctx context.Context, id string) error {
+	args := r.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (r *RepositoryMock) CountByStatus(ctx context.Context, result *[]*pet.StatusCount) error {
+	args := r.Called(ctx, result)
+
+	if args.Get(0) != nil {
+		*result = *args.Get(0).(*[]*pet.StatusCount)
+	}
+
+	return args.Error(1)
+}
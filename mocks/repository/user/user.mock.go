@@ -1,6 +1,8 @@
 package user
 
 import (
+	"context"
+	"github.com/isd-sgcu/johnjud-backend/constant"
 	"github.com/isd-sgcu/johnjud-backend/internal/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,8 +11,8 @@ type UserRepositoryMock struct {
 	mock.Mock
 }
 
-func (m *UserRepositoryMock) FindAll(user *[]*model.User) error {
-	args := m.Called(user)
+func (m *UserRepositoryMock) FindAll(ctx context.Context, user *[]*model.User) error {
+	args := m.Called(ctx, user)
 	if args.Get(0) != nil {
 		*user = *args.Get(0).(*[]*model.User)
 		return nil
@@ -19,8 +21,8 @@ func (m *UserRepositoryMock) FindAll(user *[]*model.User) error {
 	return args.Error(1)
 }
 
-func (m *UserRepositoryMock) FindById(id string, user *model.User) error {
-	args := m.Called(id, user)
+func (m *UserRepositoryMock) FindById(ctx context.Context, id string, user *model.User) error {
+	args := m.Called(ctx, id, user)
 	if args.Get(0) != nil {
 		*user = *args.Get(0).(*model.User)
 		return nil
@@ -29,8 +31,8 @@ func (m *UserRepositoryMock) FindById(id string, user *model.User) error {
 	return args.Error(1)
 }
 
-func (m *UserRepositoryMock) FindByEmail(email string, user *model.User) error {
-	args := m.Called(email, user)
+func (m *UserRepositoryMock) FindByEmail(ctx context.Context, email string, user *model.User) error {
+	args := m.Called(ctx, email, user)
 	if args.Get(0) != nil {
 		*user = *args.Get(0).(*model.User)
 		return nil
@@ -39,8 +41,19 @@ func (m *UserRepositoryMock) FindByEmail(email string, user *model.User) error {
 	return args.Error(1)
 }
 
-func (m *UserRepositoryMock) Create(user *model.User) error {
-	args := m.Called(user)
+func (m *UserRepositoryMock) Search(ctx context.Context, search string, limit int, offset int, result *[]*model.User, total *int64) error {
+	args := m.Called(ctx, search, limit, offset, result, total)
+	if args.Get(0) != nil {
+		*result = *args.Get(0).(*[]*model.User)
+		*total = int64(len(*result))
+		return nil
+	}
+
+	return args.Error(1)
+}
+
+func (m *UserRepositoryMock) Create(ctx context.Context, user *model.User) error {
+	args := m.Called(ctx, user)
 	if args.Get(0) != nil {
 		*user = *args.Get(0).(*model.User)
 		return nil
@@ -49,8 +62,8 @@ func (m *UserRepositoryMock) Create(user *model.User) error {
 	return args.Error(1)
 }
 
-func (m *UserRepositoryMock) Update(id string, user *model.User) error {
-	args := m.Called(id, user)
+func (m *UserRepositoryMock) Update(ctx context.Context, id string, user *model.User) error {
+	args := m.Called(ctx, id, user)
 	if args.Get(0) != nil {
 		*user = *args.Get(0).(*model.User)
 		return nil
@@ -59,8 +72,26 @@ func (m *UserRepositoryMock) Update(id string, user *model.User) error {
 	return args.Error(1)
 }
 
-func (m *UserRepositoryMock) Delete(id string) error {
-	args := m.Called(id)
+func (m *UserRepositoryMock) UpdateRole(ctx context.Context, id string, role constant.Role) error {
+	args := m.Called(ctx, id, role)
+
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) UpdateSuspended(ctx context.Context, id string, isSuspended bool) error {
+	args := m.Called(ctx, id, isSuspended)
+
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) Anonymize(ctx context.Context, id string, anonymized *model.User) error {
+	args := m.Called(ctx, id, anonymized)
 
 	return args.Error(0)
 }
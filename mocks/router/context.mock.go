@@ -5,6 +5,7 @@
 package mock_router
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -34,6 +35,46 @@ func (m *MockIContext) EXPECT() *MockIContextMockRecorder {
 	return m.recorder
 }
 
+// ApiKeyID mocks base method.
+func (m *MockIContext) ApiKeyID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApiKeyID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ApiKeyID indicates an expected call of ApiKeyID.
+func (mr *MockIContextMockRecorder) ApiKeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApiKeyID", reflect.TypeOf((*MockIContext)(nil).ApiKeyID))
+}
+
+// Attachment mocks base method.
+func (m *MockIContext) Attachment(arg0, arg1 string, arg2 []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Attachment", arg0, arg1, arg2)
+}
+
+// Attachment indicates an expected call of Attachment.
+func (mr *MockIContextMockRecorder) Attachment(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attachment", reflect.TypeOf((*MockIContext)(nil).Attachment), arg0, arg1, arg2)
+}
+
+// AuthSessionID mocks base method.
+func (m *MockIContext) AuthSessionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthSessionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthSessionID indicates an expected call of AuthSessionID.
+func (mr *MockIContextMockRecorder) AuthSessionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthSessionID", reflect.TypeOf((*MockIContext)(nil).AuthSessionID))
+}
+
 // Bind mocks base method.
 func (m *MockIContext) Bind(arg0 interface{}) error {
 	m.ctrl.T.Helper()
@@ -77,6 +118,20 @@ func (mr *MockIContextMockRecorder) GetFormData(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFormData", reflect.TypeOf((*MockIContext)(nil).GetFormData), arg0)
 }
 
+// Header mocks base method.
+func (m *MockIContext) Header(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockIContextMockRecorder) Header(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockIContext)(nil).Header), arg0)
+}
+
 // ID mocks base method.
 func (m *MockIContext) ID() (string, error) {
 	m.ctrl.T.Helper()
@@ -92,6 +147,20 @@ func (mr *MockIContextMockRecorder) ID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MockIContext)(nil).ID))
 }
 
+// IP mocks base method.
+func (m *MockIContext) IP() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IP")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// IP indicates an expected call of IP.
+func (mr *MockIContextMockRecorder) IP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IP", reflect.TypeOf((*MockIContext)(nil).IP))
+}
+
 // JSON mocks base method.
 func (m *MockIContext) JSON(arg0 int, arg1 interface{}) {
 	m.ctrl.T.Helper()
@@ -175,6 +244,20 @@ func (mr *MockIContextMockRecorder) Queries() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queries", reflect.TypeOf((*MockIContext)(nil).Queries))
 }
 
+// RequestID mocks base method.
+func (m *MockIContext) RequestID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// RequestID indicates an expected call of RequestID.
+func (mr *MockIContextMockRecorder) RequestID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestID", reflect.TypeOf((*MockIContext)(nil).RequestID))
+}
+
 // Role mocks base method.
 func (m *MockIContext) Role() string {
 	m.ctrl.T.Helper()
@@ -189,6 +272,18 @@ func (mr *MockIContextMockRecorder) Role() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Role", reflect.TypeOf((*MockIContext)(nil).Role))
 }
 
+// SetHeader mocks base method.
+func (m *MockIContext) SetHeader(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetHeader", arg0, arg1)
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockIContextMockRecorder) SetHeader(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockIContext)(nil).SetHeader), arg0, arg1)
+}
+
 // StoreValue mocks base method.
 func (m *MockIContext) StoreValue(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -215,6 +310,34 @@ func (mr *MockIContextMockRecorder) Token() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockIContext)(nil).Token))
 }
 
+// UserAgent mocks base method.
+func (m *MockIContext) UserAgent() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserAgent")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// UserAgent indicates an expected call of UserAgent.
+func (mr *MockIContextMockRecorder) UserAgent() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserAgent", reflect.TypeOf((*MockIContext)(nil).UserAgent))
+}
+
+// UserContext mocks base method.
+func (m *MockIContext) UserContext() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserContext")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// UserContext indicates an expected call of UserContext.
+func (mr *MockIContextMockRecorder) UserContext() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserContext", reflect.TypeOf((*MockIContext)(nil).UserContext))
+}
+
 // UserID mocks base method.
 func (m *MockIContext) UserID() string {
 	m.ctrl.T.Helper()
@@ -5,6 +5,7 @@
 package mock_auth
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,106 +36,166 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // ForgotPassword mocks base method.
-func (m *MockService) ForgotPassword(request *dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, *dto.ResponseErr) {
+func (m *MockService) ForgotPassword(ctx context.Context, request *dto.ForgotPasswordRequest, meta *dto.RequestMeta) (*dto.ForgotPasswordResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ForgotPassword", request)
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, request, meta)
 	ret0, _ := ret[0].(*dto.ForgotPasswordResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // ForgotPassword indicates an expected call of ForgotPassword.
-func (mr *MockServiceMockRecorder) ForgotPassword(request interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) ForgotPassword(ctx, request, meta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockService)(nil).ForgotPassword), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockService)(nil).ForgotPassword), ctx, request, meta)
 }
 
 // RefreshToken mocks base method.
-func (m *MockService) RefreshToken(request *dto.RefreshTokenRequest) (*dto.Credential, *dto.ResponseErr) {
+func (m *MockService) RefreshToken(ctx context.Context, request *dto.RefreshTokenRequest) (*dto.Credential, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshToken", request)
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, request)
 	ret0, _ := ret[0].(*dto.Credential)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // RefreshToken indicates an expected call of RefreshToken.
-func (mr *MockServiceMockRecorder) RefreshToken(request interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) RefreshToken(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockService)(nil).RefreshToken), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockService)(nil).RefreshToken), ctx, request)
+}
+
+// RequestMagicLink mocks base method.
+func (m *MockService) RequestMagicLink(ctx context.Context, request *dto.MagicLinkRequest, meta *dto.RequestMeta) (*dto.MagicLinkResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestMagicLink", ctx, request, meta)
+	ret0, _ := ret[0].(*dto.MagicLinkResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// RequestMagicLink indicates an expected call of RequestMagicLink.
+func (mr *MockServiceMockRecorder) RequestMagicLink(ctx, request, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestMagicLink", reflect.TypeOf((*MockService)(nil).RequestMagicLink), ctx, request, meta)
 }
 
 // ResetPassword mocks base method.
-func (m *MockService) ResetPassword(request *dto.ResetPasswordRequest) (*dto.ResetPasswordResponse, *dto.ResponseErr) {
+func (m *MockService) ResetPassword(ctx context.Context, request *dto.ResetPasswordRequest, meta *dto.RequestMeta) (*dto.ResetPasswordResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetPassword", request)
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, request, meta)
 	ret0, _ := ret[0].(*dto.ResetPasswordResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // ResetPassword indicates an expected call of ResetPassword.
-func (mr *MockServiceMockRecorder) ResetPassword(request interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) ResetPassword(ctx, request, meta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockService)(nil).ResetPassword), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockService)(nil).ResetPassword), ctx, request, meta)
 }
 
 // SignIn mocks base method.
-func (m *MockService) SignIn(request *dto.SignInRequest) (*dto.Credential, *dto.ResponseErr) {
+func (m *MockService) SignIn(ctx context.Context, request *dto.SignInRequest, meta *dto.RequestMeta) (*dto.SignInResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SignIn", request)
-	ret0, _ := ret[0].(*dto.Credential)
+	ret := m.ctrl.Call(m, "SignIn", ctx, request, meta)
+	ret0, _ := ret[0].(*dto.SignInResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // SignIn indicates an expected call of SignIn.
-func (mr *MockServiceMockRecorder) SignIn(request interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) SignIn(ctx, request, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockService)(nil).SignIn), ctx, request, meta)
+}
+
+// SignInMagicLink mocks base method.
+func (m *MockService) SignInMagicLink(ctx context.Context, request *dto.SignInMagicLinkRequest, meta *dto.RequestMeta) (*dto.SignInResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignInMagicLink", ctx, request, meta)
+	ret0, _ := ret[0].(*dto.SignInResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// SignInMagicLink indicates an expected call of SignInMagicLink.
+func (mr *MockServiceMockRecorder) SignInMagicLink(ctx, request, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInMagicLink", reflect.TypeOf((*MockService)(nil).SignInMagicLink), ctx, request, meta)
+}
+
+// SignInOAuth mocks base method.
+func (m *MockService) SignInOAuth(ctx context.Context, request *dto.OAuthCallbackRequest, meta *dto.RequestMeta) (*dto.SignInResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignInOAuth", ctx, request, meta)
+	ret0, _ := ret[0].(*dto.SignInResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// SignInOAuth indicates an expected call of SignInOAuth.
+func (mr *MockServiceMockRecorder) SignInOAuth(ctx, request, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInOAuth", reflect.TypeOf((*MockService)(nil).SignInOAuth), ctx, request, meta)
+}
+
+// SignInTwoFactor mocks base method.
+func (m *MockService) SignInTwoFactor(ctx context.Context, request *dto.SignInTwoFactorRequest, meta *dto.RequestMeta) (*dto.Credential, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignInTwoFactor", ctx, request, meta)
+	ret0, _ := ret[0].(*dto.Credential)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// SignInTwoFactor indicates an expected call of SignInTwoFactor.
+func (mr *MockServiceMockRecorder) SignInTwoFactor(ctx, request, meta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockService)(nil).SignIn), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInTwoFactor", reflect.TypeOf((*MockService)(nil).SignInTwoFactor), ctx, request, meta)
 }
 
 // SignOut mocks base method.
-func (m *MockService) SignOut(accessToken string) (*dto.SignOutResponse, *dto.ResponseErr) {
+func (m *MockService) SignOut(ctx context.Context, accessToken string, meta *dto.RequestMeta) (*dto.SignOutResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SignOut", accessToken)
+	ret := m.ctrl.Call(m, "SignOut", ctx, accessToken, meta)
 	ret0, _ := ret[0].(*dto.SignOutResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // SignOut indicates an expected call of SignOut.
-func (mr *MockServiceMockRecorder) SignOut(accessToken interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) SignOut(ctx, accessToken, meta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignOut", reflect.TypeOf((*MockService)(nil).SignOut), accessToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignOut", reflect.TypeOf((*MockService)(nil).SignOut), ctx, accessToken, meta)
 }
 
 // Signup mocks base method.
-func (m *MockService) Signup(request *dto.SignupRequest) (*dto.SignupResponse, *dto.ResponseErr) {
+func (m *MockService) Signup(ctx context.Context, request *dto.SignupRequest) (*dto.SignupResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Signup", request)
+	ret := m.ctrl.Call(m, "Signup", ctx, request)
 	ret0, _ := ret[0].(*dto.SignupResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Signup indicates an expected call of Signup.
-func (mr *MockServiceMockRecorder) Signup(request interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Signup(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signup", reflect.TypeOf((*MockService)(nil).Signup), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signup", reflect.TypeOf((*MockService)(nil).Signup), ctx, request)
 }
 
 // Validate mocks base method.
-func (m *MockService) Validate(refreshToken string) (*dto.TokenPayloadAuth, *dto.ResponseErr) {
+func (m *MockService) Validate(ctx context.Context, refreshToken string) (*dto.TokenPayloadAuth, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Validate", refreshToken)
+	ret := m.ctrl.Call(m, "Validate", ctx, refreshToken)
 	ret0, _ := ret[0].(*dto.TokenPayloadAuth)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Validate indicates an expected call of Validate.
-func (mr *MockServiceMockRecorder) Validate(refreshToken interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Validate(ctx, refreshToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockService)(nil).Validate), refreshToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockService)(nil).Validate), ctx, refreshToken)
 }
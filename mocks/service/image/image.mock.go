@@ -5,7 +5,9 @@
 package mock_image
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	dto "github.com/isd-sgcu/johnjud-backend/internal/dto"
@@ -35,91 +37,121 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // AssignPet mocks base method.
-func (m *MockService) AssignPet(request *dto.AssignPetRequest) (*dto.AssignPetResponse, *dto.ResponseErr) {
+func (m *MockService) AssignPet(ctx context.Context, request *dto.AssignPetRequest) (*dto.AssignPetResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssignPet", request)
+	ret := m.ctrl.Call(m, "AssignPet", ctx, request)
 	ret0, _ := ret[0].(*dto.AssignPetResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // AssignPet indicates an expected call of AssignPet.
-func (mr *MockServiceMockRecorder) AssignPet(request interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) AssignPet(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignPet", reflect.TypeOf((*MockService)(nil).AssignPet), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignPet", reflect.TypeOf((*MockService)(nil).AssignPet), ctx, request)
 }
 
 // Delete mocks base method.
-func (m *MockService) Delete(id string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
+func (m *MockService) Delete(ctx context.Context, id string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(*dto.DeleteImageResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockServiceMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, id)
 }
 
 // DeleteByPetId mocks base method.
-func (m *MockService) DeleteByPetId(petID string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
+func (m *MockService) DeleteByPetId(ctx context.Context, petID string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByPetId", petID)
+	ret := m.ctrl.Call(m, "DeleteByPetId", ctx, petID)
 	ret0, _ := ret[0].(*dto.DeleteImageResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // DeleteByPetId indicates an expected call of DeleteByPetId.
-func (mr *MockServiceMockRecorder) DeleteByPetId(petID interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeleteByPetId(ctx, petID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPetId", reflect.TypeOf((*MockService)(nil).DeleteByPetId), petID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPetId", reflect.TypeOf((*MockService)(nil).DeleteByPetId), ctx, petID)
+}
+
+// Download mocks base method.
+func (m *MockService) Download(ctx context.Context, objectKey string) ([]byte, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", ctx, objectKey)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockServiceMockRecorder) Download(ctx, objectKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockService)(nil).Download), ctx, objectKey)
 }
 
 // FindAll mocks base method.
-func (m *MockService) FindAll() ([]*dto.ImageResponse, *dto.ResponseErr) {
+func (m *MockService) FindAll(ctx context.Context) ([]*dto.ImageResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll")
+	ret := m.ctrl.Call(m, "FindAll", ctx)
 	ret0, _ := ret[0].([]*dto.ImageResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockServiceMockRecorder) FindAll() *gomock.Call {
+func (mr *MockServiceMockRecorder) FindAll(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockService)(nil).FindAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockService)(nil).FindAll), ctx)
 }
 
 // FindByPetId mocks base method.
-func (m *MockService) FindByPetId(petID string) ([]*dto.ImageResponse, *dto.ResponseErr) {
+func (m *MockService) FindByPetId(ctx context.Context, petID string) ([]*dto.ImageResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByPetId", petID)
+	ret := m.ctrl.Call(m, "FindByPetId", ctx, petID)
 	ret0, _ := ret[0].([]*dto.ImageResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // FindByPetId indicates an expected call of FindByPetId.
-func (mr *MockServiceMockRecorder) FindByPetId(petID interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) FindByPetId(ctx, petID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPetId", reflect.TypeOf((*MockService)(nil).FindByPetId), ctx, petID)
+}
+
+// PurgeOrphans mocks base method.
+func (m *MockService) PurgeOrphans(ctx context.Context, before time.Time) (*dto.PurgeOrphanImagesResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeOrphans", ctx, before)
+	ret0, _ := ret[0].(*dto.PurgeOrphanImagesResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// PurgeOrphans indicates an expected call of PurgeOrphans.
+func (mr *MockServiceMockRecorder) PurgeOrphans(ctx, before interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPetId", reflect.TypeOf((*MockService)(nil).FindByPetId), petID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeOrphans", reflect.TypeOf((*MockService)(nil).PurgeOrphans), ctx, before)
 }
 
 // Upload mocks base method.
-func (m *MockService) Upload(request *dto.UploadImageRequest) (*dto.ImageResponse, *dto.ResponseErr) {
+func (m *MockService) Upload(ctx context.Context, request *dto.UploadImageRequest) (*dto.ImageResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upload", request)
+	ret := m.ctrl.Call(m, "Upload", ctx, request)
 	ret0, _ := ret[0].(*dto.ImageResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Upload indicates an expected call of Upload.
-func (mr *MockServiceMockRecorder) Upload(request interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Upload(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockService)(nil).Upload), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockService)(nil).Upload), ctx, request)
 }
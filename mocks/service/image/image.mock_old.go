@@ -1,6 +1,9 @@
 package mock_image
 
 import (
+	"context"
+	"time"
+
 	dto "github.com/isd-sgcu/johnjud-backend/internal/dto"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,8 +12,8 @@ type ServiceMock struct {
 	mock.Mock
 }
 
-func (c *ServiceMock) FindAll() (res []*dto.ImageResponse, err *dto.ResponseErr) {
-	args := c.Called()
+func (c *ServiceMock) FindAll(ctx context.Context) (res []*dto.ImageResponse, err *dto.ResponseErr) {
+	args := c.Called(ctx)
 
 	if args.Get(0) != nil {
 		res = args.Get(0).([]*dto.ImageResponse)
@@ -19,8 +22,8 @@ func (c *ServiceMock) FindAll() (res []*dto.ImageResponse, err *dto.ResponseErr)
 	return res, args.Get(1).(*dto.ResponseErr)
 }
 
-func (c *ServiceMock) FindByPetId(petID string) ([]*dto.ImageResponse, *dto.ResponseErr) {
-	args := c.Called(petID)
+func (c *ServiceMock) FindByPetId(ctx context.Context, petID string) ([]*dto.ImageResponse, *dto.ResponseErr) {
+	args := c.Called(ctx, petID)
 
 	if args.Get(0) != nil {
 		res := args.Get(0).([]*dto.ImageResponse)
@@ -29,8 +32,8 @@ func (c *ServiceMock) FindByPetId(petID string) ([]*dto.ImageResponse, *dto.Resp
 	return nil, args.Get(1).(*dto.ResponseErr)
 }
 
-func (c *ServiceMock) Upload(request *dto.UploadImageRequest) (*dto.ImageResponse, *dto.ResponseErr) {
-	args := c.Called(request)
+func (c *ServiceMock) Upload(ctx context.Context, request *dto.UploadImageRequest) (*dto.ImageResponse, *dto.ResponseErr) {
+	args := c.Called(ctx, request)
 
 	if args.Get(0) != nil {
 		res := args.Get(0).(*dto.ImageResponse)
@@ -39,8 +42,8 @@ func (c *ServiceMock) Upload(request *dto.UploadImageRequest) (*dto.ImageRespons
 	return nil, args.Get(1).(*dto.ResponseErr)
 }
 
-func (c *ServiceMock) Delete(id string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
-	args := c.Called(id)
+func (c *ServiceMock) Delete(ctx context.Context, id string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
+	args := c.Called(ctx, id)
 
 	if args.Get(0) != nil {
 		res := args.Get(0).(*dto.DeleteImageResponse)
@@ -49,8 +52,8 @@ func (c *ServiceMock) Delete(id string) (*dto.DeleteImageResponse, *dto.Response
 	return nil, args.Get(1).(*dto.ResponseErr)
 }
 
-func (c *ServiceMock) DeleteByPetId(petID string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
-	args := c.Called(petID)
+func (c *ServiceMock) DeleteByPetId(ctx context.Context, petID string) (*dto.DeleteImageResponse, *dto.ResponseErr) {
+	args := c.Called(ctx, petID)
 
 	if args.Get(0) != nil {
 		res := args.Get(0).(*dto.DeleteImageResponse)
@@ -59,8 +62,8 @@ func (c *ServiceMock) DeleteByPetId(petID string) (*dto.DeleteImageResponse, *dt
 	return nil, args.Get(1).(*dto.ResponseErr)
 }
 
-func (c *ServiceMock) AssignPet(request *dto.AssignPetRequest) (*dto.AssignPetResponse, *dto.ResponseErr) {
-	args := c.Called(request)
+func (c *ServiceMock) AssignPet(ctx context.Context, request *dto.AssignPetRequest) (*dto.AssignPetResponse, *dto.ResponseErr) {
+	args := c.Called(ctx, request)
 
 	if args.Get(0) != nil {
 		res := args.Get(0).(*dto.AssignPetResponse)
@@ -68,3 +71,23 @@ func (c *ServiceMock) AssignPet(request *dto.AssignPetRequest) (*dto.AssignPetRe
 	}
 	return nil, args.Get(1).(*dto.ResponseErr)
 }
+
+func (c *ServiceMock) Download(ctx context.Context, objectKey string) ([]byte, *dto.ResponseErr) {
+	args := c.Called(ctx, objectKey)
+
+	if args.Get(0) != nil {
+		res := args.Get(0).([]byte)
+		return res, nil
+	}
+	return nil, args.Get(1).(*dto.ResponseErr)
+}
+
+func (c *ServiceMock) PurgeOrphans(ctx context.Context, before time.Time) (*dto.PurgeOrphanImagesResponse, *dto.ResponseErr) {
+	args := c.Called(ctx, before)
+
+	if args.Get(0) != nil {
+		res := args.Get(0).(*dto.PurgeOrphanImagesResponse)
+		return res, nil
+	}
+	return nil, args.Get(1).(*dto.ResponseErr)
+}
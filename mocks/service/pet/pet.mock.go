@@ -5,10 +5,12 @@
 package mock_pet
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	dto "github.com/isd-sgcu/johnjud-backend/internal/dto"
+	model "github.com/isd-sgcu/johnjud-backend/internal/model"
 )
 
 // MockService is a mock of Service interface.
@@ -35,106 +37,173 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // Adopt mocks base method.
-func (m *MockService) Adopt(id string, req *dto.AdoptByRequest) (*dto.AdoptByResponse, *dto.ResponseErr) {
+func (m *MockService) Adopt(ctx context.Context, id string, req *dto.AdoptByRequest) (*dto.AdoptByResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Adopt", id, req)
+	ret := m.ctrl.Call(m, "Adopt", ctx, id, req)
 	ret0, _ := ret[0].(*dto.AdoptByResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Adopt indicates an expected call of Adopt.
-func (mr *MockServiceMockRecorder) Adopt(id, req interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Adopt(ctx, id, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adopt", reflect.TypeOf((*MockService)(nil).Adopt), id, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adopt", reflect.TypeOf((*MockService)(nil).Adopt), ctx, id, req)
 }
 
 // ChangeView mocks base method.
-func (m *MockService) ChangeView(id string, req *dto.ChangeViewPetRequest) (*dto.ChangeViewPetResponse, *dto.ResponseErr) {
+func (m *MockService) ChangeView(ctx context.Context, id string, req *dto.ChangeViewPetRequest) (*dto.ChangeViewPetResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ChangeView", id, req)
+	ret := m.ctrl.Call(m, "ChangeView", ctx, id, req)
 	ret0, _ := ret[0].(*dto.ChangeViewPetResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // ChangeView indicates an expected call of ChangeView.
-func (mr *MockServiceMockRecorder) ChangeView(id, req interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) ChangeView(ctx, id, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeView", reflect.TypeOf((*MockService)(nil).ChangeView), id, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeView", reflect.TypeOf((*MockService)(nil).ChangeView), ctx, id, req)
 }
 
 // Create mocks base method.
-func (m *MockService) Create(req *dto.CreatePetRequest) (*dto.PetResponse, *dto.ResponseErr) {
+func (m *MockService) Create(ctx context.Context, req *dto.CreatePetRequest) (*dto.PetResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", req)
+	ret := m.ctrl.Call(m, "Create", ctx, req)
 	ret0, _ := ret[0].(*dto.PetResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockServiceMockRecorder) Create(req interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Create(ctx, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), ctx, req)
 }
 
 // Delete mocks base method.
-func (m *MockService) Delete(id string) (*dto.DeleteResponse, *dto.ResponseErr) {
+func (m *MockService) Delete(ctx context.Context, id string) (*dto.DeleteResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(*dto.DeleteResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockServiceMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, id)
+}
+
+// Export mocks base method.
+func (m *MockService) Export(ctx context.Context, req *dto.ExportPetRequest) (*dto.ExportPetResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, req)
+	ret0, _ := ret[0].(*dto.ExportPetResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockServiceMockRecorder) Export(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockService)(nil).Export), ctx, req)
 }
 
 // FindAll mocks base method.
-func (m *MockService) FindAll(req *dto.FindAllPetRequest, isAdmin bool) (*dto.FindAllPetResponse, *dto.ResponseErr) {
+func (m *MockService) FindAll(ctx context.Context, req *dto.FindAllPetRequest, isAdmin bool) (*dto.FindAllPetResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll", req, isAdmin)
+	ret := m.ctrl.Call(m, "FindAll", ctx, req, isAdmin)
 	ret0, _ := ret[0].(*dto.FindAllPetResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockServiceMockRecorder) FindAll(req, isAdmin interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) FindAll(ctx, req, isAdmin interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockService)(nil).FindAll), req, isAdmin)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockService)(nil).FindAll), ctx, req, isAdmin)
 }
 
 // FindOne mocks base method.
-func (m *MockService) FindOne(id string) (*dto.PetResponse, *dto.ResponseErr) {
+func (m *MockService) FindOne(ctx context.Context, id string) (*dto.PetResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindOne", id)
+	ret := m.ctrl.Call(m, "FindOne", ctx, id)
 	ret0, _ := ret[0].(*dto.PetResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // FindOne indicates an expected call of FindOne.
-func (mr *MockServiceMockRecorder) FindOne(id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) FindOne(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockService)(nil).FindOne), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockService)(nil).FindOne), ctx, id)
+}
+
+// Import mocks base method.
+func (m *MockService) Import(ctx context.Context, req *dto.ImportPetRequest) (*dto.ImportPetResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, req)
+	ret0, _ := ret[0].(*dto.ImportPetResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockServiceMockRecorder) Import(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockService)(nil).Import), ctx, req)
 }
 
 // Update mocks base method.
-func (m *MockService) Update(id string, req *dto.UpdatePetRequest) (*dto.PetResponse, *dto.ResponseErr) {
+func (m *MockService) Update(ctx context.Context, id string, req *dto.UpdatePetRequest) (*dto.PetResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, req)
+	ret := m.ctrl.Call(m, "Update", ctx, id, req)
 	ret0, _ := ret[0].(*dto.PetResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockServiceMockRecorder) Update(id, req interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, req)
+}
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindById mocks base method.
+func (m *MockUserRepository) FindById(ctx context.Context, id string, user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockUserRepositoryMockRecorder) FindById(ctx, id, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), id, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockUserRepository)(nil).FindById), ctx, id, user)
 }
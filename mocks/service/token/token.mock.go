@@ -5,12 +5,12 @@
 package mock_token
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	constant "github.com/isd-sgcu/johnjud-backend/constant"
 	dto "github.com/isd-sgcu/johnjud-backend/internal/dto"
-	v1 "github.com/isd-sgcu/johnjud-go-proto/johnjud/auth/auth/v1"
 )
 
 // MockService is a mock of Service interface.
@@ -36,19 +36,49 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// ConsumeMagicLinkToken mocks base method.
+func (m *MockService) ConsumeMagicLinkToken(ctx context.Context, token string) (*dto.MagicLinkTokenCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeMagicLinkToken", ctx, token)
+	ret0, _ := ret[0].(*dto.MagicLinkTokenCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeMagicLinkToken indicates an expected call of ConsumeMagicLinkToken.
+func (mr *MockServiceMockRecorder) ConsumeMagicLinkToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeMagicLinkToken", reflect.TypeOf((*MockService)(nil).ConsumeMagicLinkToken), ctx, token)
+}
+
 // CreateCredential mocks base method.
-func (m *MockService) CreateCredential(userId string, role constant.Role, authSessionId string) (*v1.Credential, error) {
+func (m *MockService) CreateCredential(ctx context.Context, userId string, role constant.Role, authSessionId string) (*dto.Credential, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCredential", userId, role, authSessionId)
-	ret0, _ := ret[0].(*v1.Credential)
+	ret := m.ctrl.Call(m, "CreateCredential", ctx, userId, role, authSessionId)
+	ret0, _ := ret[0].(*dto.Credential)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateCredential indicates an expected call of CreateCredential.
-func (mr *MockServiceMockRecorder) CreateCredential(userId, role, authSessionId interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) CreateCredential(ctx, userId, role, authSessionId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCredential", reflect.TypeOf((*MockService)(nil).CreateCredential), userId, role, authSessionId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCredential", reflect.TypeOf((*MockService)(nil).CreateCredential), ctx, userId, role, authSessionId)
+}
+
+// CreateMagicLinkToken mocks base method.
+func (m *MockService) CreateMagicLinkToken(ctx context.Context, userId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMagicLinkToken", ctx, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMagicLinkToken indicates an expected call of CreateMagicLinkToken.
+func (mr *MockServiceMockRecorder) CreateMagicLinkToken(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMagicLinkToken", reflect.TypeOf((*MockService)(nil).CreateMagicLinkToken), ctx, userId)
 }
 
 // CreateRefreshToken mocks base method.
@@ -66,103 +96,205 @@ func (mr *MockServiceMockRecorder) CreateRefreshToken() *gomock.Call {
 }
 
 // CreateResetPasswordToken mocks base method.
-func (m *MockService) CreateResetPasswordToken(userId string) (string, error) {
+func (m *MockService) CreateResetPasswordToken(ctx context.Context, userId string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateResetPasswordToken", userId)
+	ret := m.ctrl.Call(m, "CreateResetPasswordToken", ctx, userId)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateResetPasswordToken indicates an expected call of CreateResetPasswordToken.
-func (mr *MockServiceMockRecorder) CreateResetPasswordToken(userId interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) CreateResetPasswordToken(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResetPasswordToken", reflect.TypeOf((*MockService)(nil).CreateResetPasswordToken), ctx, userId)
+}
+
+// CreateTwoFactorChallenge mocks base method.
+func (m *MockService) CreateTwoFactorChallenge(ctx context.Context, userId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTwoFactorChallenge", ctx, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTwoFactorChallenge indicates an expected call of CreateTwoFactorChallenge.
+func (mr *MockServiceMockRecorder) CreateTwoFactorChallenge(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResetPasswordToken", reflect.TypeOf((*MockService)(nil).CreateResetPasswordToken), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTwoFactorChallenge", reflect.TypeOf((*MockService)(nil).CreateTwoFactorChallenge), ctx, userId)
+}
+
+// CreateVerifyEmailToken mocks base method.
+func (m *MockService) CreateVerifyEmailToken(ctx context.Context, userId, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifyEmailToken", ctx, userId, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVerifyEmailToken indicates an expected call of CreateVerifyEmailToken.
+func (mr *MockServiceMockRecorder) CreateVerifyEmailToken(ctx, userId, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyEmailToken", reflect.TypeOf((*MockService)(nil).CreateVerifyEmailToken), ctx, userId, email)
 }
 
 // FindRefreshTokenCache mocks base method.
-func (m *MockService) FindRefreshTokenCache(refreshToken string) (*dto.RefreshTokenCache, error) {
+func (m *MockService) FindRefreshTokenCache(ctx context.Context, refreshToken string) (*dto.RefreshTokenCache, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindRefreshTokenCache", refreshToken)
+	ret := m.ctrl.Call(m, "FindRefreshTokenCache", ctx, refreshToken)
 	ret0, _ := ret[0].(*dto.RefreshTokenCache)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindRefreshTokenCache indicates an expected call of FindRefreshTokenCache.
-func (mr *MockServiceMockRecorder) FindRefreshTokenCache(refreshToken interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) FindRefreshTokenCache(ctx, refreshToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRefreshTokenCache", reflect.TypeOf((*MockService)(nil).FindRefreshTokenCache), refreshToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRefreshTokenCache", reflect.TypeOf((*MockService)(nil).FindRefreshTokenCache), ctx, refreshToken)
 }
 
 // FindResetPasswordToken mocks base method.
-func (m *MockService) FindResetPasswordToken(token string) (*dto.ResetPasswordTokenCache, error) {
+func (m *MockService) FindResetPasswordToken(ctx context.Context, token string) (*dto.ResetPasswordTokenCache, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindResetPasswordToken", token)
+	ret := m.ctrl.Call(m, "FindResetPasswordToken", ctx, token)
 	ret0, _ := ret[0].(*dto.ResetPasswordTokenCache)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindResetPasswordToken indicates an expected call of FindResetPasswordToken.
-func (mr *MockServiceMockRecorder) FindResetPasswordToken(token interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) FindResetPasswordToken(ctx, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindResetPasswordToken", reflect.TypeOf((*MockService)(nil).FindResetPasswordToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindResetPasswordToken", reflect.TypeOf((*MockService)(nil).FindResetPasswordToken), ctx, token)
+}
+
+// FindTwoFactorChallenge mocks base method.
+func (m *MockService) FindTwoFactorChallenge(ctx context.Context, token string) (*dto.TwoFactorChallengeCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTwoFactorChallenge", ctx, token)
+	ret0, _ := ret[0].(*dto.TwoFactorChallengeCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTwoFactorChallenge indicates an expected call of FindTwoFactorChallenge.
+func (mr *MockServiceMockRecorder) FindTwoFactorChallenge(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTwoFactorChallenge", reflect.TypeOf((*MockService)(nil).FindTwoFactorChallenge), ctx, token)
+}
+
+// FindVerifyEmailToken mocks base method.
+func (m *MockService) FindVerifyEmailToken(ctx context.Context, token string) (*dto.VerifyEmailTokenCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVerifyEmailToken", ctx, token)
+	ret0, _ := ret[0].(*dto.VerifyEmailTokenCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindVerifyEmailToken indicates an expected call of FindVerifyEmailToken.
+func (mr *MockServiceMockRecorder) FindVerifyEmailToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVerifyEmailToken", reflect.TypeOf((*MockService)(nil).FindVerifyEmailToken), ctx, token)
 }
 
 // RemoveAccessTokenCache mocks base method.
-func (m *MockService) RemoveAccessTokenCache(authSessionId string) error {
+func (m *MockService) RemoveAccessTokenCache(ctx context.Context, authSessionId string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveAccessTokenCache", authSessionId)
+	ret := m.ctrl.Call(m, "RemoveAccessTokenCache", ctx, authSessionId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveAccessTokenCache indicates an expected call of RemoveAccessTokenCache.
-func (mr *MockServiceMockRecorder) RemoveAccessTokenCache(authSessionId interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) RemoveAccessTokenCache(ctx, authSessionId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccessTokenCache", reflect.TypeOf((*MockService)(nil).RemoveAccessTokenCache), authSessionId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccessTokenCache", reflect.TypeOf((*MockService)(nil).RemoveAccessTokenCache), ctx, authSessionId)
+}
+
+// RemoveCredential mocks base method.
+func (m *MockService) RemoveCredential(ctx context.Context, authSessionId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCredential", ctx, authSessionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCredential indicates an expected call of RemoveCredential.
+func (mr *MockServiceMockRecorder) RemoveCredential(ctx, authSessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCredential", reflect.TypeOf((*MockService)(nil).RemoveCredential), ctx, authSessionId)
 }
 
 // RemoveRefreshTokenCache mocks base method.
-func (m *MockService) RemoveRefreshTokenCache(refreshToken string) error {
+func (m *MockService) RemoveRefreshTokenCache(ctx context.Context, refreshToken string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveRefreshTokenCache", refreshToken)
+	ret := m.ctrl.Call(m, "RemoveRefreshTokenCache", ctx, refreshToken)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveRefreshTokenCache indicates an expected call of RemoveRefreshTokenCache.
-func (mr *MockServiceMockRecorder) RemoveRefreshTokenCache(refreshToken interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) RemoveRefreshTokenCache(ctx, refreshToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRefreshTokenCache", reflect.TypeOf((*MockService)(nil).RemoveRefreshTokenCache), refreshToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRefreshTokenCache", reflect.TypeOf((*MockService)(nil).RemoveRefreshTokenCache), ctx, refreshToken)
 }
 
 // RemoveResetPasswordToken mocks base method.
-func (m *MockService) RemoveResetPasswordToken(token string) error {
+func (m *MockService) RemoveResetPasswordToken(ctx context.Context, token string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveResetPasswordToken", token)
+	ret := m.ctrl.Call(m, "RemoveResetPasswordToken", ctx, token)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveResetPasswordToken indicates an expected call of RemoveResetPasswordToken.
-func (mr *MockServiceMockRecorder) RemoveResetPasswordToken(token interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) RemoveResetPasswordToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveResetPasswordToken", reflect.TypeOf((*MockService)(nil).RemoveResetPasswordToken), ctx, token)
+}
+
+// RemoveTwoFactorChallenge mocks base method.
+func (m *MockService) RemoveTwoFactorChallenge(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTwoFactorChallenge", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTwoFactorChallenge indicates an expected call of RemoveTwoFactorChallenge.
+func (mr *MockServiceMockRecorder) RemoveTwoFactorChallenge(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTwoFactorChallenge", reflect.TypeOf((*MockService)(nil).RemoveTwoFactorChallenge), ctx, token)
+}
+
+// RemoveVerifyEmailToken mocks base method.
+func (m *MockService) RemoveVerifyEmailToken(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveVerifyEmailToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveVerifyEmailToken indicates an expected call of RemoveVerifyEmailToken.
+func (mr *MockServiceMockRecorder) RemoveVerifyEmailToken(ctx, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveResetPasswordToken", reflect.TypeOf((*MockService)(nil).RemoveResetPasswordToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveVerifyEmailToken", reflect.TypeOf((*MockService)(nil).RemoveVerifyEmailToken), ctx, token)
 }
 
 // Validate mocks base method.
-func (m *MockService) Validate(token string) (*dto.UserCredential, error) {
+func (m *MockService) Validate(ctx context.Context, token string) (*dto.UserCredential, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Validate", token)
+	ret := m.ctrl.Call(m, "Validate", ctx, token)
 	ret0, _ := ret[0].(*dto.UserCredential)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Validate indicates an expected call of Validate.
-func (mr *MockServiceMockRecorder) Validate(token interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Validate(ctx, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockService)(nil).Validate), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockService)(nil).Validate), ctx, token)
 }
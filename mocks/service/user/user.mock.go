@@ -5,6 +5,7 @@
 package mock_user
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -34,47 +35,182 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockService) ChangePassword(ctx context.Context, id, authSessionId string, request *dto.ChangePasswordRequest, meta *dto.RequestMeta) (*dto.ChangePasswordResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, id, authSessionId, request, meta)
+	ret0, _ := ret[0].(*dto.ChangePasswordResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockServiceMockRecorder) ChangePassword(ctx, id, authSessionId, request, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockService)(nil).ChangePassword), ctx, id, authSessionId, request, meta)
+}
+
 // Delete mocks base method.
-func (m *MockService) Delete(id string) (*dto.DeleteUserResponse, *dto.ResponseErr) {
+func (m *MockService) Delete(ctx context.Context, id string) (*dto.DeleteUserResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(*dto.DeleteUserResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockServiceMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, id)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockService) DeleteAccount(ctx context.Context, id, authSessionId string, request *dto.DeleteAccountRequest) (*dto.DeleteUserResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, id, authSessionId, request)
+	ret0, _ := ret[0].(*dto.DeleteUserResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockServiceMockRecorder) DeleteAccount(ctx, id, authSessionId, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockService)(nil).DeleteAccount), ctx, id, authSessionId, request)
+}
+
+// Export mocks base method.
+func (m *MockService) Export(ctx context.Context, id string) (*dto.UserDataExport, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, id)
+	ret0, _ := ret[0].(*dto.UserDataExport)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockServiceMockRecorder) Export(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockService)(nil).Export), ctx, id)
+}
+
+// FindAdoptions mocks base method.
+func (m *MockService) FindAdoptions(ctx context.Context, id string) ([]*dto.PetResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAdoptions", ctx, id)
+	ret0, _ := ret[0].([]*dto.PetResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// FindAdoptions indicates an expected call of FindAdoptions.
+func (mr *MockServiceMockRecorder) FindAdoptions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAdoptions", reflect.TypeOf((*MockService)(nil).FindAdoptions), ctx, id)
+}
+
+// FindAll mocks base method.
+func (m *MockService) FindAll(ctx context.Context, request *dto.FindAllUserRequest) (*dto.FindAllUserResponse, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, request)
+	ret0, _ := ret[0].(*dto.FindAllUserResponse)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockServiceMockRecorder) FindAll(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockService)(nil).FindAll), ctx, request)
 }
 
 // FindOne mocks base method.
-func (m *MockService) FindOne(id string) (*dto.User, *dto.ResponseErr) {
+func (m *MockService) FindOne(ctx context.Context, id string) (*dto.User, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindOne", id)
+	ret := m.ctrl.Call(m, "FindOne", ctx, id)
 	ret0, _ := ret[0].(*dto.User)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // FindOne indicates an expected call of FindOne.
-func (mr *MockServiceMockRecorder) FindOne(id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) FindOne(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockService)(nil).FindOne), ctx, id)
+}
+
+// FindSessions mocks base method.
+func (m *MockService) FindSessions(ctx context.Context, id string) ([]*dto.Session, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSessions", ctx, id)
+	ret0, _ := ret[0].([]*dto.Session)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// FindSessions indicates an expected call of FindSessions.
+func (mr *MockServiceMockRecorder) FindSessions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSessions", reflect.TypeOf((*MockService)(nil).FindSessions), ctx, id)
+}
+
+// Suspend mocks base method.
+func (m *MockService) Suspend(ctx context.Context, id string, request *dto.SuspendUserRequest) (*dto.AdminUser, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suspend", ctx, id, request)
+	ret0, _ := ret[0].(*dto.AdminUser)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// Suspend indicates an expected call of Suspend.
+func (mr *MockServiceMockRecorder) Suspend(ctx, id, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockService)(nil).FindOne), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suspend", reflect.TypeOf((*MockService)(nil).Suspend), ctx, id, request)
 }
 
 // Update mocks base method.
-func (m *MockService) Update(id string, request *dto.UpdateUserRequest) (*dto.User, *dto.ResponseErr) {
+func (m *MockService) Update(ctx context.Context, id string, request *dto.UpdateUserRequest) (*dto.UpdateUserResponse, *dto.ResponseErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, request)
-	ret0, _ := ret[0].(*dto.User)
+	ret := m.ctrl.Call(m, "Update", ctx, id, request)
+	ret0, _ := ret[0].(*dto.UpdateUserResponse)
 	ret1, _ := ret[1].(*dto.ResponseErr)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockServiceMockRecorder) Update(id, request interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(ctx, id, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, request)
+}
+
+// UpdateRole mocks base method.
+func (m *MockService) UpdateRole(ctx context.Context, id string, request *dto.UpdateUserRoleRequest) (*dto.AdminUser, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, id, request)
+	ret0, _ := ret[0].(*dto.AdminUser)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockServiceMockRecorder) UpdateRole(ctx, id, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockService)(nil).UpdateRole), ctx, id, request)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockService) VerifyEmail(ctx context.Context, request *dto.VerifyEmailRequest) (*dto.User, *dto.ResponseErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, request)
+	ret0, _ := ret[0].(*dto.User)
+	ret1, _ := ret[1].(*dto.ResponseErr)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockServiceMockRecorder) VerifyEmail(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), id, request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockService)(nil).VerifyEmail), ctx, request)
 }
@@ -5,6 +5,7 @@
 package mock_validator
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,15 +36,15 @@ func (m *MockIDtoValidator) EXPECT() *MockIDtoValidatorMockRecorder {
 }
 
 // Validate mocks base method.
-func (m *MockIDtoValidator) Validate(arg0 interface{}) []*dto.BadReqErrResponse {
+func (m *MockIDtoValidator) Validate(ctx context.Context, in interface{}) []*dto.BadReqErrResponse {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Validate", arg0)
+	ret := m.ctrl.Call(m, "Validate", ctx, in)
 	ret0, _ := ret[0].([]*dto.BadReqErrResponse)
 	return ret0
 }
 
 // Validate indicates an expected call of Validate.
-func (mr *MockIDtoValidatorMockRecorder) Validate(arg0 interface{}) *gomock.Call {
+func (mr *MockIDtoValidatorMockRecorder) Validate(ctx, in interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockIDtoValidator)(nil).Validate), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockIDtoValidator)(nil).Validate), ctx, in)
 }